@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/skip-mev/platform-take-home/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ConsistencyTokenHeader carries the token item writes return, and that
+	// reads send back to observe those writes.
+	ConsistencyTokenHeader = "X-Consistency-Token"
+	// ReadConsistencyHeader set to "primary" makes a read skip the replicas
+	// and the item cache.
+	ReadConsistencyHeader = "X-Read-Consistency"
+
+	readFromPrimary = "primary"
+)
+
+var consistencyToken = strings.ToLower(ConsistencyTokenHeader)
+
+// readConsistency applies the consistency headers of the incoming request to
+// ctx and collects the token of the writes made on it.
+func readConsistency(ctx context.Context) (context.Context, func() string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	switch values := md.Get(ReadConsistencyHeader); {
+	case len(values) == 0:
+	case values[0] == readFromPrimary:
+		ctx = store.WithReadFromPrimary(ctx)
+	default:
+		return ctx, nil, status.Errorf(codes.InvalidArgument, "invalid %s %q", ReadConsistencyHeader, values[0])
+	}
+
+	if values := md.Get(ConsistencyTokenHeader); len(values) > 0 {
+		var err error
+		if ctx, err = store.WithConsistencyToken(ctx, values[0]); err != nil {
+			return ctx, nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		token string
+	)
+
+	ctx = store.WithConsistencyTokenSink(ctx, func(t string) {
+		mu.Lock()
+		defer mu.Unlock()
+		// writes commit in order, so the last token covers all of them
+		token = t
+	})
+
+	return ctx, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return token
+	}, nil
+}
+
+// consistencyUnaryInterceptor returns the consistency token of a write in the
+// response headers.
+func consistencyUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, token, err := readConsistency(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+
+	if t := token(); t != "" {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(consistencyToken, t))
+	}
+
+	return resp, err
+}
+
+// consistencyStreamInterceptor returns the consistency token of the writes on
+// a stream in its trailers, as the headers may be gone by the time they
+// commit.
+func consistencyStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, token, err := readConsistency(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	err = handler(srv, &consistentStream{ServerStream: ss, ctx: ctx})
+
+	if t := token(); t != "" {
+		ss.SetTrailer(metadata.Pairs(consistencyToken, t))
+	}
+
+	return err
+}
+
+type consistentStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *consistentStream) Context() context.Context {
+	return s.ctx
+}
+
+// forwardHeaders passes the namespace and consistency headers on to the gRPC
+// server, along with the headers the gateway forwards by default.
+func forwardHeaders(key string) (string, bool) {
+	switch textproto.CanonicalMIMEHeaderKey(key) {
+	case ConsistencyTokenHeader, ReadConsistencyHeader:
+		return strings.ToLower(key), true
+	}
+
+	return forwardNamespaceHeader(key)
+}
+
+// returnConsistencyToken returns the consistency token as a plain response
+// header rather than a Grpc-Metadata- one, so HTTP clients can echo it back
+// as is.
+func returnConsistencyToken(key string) (string, bool) {
+	if key == consistencyToken {
+		return ConsistencyTokenHeader, true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
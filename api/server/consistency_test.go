@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestReadConsistency(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		md   metadata.MD
+		want codes.Code
+	}{
+		{name: "no headers", md: metadata.MD{}, want: codes.OK},
+		{name: "primary", md: metadata.Pairs(ReadConsistencyHeader, "primary"), want: codes.OK},
+		{name: "unknown consistency", md: metadata.Pairs(ReadConsistencyHeader, "eventual"), want: codes.InvalidArgument},
+		{name: "empty consistency", md: metadata.Pairs(ReadConsistencyHeader, ""), want: codes.InvalidArgument},
+		{name: "token", md: metadata.Pairs(ConsistencyTokenHeader, "16/B374D848"), want: codes.OK},
+		{name: "invalid token", md: metadata.Pairs(ConsistencyTokenHeader, "16"), want: codes.InvalidArgument},
+		{name: "both", md: metadata.Pairs(ReadConsistencyHeader, "primary", ConsistencyTokenHeader, "0/0"), want: codes.OK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+
+			_, token, err := readConsistency(ctx)
+			if code := status.Code(err); code != tc.want {
+				t.Fatalf("readConsistency() = %v, want code %v", err, tc.want)
+			}
+			if err == nil && token() != "" {
+				t.Errorf("got token %q before any write", token())
+			}
+		})
+	}
+}
+
+func TestConsistencyUnaryInterceptor(t *testing.T) {
+	handled := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = true
+		return req, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ConsistencyTokenHeader, "latest"))
+	_, err := consistencyUnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v, want an invalid argument error", err)
+	}
+	if handled {
+		t.Error("handled a request with an invalid token")
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReadConsistencyHeader, "primary"))
+	resp, err := consistencyUnaryInterceptor(ctx, "req", &grpc.UnaryServerInfo{}, handler)
+	if err != nil || resp != "req" || !handled {
+		t.Errorf("got %v, %v, want the request handled", resp, err)
+	}
+}
+
+func TestForwardHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		header  string
+		want    string
+		forward bool
+	}{
+		{header: "X-Consistency-Token", want: "x-consistency-token", forward: true},
+		{header: "x-consistency-token", want: "x-consistency-token", forward: true},
+		{header: "X-Read-Consistency", want: "x-read-consistency", forward: true},
+		{header: "X-Namespace", want: "x-namespace", forward: true},
+		{header: "X-Request-Id", want: "x-request-id", forward: true},
+		{header: "x-request-id", want: "x-request-id", forward: true},
+		{header: "Authorization", want: runtime.MetadataPrefix + "Authorization", forward: true},
+		{header: "X-Unknown", forward: false},
+	} {
+		t.Run(tc.header, func(t *testing.T) {
+			got, forward := forwardRequestID(tc.header)
+			if forward != tc.forward || (forward && got != tc.want) {
+				t.Errorf("forwardRequestID(%q) = %q, %v, want %q, %v", tc.header, got, forward, tc.want, tc.forward)
+			}
+		})
+	}
+}
+
+func TestReturnHeaders(t *testing.T) {
+	for _, tc := range []struct {
+		key      string
+		want     string
+		returned bool
+	}{
+		{key: "x-consistency-token", want: ConsistencyTokenHeader, returned: true},
+		{key: "x-request-id", returned: false},
+		{key: "content-type", want: runtime.MetadataHeaderPrefix + "content-type", returned: true},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			got, ok := returnHeaders(tc.key)
+			if ok != tc.returned || got != tc.want {
+				t.Errorf("returnHeaders(%q) = %q, %v, want %q, %v", tc.key, got, ok, tc.want, tc.returned)
+			}
+		})
+	}
+}
@@ -2,59 +2,180 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"github.com/rs/cors"
-	"github.com/skip-mev/platform-take-home/observability/logging"
-	"go.uber.org/zap"
 
 	"fmt"
 	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/recording"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/protobuf/encoding/protojson"
 	"net"
 	"net/http"
+	"net/url"
+	"unicode/utf8"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 )
 
-func StartGRPCGateway(ctx context.Context, host string, port int) error {
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+// Gateway is the REST gateway in front of the gRPC server.
+type Gateway struct {
+	server http.Server
+	conn   *grpc.ClientConn
+	tls    *tls.Config
+}
+
+// NewGateway creates the REST gateway for the gRPC server at endpoint.
+// recorder, which may be nil, records sampled requests. tlsConfig, the gRPC
+// server's, secures and authenticates both the gateway's clients and its
+// connection to the gRPC server. GET /healthz reports the gRPC server's
+// health.
+func NewGateway(ctx context.Context, endpoint string, recorder *recording.Recorder, tlsConfig TLSConfig) (*Gateway, error) {
+	serverTLS, err := tlsConfig.load()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := insecure.NewCredentials()
+	if serverTLS != nil {
+		creds = serverTLS.gatewayCredentials()
+	}
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	jsonPb := runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		},
 	}
 
 	mux := runtime.NewServeMux(
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				UseProtoNames:   true,
-				EmitUnpopulated: true,
-			},
-		}))
-
-	if err := types.RegisterTakeHomeServiceHandlerFromEndpoint(ctx, mux, "localhost:9008", opts); err != nil {
-		return err
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &jsonPb),
+		runtime.WithMarshalerOption(mimeEventStream, &sseMarshaler{JSONPb: jsonPb}),
+		runtime.WithIncomingHeaderMatcher(forwardRequestID),
+		runtime.WithOutgoingHeaderMatcher(returnHeaders),
+		runtime.WithMetadata(recorder.GatewayMetadata),
+	)
+
+	if err := types.RegisterTakeHomeServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := mux.HandlePath(http.MethodGet, "/healthz", healthz(healthpb.NewHealthClient(conn))); err != nil {
+		conn.Close()
+		return nil, err
 	}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	corsMiddleware := cors.New(cors.Options{})
+	handler := corsMiddleware.Handler(requestIDs(recorder.Middleware(requireUTF8(namespacePaths(authenticateNamespaces(resumeFromLastEventID(mux)))))))
+
+	gateway := &Gateway{server: http.Server{Handler: handler}, conn: conn}
+	if serverTLS != nil {
+		gateway.tls = serverTLS.config
+	}
+
+	return gateway, nil
+}
+
+// Serve serves the gateway on listener, over TLS if configured, until
+// Shutdown is called.
+func (g *Gateway) Serve(listener net.Listener) error {
+	if g.tls != nil {
+		listener = tls.NewListener(listener, g.tls)
+	}
+
+	if err := g.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving http: %v", err)
+	}
+
+	return nil
+}
 
+// Shutdown stops the gateway gracefully, waiting for in-flight requests,
+// open streams included, until ctx is done. Then it closes the remaining
+// connections, and its connection to the gRPC server.
+func (g *Gateway) Shutdown(ctx context.Context) error {
+	err := g.server.Shutdown(ctx)
 	if err != nil {
-		return fmt.Errorf("error creating listener: %v", err)
+		err = errors.Join(err, g.server.Close())
 	}
 
-	corsMiddleware := cors.New(cors.Options{})
-	handler := corsMiddleware.Handler(mux)
-	server := http.Server{Handler: handler}
+	return errors.Join(err, g.conn.Close())
+}
+
+// ServeGRPCGateway serves the REST gateway for the gRPC server at endpoint on
+// listener until ctx is done, then stops gracefully, without a timeout.
+func ServeGRPCGateway(ctx context.Context, listener net.Listener, endpoint string, recorder *recording.Recorder, tlsConfig TLSConfig) error {
+	gateway, err := NewGateway(ctx, endpoint, recorder, tlsConfig)
+	if err != nil {
+		return err
+	}
 
+	shutdown := make(chan error, 1)
 	go func() {
 		<-ctx.Done()
-		if err := server.Shutdown(context.Background()); err != nil {
-			logging.FromContext(ctx).Fatal("error shutting down http server", zap.Error(err))
-		}
+		shutdown <- gateway.Shutdown(context.Background())
 	}()
 
-	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
-		return fmt.Errorf("error serving http: %v", err)
+	if err := gateway.Serve(listener); err != nil {
+		return err
+	}
+
+	if err := <-shutdown; err != nil {
+		return fmt.Errorf("error shutting down http server: %w", err)
 	}
 
 	return nil
 }
+
+// healthz reports the gRPC server's health: 200 while it's serving, and 503
+// once it's marked unready or can't be reached.
+func healthz(client healthpb.HealthClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		res, err := client.Check(r.Context(), &healthpb.HealthCheckRequest{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		if res.Status != healthpb.HealthCheckResponse_SERVING {
+			http.Error(w, res.Status.String(), http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprintln(w, res.Status)
+	}
+}
+
+// requireUTF8 rejects requests whose path or query parameters aren't valid
+// UTF-8. The gateway would copy them into string fields, which gRPC then
+// fails to marshal with an internal error.
+func requireUTF8(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		valid := utf8.ValidString(r.URL.Path)
+
+		// the gateway rejects malformed queries itself
+		query, _ := url.ParseQuery(r.URL.RawQuery)
+		for key, values := range query {
+			valid = valid && utf8.ValidString(key)
+			for _, value := range values {
+				valid = valid && utf8.ValidString(value)
+			}
+		}
+
+		if !valid {
+			http.Error(w, "path and query parameters must be valid UTF-8", http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,125 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/testutil"
+	"google.golang.org/grpc/codes"
+)
+
+// FuzzGateway sends arbitrary requests through the REST gateway to a real
+// server. JSON that the gateway can't decode into the request must be
+// rejected, and whatever it decodes must not crash the server or fail with
+// an internal error.
+func FuzzGateway(f *testing.F) {
+	s := testutil.Start(f, testutil.Config{})
+
+	seeds := []struct{ method, target, body string }{
+		{http.MethodPost, "/items", ""},
+		{http.MethodPost, "/items", "{}"},
+		{http.MethodPost, "/items", `{"item": null}`},
+		{http.MethodPost, "/items", `{"item": {"name": "Kettle", "labels": {"env": "prod"}, "attributes": {"size": 2}}}`},
+		{http.MethodPost, "/items", `{"item": {"id": "18446744073709551615", "kind": "missing"}}`},
+		{http.MethodGet, "/items?label_selector=env%3Dprod&page_size=2&read_mask=name,labels", ""},
+		{http.MethodGet, "/items?attribute_filter=size%3E%3D1&page_token=AQ", ""},
+		{http.MethodGet, "/items/1?read_mask=attributes", ""},
+		{http.MethodPut, "/items/1?update_mask=name", `{"name": "Teapot"}`},
+		{http.MethodPatch, "/items/1", `{"labels": {"env": "dev"}}`},
+		{http.MethodDelete, "/items/9223372036854775808", ""},
+		{http.MethodGet, "/items:search?q=kettle&limit=5", ""},
+		{http.MethodPost, "/items:import", `{"item": {"name": "Imported"}}` + "\n" + `{"item": {}}`},
+		{http.MethodGet, "/items:export?batch_size=1", ""},
+		{http.MethodPut, "/kinds/gadget", `{"schema": {"type": "object", "required": ["color"]}}`},
+		{http.MethodGet, "/kinds", ""},
+		{http.MethodGet, "/webhooks/1/deliveries?status=DELIVERY_STATUS_DEAD", ""},
+		{http.MethodPost, "/webhooks/deliveries/1:redeliver", ""},
+		{http.MethodGet, "/namespaces/team-a/items", ""},
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed.method, seed.target, seed.body)
+	}
+
+	f.Fuzz(func(t *testing.T, method, target, body string) {
+		path, _, _ := strings.Cut(target, "?")
+
+		// watches never end, and webhooks would make the server call out
+		// to whatever URL the fuzzer comes up with
+		if strings.Contains(path, ":watch") || (method == http.MethodPost && strings.HasSuffix(path, "/webhooks")) {
+			return
+		}
+
+		if !strings.HasPrefix(target, "/") {
+			return
+		}
+		if _, err := url.ParseRequestURI(target); err != nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, method, s.URL+target, strings.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := s.HTTP.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s %q: %v", method, target, body, err)
+		}
+		defer res.Body.Close()
+
+		data, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("%s %s %q: %v", method, target, body, err)
+		}
+
+		if res.StatusCode == http.StatusInternalServerError {
+			t.Errorf("%s %s %q: got %s: %s", method, target, body, res.Status, data)
+		}
+
+		if strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+			if err := checkJSON(data); err != nil {
+				t.Errorf("%s %s %q: %v: %s", method, target, body, err, data)
+			}
+		}
+	})
+}
+
+// checkJSON checks that data holds one or more JSON values, as unary and
+// streamed responses do, and that no streamed message failed with an
+// internal error: streams fail after their 200 OK.
+func checkJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	for {
+		var message struct {
+			Error *struct {
+				Code codes.Code `json:"code"`
+			} `json:"error"`
+		}
+
+		err := decoder.Decode(&message)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+
+		if message.Error != nil && (message.Error.Code == codes.Unknown || message.Error.Code == codes.Internal) {
+			return fmt.Errorf("stream failed with %v", message.Error.Code)
+		}
+	}
+}
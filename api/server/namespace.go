@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/skip-mev/platform-take-home/tenancy"
+)
+
+// forwardNamespaceHeader passes the namespace header on to the gRPC server,
+// along with the headers the gateway forwards by default.
+func forwardNamespaceHeader(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == tenancy.Header {
+		return strings.ToLower(tenancy.Header), true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// namespacePaths serves every route under namespaces/{namespace}/ as well,
+// e.g. GET /namespaces/team-a/items/1, by moving the namespace into the
+// namespace header. A request can't name two different namespaces.
+func namespacePaths(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/namespaces/")
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		namespace, rest, _ := strings.Cut(rest, "/")
+
+		if header := r.Header.Get(tenancy.Header); header != "" && header != namespace {
+			http.Error(w, "namespace in path and "+tenancy.Header+" header differ", http.StatusBadRequest)
+			return
+		}
+
+		r.Header.Set(tenancy.Header, namespace)
+		r.URL.Path = "/" + rest
+		r.URL.RawPath = ""
+
+		next.ServeHTTP(w, r)
+	})
+}
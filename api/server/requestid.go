@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/requestid"
+)
+
+// requestIDs gives every gateway request an ID: the client's X-Request-Id,
+// if it's valid, or a new one. The ID is forwarded to the gRPC server and
+// returned in the response headers, also of requests that fail before
+// reaching it.
+func requestIDs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+			r.Header.Set(requestid.Header, id)
+		}
+
+		w.Header().Set(requestid.Header, id)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardRequestID passes the request ID header on to the gRPC server,
+// along with the other headers it forwards.
+func forwardRequestID(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == requestid.Header {
+		return strings.ToLower(requestid.Header), true
+	}
+
+	return forwardHeaders(key)
+}
+
+// returnHeaders drops the request ID the gRPC server returns, which
+// requestIDs already set as a plain response header, and returns the rest
+// like returnConsistencyToken.
+func returnHeaders(key string) (string, bool) {
+	if key == strings.ToLower(requestid.Header) {
+		return "", false
+	}
+
+	return returnConsistencyToken(key)
+}
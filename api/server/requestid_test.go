@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/requestid"
+)
+
+func TestRequestIDs(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		header string
+		// want is the ID the request is forwarded with, or "" for a
+		// generated one
+		want string
+	}{
+		{name: "incoming", header: "req-1", want: "req-1"},
+		{name: "none"},
+		{name: "invalid", header: "req 1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var forwarded string
+			handler := requestIDs(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				forwarded = r.Header.Get(requestid.Header)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+			if tc.header != "" {
+				req.Header.Set(requestid.Header, tc.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			switch {
+			case tc.want != "" && forwarded != tc.want:
+				t.Errorf("forwarded %q, want %q", forwarded, tc.want)
+			case tc.want == "" && (!requestid.Valid(forwarded) || forwarded == tc.header):
+				t.Errorf("forwarded %q, want a generated ID", forwarded)
+			}
+
+			if got := rec.Header().Values(requestid.Header); len(got) != 1 || got[0] != forwarded {
+				t.Errorf("returned %v, want %q", got, forwarded)
+			}
+		})
+	}
+}
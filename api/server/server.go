@@ -2,74 +2,192 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/skip-mev/platform-take-home/api/service"
 	"github.com/skip-mev/platform-take-home/observability/logging"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
-	"os"
 
 	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/recording"
+	"github.com/skip-mev/platform-take-home/requestid"
+	"github.com/skip-mev/platform-take-home/seed"
 	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"github.com/skip-mev/platform-take-home/webhook"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// itemEventRetention is how long watchers can fall behind before they
+	// have to re-list instead of resuming.
+	itemEventRetention  = 24 * time.Hour
+	itemEventCompaction = time.Hour
 )
 
 type Server struct {
 	grpcServer *grpc.Server
+	health     *health.Server
+	// serving is closed once Serve has set up the store and starts
+	// accepting connections.
+	serving chan struct{}
 }
 
-func NewServer() *Server {
-	return &Server{
-		grpcServer: grpc.NewServer(
-			grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		),
+// NewServer creates the gRPC server. Requests log to logger. recorder, which
+// may be nil, records sampled requests. tlsConfig secures connections and
+// authenticates clients. The server's health service reports it as serving
+// until it's marked unready.
+func NewServer(logger *zap.Logger, recorder *recording.Recorder, tlsConfig TLSConfig) (*Server, error) {
+	serverTLS, err := tlsConfig.load()
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (s *Server) Start(ctx context.Context, address string) error {
-	listener, err := net.Listen("tcp", address)
+	options := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(requestid.UnaryServerInterceptor(), logging.UnaryServerInterceptor(logger, 1), tenancy.UnaryServerInterceptor(serverTLS.authentication()), consistencyUnaryInterceptor, recorder.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(requestid.StreamServerInterceptor(), logging.StreamServerInterceptor(logger, 1), tenancy.StreamServerInterceptor(serverTLS.authentication()), consistencyStreamInterceptor),
+	}
+	if serverTLS != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(serverTLS.config)))
+	}
 
-	if err != nil {
-		logging.FromContext(ctx).Fatal("error creating database connection", zap.Error(err))
-		return err
+	s := &Server{
+		grpcServer: grpc.NewServer(options...),
+		health:     health.NewServer(),
+		serving:    make(chan struct{}),
 	}
 
-	var dbStore *store.DBStore
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
 
-	if os.Getenv("POSTGRES_DSN") != "" {
-		dbStore, err = store.NewPostgresBackedStore(os.Getenv("POSTGRES_DSN"))
-		if err != nil {
-			logging.FromContext(ctx).Fatal("error creating database connection", zap.Error(err))
-			return err
-		}
-	} else {
-		dbStore, err = store.NewSQLiteBackedStore()
-		if err != nil {
-			logging.FromContext(ctx).Fatal("error creating database connection", zap.Error(err))
-			return err
-		}
+	return s, nil
+}
+
+// Serve migrates dbStore and serves it on listener until ctx is done or
+// Shutdown is called, then waits for the background work to finish. When
+// ctx is done, it stops gracefully, without a timeout; if that's before it
+// starts serving, it returns nil rather than the error of the step it was
+// at. It does not close dbStore.
+func (s *Server) Serve(ctx context.Context, listener net.Listener, dbStore *store.DBStore) error {
+	webhookConfig, err := service.WebhookConfigFromEnv()
+	if err != nil {
+		return err
 	}
 
-	if err := dbStore.Migrate(); err != nil {
-		logging.FromContext(ctx).Fatal("error migrating database", zap.Error(err))
+	takeHomeService, err := setUp(ctx, dbStore)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
 		return err
 	}
 
-	takeHomeService := service.NewTakeHomeService(dbStore)
+	takeHomeService.ConfigureWebhooks(webhookConfig)
+	takeHomeService.ConfigureAdmin(service.AdminConfigFromEnv())
 
 	types.RegisterTakeHomeServiceServer(s.grpcServer, takeHomeService)
 	reflection.Register(s.grpcServer)
 
+	var background sync.WaitGroup
+	defer background.Wait()
+
+	// stop the background work too if serving fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for _, run := range []func(context.Context){
+		dbStore.MonitorReplicas,
+		func(ctx context.Context) { compactItemEvents(ctx, dbStore) },
+		webhook.NewDispatcher(dbStore, webhookConfig).Run,
+	} {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			run(ctx)
+		}()
+	}
+
+	close(s.serving)
+
+	// started last, so that only stopping the server is left to it. A stop
+	// that comes before grpcServer.Serve makes it return ErrServerStopped.
+	background.Add(1)
 	go func() {
+		defer background.Done()
 		<-ctx.Done()
-		s.grpcServer.GracefulStop()
+		s.Shutdown(context.Background())
 	}()
 
-	if err := s.grpcServer.Serve(listener); err != nil {
-		logging.FromContext(ctx).Fatal("error serving grpc", zap.Error(err))
+	if err := s.grpcServer.Serve(listener); !errors.Is(err, grpc.ErrServerStopped) {
 		return err
 	}
 
 	return nil
 }
+
+// setUp migrates dbStore, enables its cache and seeds it, as configured by
+// the environment.
+func setUp(ctx context.Context, dbStore *store.DBStore) (*service.TakeHomeService, error) {
+	if err := dbStore.Migrate(); err != nil {
+		return nil, fmt.Errorf("error migrating database: %w", err)
+	}
+
+	cacheConfig, err := store.CacheConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error reading item cache config: %w", err)
+	}
+
+	if err := dbStore.EnableCache(ctx, cacheConfig); err != nil {
+		return nil, fmt.Errorf("error enabling item cache: %w", err)
+	}
+
+	takeHomeService := service.NewTakeHomeService(dbStore)
+
+	seedConfig, err := seed.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error reading seed config: %w", err)
+	}
+
+	if !seedConfig.Empty() {
+		if _, err := seed.Run(ctx, dbStore, takeHomeService.ValidateAttributes, seedConfig); err != nil {
+			return nil, fmt.Errorf("error seeding items: %w", err)
+		}
+	}
+
+	return takeHomeService, nil
+}
+
+// Serving returns a channel that's closed once Serve has migrated, and
+// seeded, the store, and starts accepting connections.
+func (s *Server) Serving() <-chan struct{} {
+	return s.serving
+}
+
+func compactItemEvents(ctx context.Context, dbStore *store.DBStore) {
+	ticker := time.NewTicker(itemEventCompaction)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := dbStore.CompactItemEvents(ctx, time.Now().Add(-itemEventRetention))
+		if err != nil {
+			logging.FromContext(ctx).Error("error compacting item events", zap.Error(err))
+			continue
+		}
+
+		logging.FromContext(ctx).Debug("compacted item events", zap.Int64("deleted", deleted))
+	}
+}
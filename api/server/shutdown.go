@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	defaultDrainPeriod     = 5 * time.Second
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+// ShutdownConfig paces the server's shutdown.
+type ShutdownConfig struct {
+	// DrainPeriod is how long the servers keep serving once they're
+	// marked unready, so that load balancers stop routing to them before
+	// they refuse connections.
+	DrainPeriod time.Duration
+	// Timeout bounds how long the gateway, then the gRPC server, wait for
+	// in-flight requests before closing their connections.
+	Timeout time.Duration
+}
+
+// ShutdownConfigFromEnv reads SHUTDOWN_DRAIN_PERIOD and SHUTDOWN_TIMEOUT, Go
+// durations.
+func ShutdownConfigFromEnv() (ShutdownConfig, error) {
+	config := ShutdownConfig{DrainPeriod: defaultDrainPeriod, Timeout: defaultShutdownTimeout}
+
+	if period := os.Getenv("SHUTDOWN_DRAIN_PERIOD"); period != "" {
+		var err error
+		if config.DrainPeriod, err = time.ParseDuration(period); err != nil || config.DrainPeriod < 0 {
+			return config, fmt.Errorf("invalid SHUTDOWN_DRAIN_PERIOD %q", period)
+		}
+	}
+
+	if timeout := os.Getenv("SHUTDOWN_TIMEOUT"); timeout != "" {
+		var err error
+		if config.Timeout, err = time.ParseDuration(timeout); err != nil || config.Timeout <= 0 {
+			return config, fmt.Errorf("invalid SHUTDOWN_TIMEOUT %q", timeout)
+		}
+	}
+
+	return config, nil
+}
+
+// MarkUnready fails the gRPC health checks, and the gateway's /healthz,
+// from now on, while requests are still served.
+func (s *Server) MarkUnready() {
+	s.health.Shutdown()
+}
+
+// Shutdown marks the server unready and stops it gracefully, waiting for
+// in-flight RPCs, open streams included, until ctx is done. Then it closes
+// the remaining connections, failing their RPCs, and returns ctx's error.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.MarkUnready()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/skip-mev/platform-take-home/api/types"
+)
+
+const mimeEventStream = "text/event-stream"
+
+// sseMarshaler frames streamed gateway responses as Server-Sent Events. It is
+// selected when a client sends "Accept: text/event-stream". Watch events carry
+// their resource version as the SSE id, so a reconnecting EventSource resumes
+// where it left off via Last-Event-ID.
+type sseMarshaler struct {
+	runtime.JSONPb
+}
+
+func (m *sseMarshaler) ContentType(_ interface{}) string {
+	return mimeEventStream
+}
+
+func (m *sseMarshaler) Delimiter() []byte {
+	return []byte("\n\n")
+}
+
+func (m *sseMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if chunk, ok := v.(map[string]interface{}); ok {
+		if event, ok := chunk["result"].(*types.WatchItemsResponse); ok {
+			eventName := strings.TrimPrefix(event.Type.String(), "EVENT_TYPE_")
+			fmt.Fprintf(&buf, "id: %d\nevent: %s\n", event.ResourceVersion, eventName)
+		} else if _, ok := chunk["error"]; ok {
+			buf.WriteString("event: error\n")
+		}
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// resumeFromLastEventID maps the Last-Event-ID header sent by reconnecting
+// EventSource clients onto the watch resource_version parameter.
+func resumeFromLastEventID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get("Last-Event-ID"); id != "" && r.URL.Query().Get("resource_version") == "" {
+			query := r.URL.Query()
+			query.Set("resource_version", id)
+			r.URL.RawQuery = query.Encode()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"google.golang.org/grpc/credentials"
+)
+
+// TLSConfig configures TLS on the gRPC server and the gateway. Its zero
+// value serves plaintext.
+type TLSConfig struct {
+	// CertFile and KeyFile are the certificate both servers present. The
+	// gateway presents it to the gRPC server too, as a client certificate,
+	// so with ClientCAFile it must be issued by one of those CAs and allow
+	// client authentication.
+	CertFile string
+	KeyFile  string
+	// ClientCAFile holds the CAs that issue client certificates. Setting it
+	// requires a client certificate on every connection, whose subject's
+	// first Organization is the namespace the client acts in.
+	ClientCAFile string
+}
+
+// TLSConfigFromEnv reads TLS_CERT_FILE, TLS_KEY_FILE and TLS_CLIENT_CA_FILE.
+func TLSConfigFromEnv() (TLSConfig, error) {
+	config := TLSConfig{
+		CertFile:     os.Getenv("TLS_CERT_FILE"),
+		KeyFile:      os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile: os.Getenv("TLS_CLIENT_CA_FILE"),
+	}
+
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return config, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if config.ClientCAFile != "" && config.CertFile == "" {
+		return config, errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	return config, nil
+}
+
+// Authenticated reports whether clients authenticate with certificates,
+// rather than choose their namespace with the namespace header.
+func (c TLSConfig) Authenticated() bool {
+	return c.ClientCAFile != ""
+}
+
+// serverTLS is the loaded form of a TLSConfig.
+type serverTLS struct {
+	config      *tls.Config
+	certificate tls.Certificate
+}
+
+// load reads the certificates of c, or returns nil if TLS is disabled.
+func (c TLSConfig) load() (*serverTLS, error) {
+	if c.CertFile == "" {
+		return nil, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+
+	config := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading TLS client CAs: %w", err)
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found", c.ClientCAFile)
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return &serverTLS{config: config, certificate: certificate}, nil
+}
+
+// authentication is how the gRPC server authenticates namespaces: from
+// client certificates, trusting the gateway, which presents the server's
+// own certificate, to pass on the namespaces of its clients.
+func (t *serverTLS) authentication() tenancy.Authentication {
+	if t == nil || t.config.ClientCAs == nil {
+		return tenancy.Authentication{}
+	}
+
+	return tenancy.Authentication{RequirePrincipal: true, Forwarder: t.certificate.Certificate[0]}
+}
+
+// gatewayCredentials are the credentials the gateway connects to the gRPC
+// server in the same process with: its certificate, and the server's, which
+// is pinned rather than verified by name, since the gateway dials whichever
+// address the gRPC server listens on.
+func (t *serverTLS) gatewayCredentials() credentials.TransportCredentials {
+	own := t.certificate.Certificate[0]
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates:       []tls.Certificate{t.certificate},
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], own) {
+				return errors.New("gRPC server presented another certificate than the gateway's")
+			}
+			return nil
+		},
+		MinVersion: tls.VersionTLS12,
+	})
+}
+
+// authenticateNamespaces sets the namespace header of gateway requests to
+// the namespace of their client certificate, which TLS has verified, and
+// rejects requests for other namespaces. Requests without a certificate
+// keep their header, when TLS doesn't require one.
+func authenticateNamespaces(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		namespace, ok := tenancy.CertificateNamespace(r.TLS.VerifiedChains[0][0])
+		if !ok {
+			http.Error(w, "client certificate names no namespace in its subject's Organization", http.StatusUnauthorized)
+			return
+		}
+
+		if header := r.Header.Get(tenancy.Header); header != "" && header != namespace {
+			http.Error(w, fmt.Sprintf("principal may not act in namespace %q", header), http.StatusForbidden)
+			return
+		}
+
+		r.Header.Set(tenancy.Header, namespace)
+
+		next.ServeHTTP(w, r)
+	})
+}
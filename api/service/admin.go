@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AdminConfig configures who may make the administrative calls, which change
+// what every client of a namespace may write, such as PutItemKind.
+type AdminConfig struct {
+	// Token is the bearer token administrators send in the authorization
+	// header. Without one, administrative calls are refused.
+	Token string
+}
+
+// AdminConfigFromEnv reads ADMIN_TOKEN.
+func AdminConfigFromEnv() AdminConfig {
+	return AdminConfig{Token: os.Getenv("ADMIN_TOKEN")}
+}
+
+// ConfigureAdmin sets the token administrative calls must carry; by default
+// they are refused.
+func (s *TakeHomeService) ConfigureAdmin(config AdminConfig) {
+	s.admin = config
+}
+
+// requireAdmin fails with PermissionDenied unless the call carries the admin
+// token as a bearer token.
+func (s *TakeHomeService) requireAdmin(ctx context.Context) error {
+	if s.admin.Token == "" {
+		return status.Error(codes.PermissionDenied, "administrative calls are disabled; set ADMIN_TOKEN to enable them")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "bearer") && subtle.ConstantTimeCompare([]byte(token), []byte(s.admin.Token)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.PermissionDenied, "an administrator's bearer token is required")
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		token         string
+		authorization []string
+		want          codes.Code
+	}{
+		{name: "admin", token: "s3cret", authorization: []string{"Bearer s3cret"}, want: codes.OK},
+		{name: "scheme case", token: "s3cret", authorization: []string{"bearer s3cret"}, want: codes.OK},
+		{name: "second value", token: "s3cret", authorization: []string{"Basic dXNlcg==", "Bearer s3cret"}, want: codes.OK},
+		{name: "anonymous", token: "s3cret", want: codes.PermissionDenied},
+		{name: "wrong token", token: "s3cret", authorization: []string{"Bearer s3cre"}, want: codes.PermissionDenied},
+		{name: "bare token", token: "s3cret", authorization: []string{"s3cret"}, want: codes.PermissionDenied},
+		{name: "other scheme", token: "s3cret", authorization: []string{"Basic s3cret"}, want: codes.PermissionDenied},
+		{name: "disabled", authorization: []string{"Bearer "}, want: codes.PermissionDenied},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &TakeHomeService{}
+			s.ConfigureAdmin(AdminConfig{Token: tc.token})
+
+			md := metadata.MD{}
+			md.Append("authorization", tc.authorization...)
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			if got := status.Code(s.requireAdmin(ctx)); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,15 +2,19 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"github.com/skip-mev/platform-take-home/api/types"
 	"github.com/skip-mev/platform-take-home/observability/logging"
 	"github.com/skip-mev/platform-take-home/store"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type TakeHomeService struct {
-	store *store.DBStore
+	store    *store.DBStore
+	schemas  schemaCache
+	webhooks WebhookConfig
+	admin    AdminConfig
 	types.UnimplementedTakeHomeServiceServer
 }
 
@@ -20,51 +24,150 @@ func NewTakeHomeService(store *store.DBStore) *TakeHomeService {
 	return &TakeHomeService{store: store}
 }
 
-func (s *TakeHomeService) GetItems(ctx context.Context, _ *types.EmptyRequest) (*types.GetItemsResponse, error) {
-	items, err := s.store.GetItems(ctx)
+func (s *TakeHomeService) GetItems(ctx context.Context, req *types.GetItemsRequest) (*types.GetItemsResponse, error) {
+	selector, err := store.ParseSelector(req.LabelSelector)
+	if err != nil {
+		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	attributeFilters, err := store.ParseAttributeFilters(req.AttributeFilter)
+	if err != nil {
+		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	fields, err := readMaskFields(req.ReadMask)
+	if err != nil {
+		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if req.PageSize > maxPageSize {
+		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, status.Errorf(codes.InvalidArgument, "page_size must be at most %d", maxPageSize)
+	}
+
+	afterID, err := parsePageToken(req.PageToken)
+	if err != nil {
+		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := store.ItemFilter{Labels: selector, Attributes: attributeFilters, Fields: fields, AfterID: afterID}
+	if req.PageSize > 0 {
+		// one more than asked for tells whether there is a next page
+		filter.Limit = int(req.PageSize) + 1
+	}
+
+	items, err := s.store.GetItems(ctx, filter)
 
 	if err != nil {
 		logging.FromContext(ctx).Error("failed to retrieve items", zap.Error(err))
-		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, fmt.Errorf("failed to retrieve items")
+		return &types.GetItemsResponse{Items: make([]*types.Item, 0)}, storeError(err, "failed to retrieve items")
+	}
+
+	var nextPageToken string
+	if req.PageSize > 0 && len(items) > int(req.PageSize) {
+		items = items[:req.PageSize]
+		nextPageToken = pageToken(items[len(items)-1].ID)
 	}
 
 	apiItems := make([]*types.Item, 0, len(items))
 
 	for _, item := range items {
-		apiItems = append(apiItems, &types.Item{
-			Id:          uint64(item.ID),
-			Name:        item.Name,
-			Description: item.Description,
-		})
+		apiItem := ItemToAPI(&item)
+		applyReadMask(apiItem, req.ReadMask)
+		apiItems = append(apiItems, apiItem)
 	}
 
-	return &types.GetItemsResponse{Items: apiItems}, nil
+	return &types.GetItemsResponse{Items: apiItems, NextPageToken: nextPageToken}, nil
 }
 
 func (s *TakeHomeService) GetItem(ctx context.Context, req *types.GetItemRequest) (*types.GetItemResponse, error) {
-	item, err := s.store.GetItem(ctx, uint(req.Id))
+	fields, err := readMaskFields(req.ReadMask)
+	if err != nil {
+		return &types.GetItemResponse{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.store.GetItem(ctx, uint(req.Id), fields...)
 
 	if err != nil {
 		logging.FromContext(ctx).Error("failed to retrieve item", zap.Error(err))
-		return &types.GetItemResponse{}, fmt.Errorf("failed to retrieve item")
+		return &types.GetItemResponse{}, storeError(err, "failed to retrieve item")
 	}
 
-	return &types.GetItemResponse{
-		Item: &types.Item{
-			Id:          uint64(item.ID),
-			Name:        item.Name,
-			Description: item.Description,
-		},
-	}, nil
+	apiItem := ItemToAPI(item)
+	applyReadMask(apiItem, req.ReadMask)
+
+	return &types.GetItemResponse{Item: apiItem}, nil
 }
 
 func (s *TakeHomeService) CreateItem(ctx context.Context, req *types.CreateItemRequest) (*types.CreateItemResponse, error) {
-	item, err := s.store.CreateItem(ctx, req.Item.Name, req.Item.Description)
+	// e.g. POST /items with an empty body
+	if req.Item == nil {
+		return &types.CreateItemResponse{}, status.Error(codes.InvalidArgument, "item is required")
+	}
+
+	newItem := ItemFromAPI(req.Item)
+	newItem.ID = 0
+
+	if err := newItem.Labels.Validate(); err != nil {
+		return &types.CreateItemResponse{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.ValidateAttributes(ctx, &newItem); err != nil {
+		return &types.CreateItemResponse{}, err
+	}
+
+	item, err := s.store.CreateItem(ctx, &newItem)
 
 	if err != nil {
 		logging.FromContext(ctx).Error("failed to create item", zap.Error(err))
-		return &types.CreateItemResponse{}, fmt.Errorf("failed to create item")
+		return &types.CreateItemResponse{}, storeError(err, "failed to create item")
 	}
 
 	return &types.CreateItemResponse{ItemId: uint64(item)}, nil
 }
+
+func (s *TakeHomeService) UpdateItem(ctx context.Context, req *types.UpdateItemRequest) (*types.UpdateItemResponse, error) {
+	if req.Item == nil {
+		return &types.UpdateItemResponse{}, status.Error(codes.InvalidArgument, "item is required")
+	}
+
+	fields, err := updateMaskFields(req.UpdateMask)
+	if err != nil {
+		return &types.UpdateItemResponse{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	update := ItemFromAPI(req.Item)
+
+	// the item the update produces is validated in the store's transaction,
+	// against the row and the kind it is written over
+	var invalid error
+	check := func(item *store.Item, kind *store.ItemKind) error {
+		if err := item.Labels.Validate(); err != nil {
+			invalid = status.Error(codes.InvalidArgument, err.Error())
+		} else {
+			invalid = s.validateKind(ctx, item, kind)
+		}
+		return invalid
+	}
+
+	item, err := s.store.UpdateItemChecked(ctx, &update, check, fields...)
+
+	if invalid != nil {
+		return &types.UpdateItemResponse{}, invalid
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to update item", zap.Error(err))
+		return &types.UpdateItemResponse{}, storeError(err, "failed to update item")
+	}
+
+	return &types.UpdateItemResponse{Item: ItemToAPI(item)}, nil
+}
+
+func (s *TakeHomeService) DeleteItem(ctx context.Context, req *types.DeleteItemRequest) (*types.DeleteItemResponse, error) {
+	if err := s.store.DeleteItem(ctx, uint(req.Id)); err != nil {
+		logging.FromContext(ctx).Error("failed to delete item", zap.Error(err))
+		return &types.DeleteItemResponse{}, storeError(err, "failed to delete item")
+	}
+
+	return &types.DeleteItemResponse{}, nil
+}
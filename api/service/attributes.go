@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gorm.io/gorm"
+)
+
+// schemaCache holds compiled kind schemas by namespace and name. Entries are
+// stamped with the kind's UpdatedAt, so a schema replaced through another
+// replica is recompiled the next time it is used here.
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[string]compiledSchema
+}
+
+type compiledSchema struct {
+	updatedAt time.Time
+	schema    *jsonschema.Schema
+}
+
+func compileSchema(kind, schema string) (*jsonschema.Schema, error) {
+	return jsonschema.CompileString("urn:kind:"+kind, schema)
+}
+
+func (c *schemaCache) get(kind *store.ItemKind) (*jsonschema.Schema, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := kind.Namespace + "/" + kind.Name
+
+	if cached, ok := c.schemas[key]; ok && cached.updatedAt.Equal(kind.UpdatedAt) {
+		return cached.schema, nil
+	}
+
+	schema, err := compileSchema(kind.Name, kind.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.schemas == nil {
+		c.schemas = make(map[string]compiledSchema)
+	}
+	c.schemas[key] = compiledSchema{updatedAt: kind.UpdatedAt, schema: schema}
+
+	return schema, nil
+}
+
+// ValidateAttributes checks an item's attributes against the schema of its
+// kind. Items without a kind are not validated. Errors for invalid input carry
+// codes.InvalidArgument.
+func (s *TakeHomeService) ValidateAttributes(ctx context.Context, item *store.Item) error {
+	if item.Kind == "" {
+		return nil
+	}
+
+	kind, err := s.store.GetItemKind(ctx, item.Kind)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		kind, err = nil, nil
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to retrieve item kind", zap.Error(err))
+		return storeError(err, "failed to retrieve item kind")
+	}
+
+	return s.validateKind(ctx, item, kind)
+}
+
+// validateKind is ValidateAttributes with the item's kind already read, nil
+// if it's unknown.
+func (s *TakeHomeService) validateKind(ctx context.Context, item *store.Item, kind *store.ItemKind) error {
+	if item.Kind == "" {
+		return nil
+	}
+
+	if kind == nil {
+		return status.Errorf(codes.InvalidArgument, "unknown kind %q", item.Kind)
+	}
+
+	schema, err := s.schemas.get(kind)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to compile kind schema", zap.String("kind", kind.Name), zap.Error(err))
+		return fmt.Errorf("failed to compile schema for kind %q", kind.Name)
+	}
+
+	attributes := map[string]interface{}(item.Attributes)
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+
+	if err := schema.Validate(attributes); err != nil {
+		return status.Errorf(codes.InvalidArgument, "attributes do not match the schema for kind %q: %v", item.Kind, err)
+	}
+
+	return nil
+}
+
+// PutItemKind registers a kind's schema, which every item of the kind in the
+// namespace must then match, so only administrators may call it.
+func (s *TakeHomeService) PutItemKind(ctx context.Context, req *types.PutItemKindRequest) (*types.PutItemKindResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return &types.PutItemKindResponse{}, err
+	}
+
+	if req.Kind == nil || req.Kind.Name == "" {
+		return &types.PutItemKindResponse{}, status.Error(codes.InvalidArgument, "kind name is required")
+	}
+
+	if err := store.ValidateLabelValue(req.Kind.Name); err != nil {
+		return &types.PutItemKindResponse{}, status.Errorf(codes.InvalidArgument, "invalid kind name %q", req.Kind.Name)
+	}
+
+	schema, err := json.Marshal(req.Kind.Schema.AsMap())
+	if err != nil {
+		return &types.PutItemKindResponse{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := compileSchema(req.Kind.Name, string(schema)); err != nil {
+		return &types.PutItemKindResponse{}, status.Errorf(codes.InvalidArgument, "invalid JSON Schema: %v", err)
+	}
+
+	kind := store.ItemKind{Name: req.Kind.Name, Schema: string(schema)}
+
+	if err := s.store.PutItemKind(ctx, &kind); err != nil {
+		logging.FromContext(ctx).Error("failed to save item kind", zap.Error(err))
+		return &types.PutItemKindResponse{}, storeError(err, "failed to save item kind")
+	}
+
+	return &types.PutItemKindResponse{Kind: req.Kind}, nil
+}
+
+func (s *TakeHomeService) GetItemKinds(ctx context.Context, _ *types.EmptyRequest) (*types.GetItemKindsResponse, error) {
+	kinds, err := s.store.GetItemKinds(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to retrieve item kinds", zap.Error(err))
+		return &types.GetItemKindsResponse{Kinds: make([]*types.ItemKind, 0)}, storeError(err, "failed to retrieve item kinds")
+	}
+
+	apiKinds := make([]*types.ItemKind, 0, len(kinds))
+
+	for _, kind := range kinds {
+		var schema map[string]interface{}
+		if err := json.Unmarshal([]byte(kind.Schema), &schema); err != nil {
+			logging.FromContext(ctx).Error("failed to decode kind schema", zap.String("kind", kind.Name), zap.Error(err))
+			return &types.GetItemKindsResponse{Kinds: make([]*types.ItemKind, 0)}, fmt.Errorf("failed to retrieve item kinds")
+		}
+
+		apiSchema, err := structpb.NewStruct(schema)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to convert kind schema", zap.String("kind", kind.Name), zap.Error(err))
+			return &types.GetItemKindsResponse{Kinds: make([]*types.ItemKind, 0)}, fmt.Errorf("failed to retrieve item kinds")
+		}
+
+		apiKinds = append(apiKinds, &types.ItemKind{Name: kind.Name, Schema: apiSchema})
+	}
+
+	return &types.GetItemKindsResponse{Kinds: apiKinds}, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"io"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	importBatchSize        = 500
+	defaultExportBatchSize = 500
+	maxExportBatchSize     = 10000
+)
+
+// ImportItems upserts streamed items in batches. After every committed batch
+// it reports the running total. Unlike the import command it keeps no
+// checkpoint, so an interrupted stream can't be resumed: its last batch may
+// have committed without the total arriving, and only items with an ID are
+// safe to send again.
+func (s *TakeHomeService) ImportItems(stream types.TakeHomeService_ImportItemsServer) error {
+	ctx := stream.Context()
+	batch := make([]store.Item, 0, importBatchSize)
+	var imported uint64
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := s.store.UpsertItems(ctx, batch)
+		if errors.Is(err, store.ErrNamespaceConflict) {
+			return status.Errorf(codes.PermissionDenied, "items %d-%d: %v", imported+1, imported+uint64(len(batch)), err)
+		}
+
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to import items", zap.Error(err), zap.Uint64("imported", imported))
+			return storeError(err, "failed to import items")
+		}
+
+		imported += uint64(len(batch))
+		batch = batch[:0]
+
+		return stream.Send(&types.ImportItemsResponse{Imported: imported})
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if req.Item == nil {
+			continue
+		}
+
+		item := ItemFromAPI(req.Item)
+		if err := item.Labels.Validate(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "item %d: %v", imported+uint64(len(batch))+1, err)
+		}
+
+		if err := s.ValidateAttributes(ctx, &item); err != nil {
+			if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+				return status.Errorf(codes.InvalidArgument, "item %d: %s", imported+uint64(len(batch))+1, st.Message())
+			}
+			return err
+		}
+
+		batch = append(batch, item)
+
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ExportItems streams every item in ID order without loading the table into memory.
+func (s *TakeHomeService) ExportItems(req *types.ExportItemsRequest, stream types.TakeHomeService_ExportItemsServer) error {
+	ctx := stream.Context()
+
+	batchSize := int(req.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultExportBatchSize
+	}
+	batchSize = min(batchSize, maxExportBatchSize)
+
+	err := s.store.StreamItems(ctx, batchSize, func(item *store.Item) error {
+		return stream.Send(&types.ExportItemsResponse{Item: ItemToAPI(item)})
+	})
+
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to export items", zap.Error(err))
+		return storeError(err, "failed to export items")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventTypes = map[store.EventType]types.EventType{
+	store.EventAdded:    types.EventType_EVENT_TYPE_ADDED,
+	store.EventModified: types.EventType_EVENT_TYPE_MODIFIED,
+	store.EventDeleted:  types.EventType_EVENT_TYPE_DELETED,
+}
+
+var deliveryStatuses = map[store.DeliveryStatus]types.DeliveryStatus{
+	store.DeliveryPending:   types.DeliveryStatus_DELIVERY_STATUS_PENDING,
+	store.DeliveryDelivered: types.DeliveryStatus_DELIVERY_STATUS_DELIVERED,
+	store.DeliveryDead:      types.DeliveryStatus_DELIVERY_STATUS_DEAD,
+}
+
+// ItemToAPI converts a stored item to its API representation.
+func ItemToAPI(item *store.Item) *types.Item {
+	apiItem := &types.Item{
+		Id:          uint64(item.ID),
+		Name:        item.Name,
+		Description: item.Description,
+		Labels:      item.Labels,
+		Kind:        item.Kind,
+		Namespace:   item.Namespace,
+	}
+
+	if item.Namespace != "" {
+		apiItem.ResourceName = tenancy.ResourceName(item.Namespace, apiItem.Id)
+	}
+
+	// attributes are decoded from JSON, so every value converts
+	if item.Attributes != nil {
+		apiItem.Attributes, _ = structpb.NewStruct(item.Attributes)
+	}
+
+	return apiItem
+}
+
+// ItemFromAPI converts an API item to a store row. A zero ID leaves the row
+// unsaved so the database assigns one.
+func ItemFromAPI(item *types.Item) store.Item {
+	storeItem := store.Item{
+		Name:        item.Name,
+		Description: item.Description,
+		Labels:      item.Labels,
+		Kind:        item.Kind,
+	}
+	storeItem.ID = uint(item.Id)
+
+	if item.Attributes != nil {
+		storeItem.Attributes = item.Attributes.AsMap()
+	}
+
+	return storeItem
+}
+
+func EventTypeToAPI(eventType store.EventType) types.EventType {
+	return eventTypes[eventType]
+}
+
+// EventTypeFromAPI returns the store event type for t, or false if t is not a
+// concrete event type.
+func EventTypeFromAPI(t types.EventType) (store.EventType, bool) {
+	for storeType, apiType := range eventTypes {
+		if apiType == t {
+			return storeType, true
+		}
+	}
+
+	return "", false
+}
+
+// WebhookEventToAPI builds the payload delivered to webhooks for an outbox message.
+func WebhookEventToAPI(message *store.OutboxMessage) *types.WebhookEvent {
+	return &types.WebhookEvent{
+		Id:         message.ID,
+		Type:       EventTypeToAPI(message.Type),
+		Item:       ItemToAPI(&message.Object),
+		OccurredAt: timestamppb.New(message.CreatedAt),
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/skip-mev/platform-take-home/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo details attached to
+// store errors, whose reasons are the Reason constants.
+const ErrorDomain = "platform-take-home"
+
+const (
+	ReasonNotFound    = "NOT_FOUND"
+	ReasonConflict    = "CONFLICT"
+	ReasonUnavailable = "DATABASE_UNAVAILABLE"
+)
+
+// storeError hides the details of a failed store call from the client, except
+// for what the client can act on: that the record doesn't exist, that a
+// concurrent write won, or that the database is unavailable, which is worth
+// retrying later. Those carry an ErrorInfo detail.
+func storeError(err error, message string) error {
+	switch {
+	case errors.Is(err, store.ErrUnavailable):
+		return detailedError(codes.Unavailable, err.Error(), ReasonUnavailable)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return detailedError(codes.NotFound, message+": not found", ReasonNotFound)
+	case errors.Is(err, store.ErrConflict):
+		return detailedError(codes.Aborted, message+": "+store.ErrConflict.Error(), ReasonConflict)
+	case store.Transient(err):
+		return detailedError(codes.Unavailable, message+": "+store.ErrUnavailable.Error(), ReasonUnavailable)
+	}
+
+	return errors.New(message)
+}
+
+func detailedError(code codes.Code, message, reason string) error {
+	s, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain})
+	if err != nil {
+		return status.Error(code, message)
+	}
+
+	return s.Err()
+}
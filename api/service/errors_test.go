@@ -0,0 +1,51 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/skip-mev/platform-take-home/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func TestStoreError(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{name: "not found", err: gorm.ErrRecordNotFound, code: codes.NotFound, reason: ReasonNotFound},
+		{name: "conflict", err: fmt.Errorf("%w: %w", store.ErrConflict, &pgconn.PgError{Code: "40001"}), code: codes.Aborted, reason: ReasonConflict},
+		{name: "breaker open", err: store.ErrUnavailable, code: codes.Unavailable, reason: ReasonUnavailable},
+		{name: "connection reset", err: fmt.Errorf("query: %w", syscall.ECONNRESET), code: codes.Unavailable, reason: ReasonUnavailable},
+		{name: "connection lost", err: io.ErrUnexpectedEOF, code: codes.Unavailable, reason: ReasonUnavailable},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, code: codes.Unavailable, reason: ReasonUnavailable},
+		{name: "query canceled", err: &pgconn.PgError{Code: "57014"}, code: codes.Unknown},
+		{name: "other", err: errors.New("syntax error"), code: codes.Unknown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := status.Convert(storeError(tc.err, "failed to get item"))
+			if s.Code() != tc.code {
+				t.Fatalf("got code %v, want %v", s.Code(), tc.code)
+			}
+
+			var reason string
+			for _, detail := range s.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tc.reason {
+				t.Errorf("got reason %q, want %q", reason, tc.reason)
+			}
+		})
+	}
+}
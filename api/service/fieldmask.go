@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/store"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// validateItemMask checks that every path of mask names a top-level field of
+// Item. Nested paths such as "attributes.color" are rejected, since masks are
+// applied per column.
+func validateItemMask(mask *fieldmaskpb.FieldMask) error {
+	if !mask.IsValid(&types.Item{}) {
+		return fmt.Errorf("invalid field mask %q: paths must name Item fields", strings.Join(mask.GetPaths(), ","))
+	}
+
+	for _, path := range mask.GetPaths() {
+		if strings.Contains(path, ".") {
+			return fmt.Errorf("invalid field mask path %q: only top-level Item fields are supported", path)
+		}
+	}
+
+	return nil
+}
+
+// readMaskFields returns the store fields to load for a read mask, or nil to
+// load every field.
+func readMaskFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+
+	if err := validateItemMask(mask); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(mask.GetPaths()))
+
+	for _, path := range mask.GetPaths() {
+		switch path {
+		case "id":
+			// always loaded
+		case "resource_name":
+			fields = append(fields, store.FieldNamespace)
+		default:
+			fields = append(fields, path)
+		}
+	}
+
+	return fields, nil
+}
+
+// updateMaskFields returns the store fields an update mask overwrites, or nil
+// to overwrite all of them. Columns are written whole, so a nested path such
+// as "attributes.color", which the PATCH binding infers from a body, updates
+// its entire top-level field.
+func updateMaskFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if len(mask.GetPaths()) == 0 || slices.Equal(mask.GetPaths(), []string{"*"}) {
+		return nil, nil
+	}
+
+	topLevel := &fieldmaskpb.FieldMask{}
+	for _, path := range mask.GetPaths() {
+		field, _, _ := strings.Cut(path, ".")
+		if !slices.Contains(topLevel.Paths, field) {
+			topLevel.Paths = append(topLevel.Paths, field)
+		}
+	}
+
+	if err := validateItemMask(topLevel); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(topLevel.GetPaths()))
+
+	for _, path := range topLevel.GetPaths() {
+		// the PATCH binding infers a mask from the body, which may echo the ID
+		if path == "id" {
+			continue
+		}
+
+		if !slices.Contains(store.UpdatableFields, path) {
+			return nil, fmt.Errorf("invalid field mask path %q: field can't be updated", path)
+		}
+
+		fields = append(fields, path)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid field mask %q: no updatable fields", strings.Join(mask.GetPaths(), ","))
+	}
+
+	return fields, nil
+}
+
+// applyReadMask clears the fields of item that mask doesn't name. The ID is
+// always kept, so the item can still be addressed.
+func applyReadMask(item *types.Item, mask *fieldmaskpb.FieldMask) {
+	if len(mask.GetPaths()) == 0 {
+		return
+	}
+
+	message := item.ProtoReflect()
+
+	message.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if name := string(field.Name()); name != "id" && !slices.Contains(mask.GetPaths(), name) {
+			message.Clear(field)
+		}
+		return true
+	})
+}
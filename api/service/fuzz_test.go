@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// The fuzz targets decode each input as a request in the protobuf wire
+// format, as the gRPC server would, and call the handler with it. Whatever
+// the request, a handler must not panic, and must reject it with a status
+// that blames the request: an error without a status, which storeError
+// returns for unexpected store failures, is reported as a crash.
+
+func FuzzGetItems(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).GetItems,
+		&types.GetItemsRequest{},
+		&types.GetItemsRequest{LabelSelector: "env in (prod,dev),!deprecated", AttributeFilter: "size>=2,color=red"},
+		&types.GetItemsRequest{PageSize: 1, PageToken: pageToken(1), ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "labels"}}},
+	)
+}
+
+func FuzzGetItem(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).GetItem,
+		&types.GetItemRequest{Id: 1},
+		&types.GetItemRequest{Id: 1, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"attributes"}}},
+	)
+}
+
+func FuzzCreateItem(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).CreateItem,
+		&types.CreateItemRequest{},
+		&types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Labels: map[string]string{"env": "prod"}}},
+		&types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Kind: "gadget", Attributes: fuzzAttributes(f)}},
+	)
+}
+
+func FuzzSearchItems(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).SearchItems,
+		&types.SearchItemsRequest{Q: "kettle", Limit: 10},
+	)
+}
+
+func FuzzUpdateItem(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).UpdateItem,
+		&types.UpdateItemRequest{},
+		&types.UpdateItemRequest{Item: &types.Item{Id: 1, Name: "Teapot"}},
+		&types.UpdateItemRequest{Item: &types.Item{Id: 1, Labels: map[string]string{"env": "dev"}}, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}}},
+	)
+}
+
+func FuzzDeleteItem(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).DeleteItem,
+		&types.DeleteItemRequest{Id: 2},
+	)
+}
+
+func FuzzPutItemKind(f *testing.F) {
+	schema, err := structpb.NewStruct(map[string]interface{}{"type": "object", "required": []interface{}{"color"}})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	fuzzUnary(f, (*TakeHomeService).PutItemKind,
+		&types.PutItemKindRequest{},
+		&types.PutItemKindRequest{Kind: &types.ItemKind{Name: "gizmo", Schema: schema}},
+	)
+}
+
+func FuzzGetItemKinds(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).GetItemKinds, &types.EmptyRequest{})
+}
+
+func FuzzCreateWebhook(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).CreateWebhook,
+		&types.CreateWebhookRequest{},
+		&types.CreateWebhookRequest{Webhook: &types.Webhook{Url: "https://example.com/hook", EventTypes: []types.EventType{types.EventType_EVENT_TYPE_ADDED}}},
+	)
+}
+
+func FuzzGetWebhooks(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).GetWebhooks, &types.EmptyRequest{})
+}
+
+func FuzzDeleteWebhook(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).DeleteWebhook,
+		&types.DeleteWebhookRequest{Id: 2},
+	)
+}
+
+func FuzzGetWebhookDeliveries(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).GetWebhookDeliveries,
+		&types.GetWebhookDeliveriesRequest{WebhookId: 1, Status: types.DeliveryStatus_DELIVERY_STATUS_DEAD, Limit: 10},
+	)
+}
+
+func FuzzRedeliverWebhookDelivery(f *testing.F) {
+	fuzzUnary(f, (*TakeHomeService).RedeliverWebhookDelivery,
+		&types.RedeliverWebhookDeliveryRequest{Id: 1},
+		// beyond the int64 range SQLite compares IDs in
+		&types.RedeliverWebhookDeliveryRequest{Id: 1 << 63},
+	)
+}
+
+func FuzzImportItems(f *testing.F) {
+	fuzzStream(f, func(s *TakeHomeService, ctx context.Context, req *types.ImportItemsRequest) error {
+		return s.ImportItems(&fakeStream[types.ImportItemsRequest, types.ImportItemsResponse]{ctx: ctx, in: []*types.ImportItemsRequest{req, req}})
+	},
+		&types.ImportItemsRequest{},
+		&types.ImportItemsRequest{Item: &types.Item{Id: 100, Name: "Imported", Labels: map[string]string{"env": "prod"}}},
+	)
+}
+
+func FuzzExportItems(f *testing.F) {
+	fuzzStream(f, func(s *TakeHomeService, ctx context.Context, req *types.ExportItemsRequest) error {
+		return s.ExportItems(req, &fakeStream[types.ExportItemsRequest, types.ExportItemsResponse]{ctx: ctx})
+	},
+		&types.ExportItemsRequest{BatchSize: 1},
+	)
+}
+
+func FuzzWatchItems(f *testing.F) {
+	fuzzStream(f, func(s *TakeHomeService, ctx context.Context, req *types.WatchItemsRequest) error {
+		// a watch only ends with its client
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		err := s.WatchItems(req, &fakeStream[types.WatchItemsRequest, types.WatchItemsResponse]{ctx: ctx})
+		if status.Code(err) == codes.DeadlineExceeded {
+			return nil
+		}
+		return err
+	},
+		&types.WatchItemsRequest{},
+		&types.WatchItemsRequest{ResourceVersion: 1},
+		&types.WatchItemsRequest{ResourceVersion: 1 << 63},
+	)
+}
+
+func fuzzUnary[Req proto.Message, Resp any](f *testing.F, handler func(*TakeHomeService, context.Context, Req) (Resp, error), seeds ...Req) {
+	fuzzStream(f, func(s *TakeHomeService, ctx context.Context, req Req) error {
+		_, err := handler(s, ctx, req)
+		return err
+	}, seeds...)
+}
+
+// fuzzStream fuzzes call with requests decoded from the fuzzer's input, and
+// with seeds as the seed corpus.
+func fuzzStream[Req proto.Message](f *testing.F, call func(*TakeHomeService, context.Context, Req) error, seeds ...Req) {
+	s := newFuzzService(f)
+
+	for _, seed := range seeds {
+		data, err := proto.Marshal(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		req := seeds[0].ProtoReflect().New().Interface().(Req)
+		if err := proto.Unmarshal(data, req); err != nil {
+			return
+		}
+
+		ctx := tenancy.WithNamespace(fuzzContext(), tenancy.DefaultNamespace)
+
+		err := call(s, ctx, req)
+		if _, ok := status.FromError(err); !ok {
+			request, _ := protojson.Marshal(req)
+			t.Errorf("request %s: failed without a status: %v", request, err)
+		}
+	})
+}
+
+const fuzzAdminToken = "fuzz"
+
+// fuzzContext is the context of an administrator's call, so that the fuzz
+// targets get past requireAdmin.
+func fuzzContext() context.Context {
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+fuzzAdminToken))
+}
+
+// newFuzzService returns a service over an in-memory store holding an item,
+// a kind and a webhook with a delivery, so that requests can hit them.
+func newFuzzService(f *testing.F) *TakeHomeService {
+	dbStore, err := store.NewSQLiteBackedStore(store.SQLiteConfig{Path: store.InMemory})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Cleanup(func() { dbStore.Close() })
+
+	if err := dbStore.Migrate(); err != nil {
+		f.Fatal(err)
+	}
+
+	s := NewTakeHomeService(dbStore)
+	s.ConfigureAdmin(AdminConfig{Token: fuzzAdminToken})
+	ctx := fuzzContext()
+
+	schema, err := structpb.NewStruct(map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"color": map[string]interface{}{"type": "string"}, "size": map[string]interface{}{"type": "number"}},
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	if _, err := s.PutItemKind(ctx, &types.PutItemKindRequest{Kind: &types.ItemKind{Name: "gadget", Schema: schema}}); err != nil {
+		f.Fatal(err)
+	}
+
+	for _, item := range []*types.Item{
+		{Name: "Kettle", Labels: map[string]string{"env": "prod"}, Kind: "gadget", Attributes: fuzzAttributes(f)},
+		{Name: "Teapot", Description: "Brews tea"},
+	} {
+		if _, err := s.CreateItem(ctx, &types.CreateItemRequest{Item: item}); err != nil {
+			f.Fatal(err)
+		}
+	}
+
+	if _, err := s.CreateWebhook(ctx, &types.CreateWebhookRequest{Webhook: &types.Webhook{Url: "https://example.com/hook"}}); err != nil {
+		f.Fatal(err)
+	}
+
+	// fan the item events out into deliveries, without delivering them
+	if _, err := dbStore.DispatchOutbox(ctx, 100); err != nil {
+		f.Fatal(err)
+	}
+
+	return s
+}
+
+func fuzzAttributes(f *testing.F) *structpb.Struct {
+	attributes, err := structpb.NewStruct(map[string]interface{}{"color": "red", "size": 2})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	return attributes
+}
+
+// fakeStream feeds in to a streaming handler and discards what it sends.
+type fakeStream[Req, Resp any] struct {
+	grpc.ServerStream
+	ctx context.Context
+	in  []*Req
+}
+
+func (s *fakeStream[Req, Resp]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeStream[Req, Resp]) Recv() (*Req, error) {
+	if len(s.in) == 0 {
+		return nil, io.EOF
+	}
+
+	req := s.in[0]
+	s.in = s.in[1:]
+	return req, nil
+}
+
+func (s *fakeStream[Req, Resp]) Send(*Resp) error {
+	return nil
+}
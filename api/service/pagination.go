@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+const maxPageSize = 1000
+
+// pageToken encodes the ID of the last item of a page. Items are paged in ID
+// order, so the next page starts after it.
+func pageToken(lastID uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(lastID), 10)))
+}
+
+// parsePageToken returns the ID the page of token starts after, 0 for the
+// first page.
+func parsePageToken(token string) (uint, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errors.New("invalid page_token")
+	}
+
+	id, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("invalid page_token")
+	}
+
+	return uint(id), nil
+}
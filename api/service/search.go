@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// snippetWords is how many words of the description a snippet shows.
+	snippetWords = 16
+	// snippetScanBytes bounds how much of the description is searched for
+	// the match a snippet shows.
+	snippetScanBytes = 16 << 10
+)
+
+func (s *TakeHomeService) SearchItems(ctx context.Context, req *types.SearchItemsRequest) (*types.SearchItemsResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	results, err := s.store.SearchItems(ctx, req.Q, limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to search items", zap.Error(err))
+		return &types.SearchItemsResponse{Results: make([]*types.SearchResult, 0)}, storeError(err, "failed to search items")
+	}
+
+	terms := store.SearchTerms(req.Q)
+	apiResults := make([]*types.SearchResult, 0, len(results))
+
+	for _, result := range results {
+		apiResults = append(apiResults, &types.SearchResult{
+			Item:          ItemToAPI(&result.Item),
+			Score:         result.Score,
+			NameHighlight: highlight(result.Name, terms, 0),
+			Snippet:       highlight(result.Description, terms, snippetWords),
+		})
+	}
+
+	return &types.SearchItemsResponse{Results: apiResults}, nil
+}
+
+// highlight HTML-escapes text and wraps every word that starts with one of
+// terms in <mark> tags. If maxWords is positive, the output is trimmed to a
+// window of that many words around the first match within the first
+// snippetScanBytes of text.
+func highlight(text string, terms []string, maxWords int) string {
+	truncated := false
+	if maxWords > 0 && len(text) > snippetScanBytes {
+		cut := snippetScanBytes
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text, truncated = text[:cut], true
+	}
+
+	words := splitWords(text)
+
+	first := -1
+	for i, w := range words {
+		if w.isWord && matchesAny(w.text, terms) {
+			first = i
+			break
+		}
+	}
+
+	start, end := 0, len(words)
+	if maxWords > 0 {
+		start, end = window(words, first, maxWords)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+
+	for _, w := range words[start:end] {
+		if w.isWord && matchesAny(w.text, terms) {
+			b.WriteString("<mark>" + html.EscapeString(w.text) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(w.text))
+		}
+	}
+
+	if end < len(words) || truncated {
+		b.WriteString("…")
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+type segment struct {
+	text   string
+	isWord bool
+}
+
+// splitWords splits text into alternating runs of word and non-word runes,
+// using the same word definition as store.SearchTerms.
+func splitWords(text string) []segment {
+	var segments []segment
+
+	for len(text) > 0 {
+		r, _ := utf8.DecodeRuneInString(text)
+		isWord := isWordRune(r)
+		end := strings.IndexFunc(text, func(r rune) bool { return isWordRune(r) != isWord })
+		if end < 0 {
+			end = len(text)
+		}
+
+		segments = append(segments, segment{text: text[:end], isWord: isWord})
+		text = text[end:]
+	}
+
+	return segments
+}
+
+// window returns the segment range covering maxWords words, starting a few
+// words before the match at index first so it has some context.
+func window(segments []segment, first, maxWords int) (int, int) {
+	start := 0
+
+	if first > 0 {
+		before := 0
+		for start = first; start > 0 && before < maxWords/4; start-- {
+			if segments[start-1].isWord {
+				before++
+			}
+		}
+	}
+
+	words := 0
+	end := start
+	for ; end < len(segments) && words < maxWords; end++ {
+		if segments[end].isWord {
+			words++
+		}
+	}
+
+	return start, end
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+func matchesAny(word string, terms []string) bool {
+	word = strings.ToLower(word)
+
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+
+	return false
+}
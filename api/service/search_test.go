@@ -0,0 +1,76 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHighlight(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		text     string
+		terms    []string
+		maxWords int
+		want     string
+	}{
+		{name: "prefix", text: "Electric kettle, boils water", terms: []string{"kett"}, want: "Electric <mark>kettle</mark>, boils water"},
+		{name: "case insensitive", text: "KETTLE", terms: []string{"kettle"}, want: "<mark>KETTLE</mark>"},
+		{name: "several terms", text: "red road bike", terms: []string{"road", "bike"}, want: "red <mark>road</mark> <mark>bike</mark>"},
+		{name: "not inside words", text: "bikes and motorbikes", terms: []string{"bike"}, want: "<mark>bikes</mark> and motorbikes"},
+		{name: "escaped", text: "<b>kettle</b> & co", terms: []string{"kettle"}, want: "&lt;b&gt;<mark>kettle</mark>&lt;/b&gt; &amp; co"},
+		{name: "unicode", text: "Crème brûlée torch", terms: []string{"brûl"}, want: "Crème <mark>brûlée</mark> torch"},
+		{name: "no match", text: "a kettle", terms: []string{"toaster"}, want: "a kettle"},
+		{name: "empty", text: "", terms: []string{"kettle"}, want: ""},
+		{
+			name:     "window around the match",
+			text:     "one two three four five six seven eight kettle nine ten eleven twelve",
+			terms:    []string{"kettle"},
+			maxWords: 4,
+			want:     "…eight <mark>kettle</mark> nine ten…",
+		},
+		{
+			name:     "window without a match",
+			text:     "one two three four five six",
+			terms:    []string{"kettle"},
+			maxWords: 3,
+			want:     "one two three…",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := highlight(tc.text, tc.terms, tc.maxWords); got != tc.want {
+				t.Errorf("highlight(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHighlightLongDescription(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 100000) + "kettle"
+
+	start := time.Now()
+	snippet := highlight(text, []string{"kettle"}, snippetWords)
+	name := highlight(text, []string{"kettle"}, 0)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("highlighting took %s", elapsed)
+	}
+
+	// the match lies beyond the scanned prefix
+	if want := strings.Repeat("lorem ipsum ", snippetWords/2)[:len("lorem ipsum ")*snippetWords/2-1] + "…"; snippet != want {
+		t.Errorf("snippet = %q, want %q", snippet, want)
+	}
+
+	if !strings.HasSuffix(name, "<mark>kettle</mark>") {
+		t.Errorf("highlight without a window lost the match: …%s", name[len(name)-40:])
+	}
+}
+
+func TestHighlightScanCutsAtRunes(t *testing.T) {
+	// a multi-byte rune straddles the scan limit
+	text := strings.Repeat("a", snippetScanBytes-1) + "é kettle"
+
+	snippet := highlight(text, []string{"kettle"}, snippetWords)
+	if !strings.HasSuffix(snippet, "a…") || !strings.HasPrefix(snippet, "aaa") {
+		t.Errorf("snippet = %.20q…%q", snippet, snippet[len(snippet)-10:])
+	}
+}
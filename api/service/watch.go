@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchItems streams item changes until the client goes away. Clients resume
+// by passing the resource_version of the last event they processed; if that
+// version has been compacted they get OutOfRange and should re-list.
+func (s *TakeHomeService) WatchItems(req *types.WatchItemsRequest, stream types.TakeHomeService_WatchItemsServer) error {
+	ctx := stream.Context()
+
+	err := s.store.WatchItems(ctx, req.ResourceVersion, func(event *store.ItemEvent) error {
+		return stream.Send(&types.WatchItemsResponse{
+			Type:            EventTypeToAPI(event.Type),
+			Item:            ItemToAPI(&event.Object),
+			ResourceVersion: event.ID,
+		})
+	})
+
+	switch {
+	case ctx.Err() != nil:
+		return status.FromContextError(ctx.Err()).Err()
+	case errors.Is(err, store.ErrResourceVersionTooOld):
+		return status.Errorf(codes.OutOfRange, "resource version %d is too old, re-list and watch again", req.ResourceVersion)
+	case err != nil:
+		logging.FromContext(ctx).Error("failed to watch items", zap.Error(err))
+		return storeError(err, "failed to watch items")
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultDeliveriesLimit = 100
+	maxDeliveriesLimit     = 1000
+)
+
+// WebhookConfig restricts where webhooks are delivered.
+type WebhookConfig struct {
+	// AllowLocal permits webhooks to loopback, link-local and unspecified
+	// addresses, which are refused by default so that webhooks can't reach
+	// services on the server's host or a cloud metadata endpoint.
+	AllowLocal bool
+	// AllowPrivate permits webhooks to private addresses, i.e. the RFC 1918
+	// ranges and IPv6 unique local addresses, which are refused by default so
+	// that webhooks can't reach services on the server's internal network.
+	AllowPrivate bool
+}
+
+// WebhookConfigFromEnv reads WEBHOOK_ALLOW_LOCAL and WEBHOOK_ALLOW_PRIVATE,
+// both bools.
+func WebhookConfigFromEnv() (WebhookConfig, error) {
+	var config WebhookConfig
+
+	for name, value := range map[string]*bool{
+		"WEBHOOK_ALLOW_LOCAL":   &config.AllowLocal,
+		"WEBHOOK_ALLOW_PRIVATE": &config.AllowPrivate,
+	} {
+		if env := os.Getenv(name); env != "" {
+			var err error
+			if *value, err = strconv.ParseBool(env); err != nil {
+				return config, fmt.Errorf("invalid %s %q", name, env)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// CheckURL checks that a webhook may be delivered to endpoint: an absolute
+// http or https URL whose host, if it's an address or localhost, is allowed
+// by CheckAddress. Other hosts are checked once resolved, when delivering.
+func (c WebhookConfig) CheckURL(endpoint *url.URL) error {
+	if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Hostname() == "" {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(endpoint.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return c.CheckAddress(netip.IPv6Loopback())
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return c.CheckAddress(addr)
+	}
+
+	return nil
+}
+
+// CheckAddress refuses loopback, link-local and unspecified addresses unless
+// AllowLocal is set, and private ones unless AllowPrivate is.
+func (c WebhookConfig) CheckAddress(addr netip.Addr) error {
+	addr = addr.Unmap()
+
+	switch {
+	case !c.AllowLocal && (addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsUnspecified()):
+		return fmt.Errorf("webhooks may not be delivered to local address %s", addr)
+	case !c.AllowPrivate && addr.IsPrivate():
+		return fmt.Errorf("webhooks may not be delivered to private address %s", addr)
+	}
+
+	return nil
+}
+
+// ConfigureWebhooks sets where CreateWebhook accepts webhooks to; by default
+// local and private addresses are refused.
+func (s *TakeHomeService) ConfigureWebhooks(config WebhookConfig) {
+	s.webhooks = config
+}
+
+func (s *TakeHomeService) CreateWebhook(ctx context.Context, req *types.CreateWebhookRequest) (*types.CreateWebhookResponse, error) {
+	if req.Webhook == nil {
+		return &types.CreateWebhookResponse{}, status.Error(codes.InvalidArgument, "webhook is required")
+	}
+
+	endpoint, err := url.Parse(req.Webhook.Url)
+	if err != nil {
+		return &types.CreateWebhookResponse{}, status.Error(codes.InvalidArgument, "webhook url must be an absolute http or https url")
+	}
+
+	if err := s.webhooks.CheckURL(endpoint); err != nil {
+		return &types.CreateWebhookResponse{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	eventTypes := make([]string, 0, len(req.Webhook.EventTypes))
+	for _, t := range req.Webhook.EventTypes {
+		eventType, ok := EventTypeFromAPI(t)
+		if !ok {
+			return &types.CreateWebhookResponse{}, status.Errorf(codes.InvalidArgument, "invalid event type %s", t)
+		}
+		eventTypes = append(eventTypes, string(eventType))
+	}
+
+	secret := req.Webhook.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return &types.CreateWebhookResponse{}, fmt.Errorf("failed to generate webhook secret")
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	webhook := store.Webhook{
+		URL:        endpoint.String(),
+		Secret:     secret,
+		EventTypes: strings.Join(eventTypes, ","),
+	}
+
+	if err := s.store.CreateWebhook(ctx, &webhook); err != nil {
+		logging.FromContext(ctx).Error("failed to create webhook", zap.Error(err))
+		return &types.CreateWebhookResponse{}, storeError(err, "failed to create webhook")
+	}
+
+	apiWebhook := webhookToAPI(&webhook)
+	apiWebhook.Secret = webhook.Secret
+
+	return &types.CreateWebhookResponse{Webhook: apiWebhook}, nil
+}
+
+func (s *TakeHomeService) GetWebhooks(ctx context.Context, _ *types.EmptyRequest) (*types.GetWebhooksResponse, error) {
+	webhooks, err := s.store.GetWebhooks(ctx)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to retrieve webhooks", zap.Error(err))
+		return &types.GetWebhooksResponse{Webhooks: make([]*types.Webhook, 0)}, storeError(err, "failed to retrieve webhooks")
+	}
+
+	apiWebhooks := make([]*types.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		apiWebhooks = append(apiWebhooks, webhookToAPI(&webhook))
+	}
+
+	return &types.GetWebhooksResponse{Webhooks: apiWebhooks}, nil
+}
+
+func (s *TakeHomeService) DeleteWebhook(ctx context.Context, req *types.DeleteWebhookRequest) (*types.DeleteWebhookResponse, error) {
+	if err := s.store.DeleteWebhook(ctx, uint(req.Id)); err != nil {
+		logging.FromContext(ctx).Error("failed to delete webhook", zap.Error(err))
+		return &types.DeleteWebhookResponse{}, storeError(err, "failed to delete webhook")
+	}
+
+	return &types.DeleteWebhookResponse{}, nil
+}
+
+func (s *TakeHomeService) GetWebhookDeliveries(ctx context.Context, req *types.GetWebhookDeliveriesRequest) (*types.GetWebhookDeliveriesResponse, error) {
+	var status store.DeliveryStatus
+	if req.Status != types.DeliveryStatus_DELIVERY_STATUS_UNSPECIFIED {
+		for storeStatus, apiStatus := range deliveryStatuses {
+			if apiStatus == req.Status {
+				status = storeStatus
+			}
+		}
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+	limit = min(limit, maxDeliveriesLimit)
+
+	deliveries, err := s.store.GetWebhookDeliveries(ctx, uint(req.WebhookId), status, limit)
+
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to retrieve webhook deliveries", zap.Error(err))
+		return &types.GetWebhookDeliveriesResponse{Deliveries: make([]*types.WebhookDelivery, 0)}, storeError(err, "failed to retrieve webhook deliveries")
+	}
+
+	apiDeliveries := make([]*types.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		apiDelivery := &types.WebhookDelivery{
+			Id:            delivery.ID,
+			WebhookId:     uint64(delivery.WebhookID),
+			Event:         WebhookEventToAPI(&delivery.OutboxMessage),
+			Status:        deliveryStatuses[delivery.Status],
+			Attempts:      uint32(delivery.Attempts),
+			LastError:     delivery.LastError,
+			NextAttemptAt: timestamppb.New(delivery.NextAttemptAt),
+		}
+
+		if delivery.DeliveredAt != nil {
+			apiDelivery.DeliveredAt = timestamppb.New(*delivery.DeliveredAt)
+		}
+
+		apiDeliveries = append(apiDeliveries, apiDelivery)
+	}
+
+	return &types.GetWebhookDeliveriesResponse{Deliveries: apiDeliveries}, nil
+}
+
+func (s *TakeHomeService) RedeliverWebhookDelivery(ctx context.Context, req *types.RedeliverWebhookDeliveryRequest) (*types.RedeliverWebhookDeliveryResponse, error) {
+	if err := s.store.RedeliverWebhookDelivery(ctx, req.Id); err != nil {
+		logging.FromContext(ctx).Error("failed to redeliver webhook delivery", zap.Error(err))
+		return &types.RedeliverWebhookDeliveryResponse{}, storeError(err, "failed to redeliver webhook delivery")
+	}
+
+	return &types.RedeliverWebhookDeliveryResponse{}, nil
+}
+
+// webhookToAPI converts a webhook without its secret, which is only ever
+// returned on creation.
+func webhookToAPI(webhook *store.Webhook) *types.Webhook {
+	apiWebhook := &types.Webhook{
+		Id:         uint64(webhook.ID),
+		Url:        webhook.URL,
+		EventTypes: make([]types.EventType, 0),
+	}
+
+	if webhook.EventTypes != "" {
+		for _, t := range strings.Split(webhook.EventTypes, ",") {
+			apiWebhook.EventTypes = append(apiWebhook.EventTypes, EventTypeToAPI(store.EventType(t)))
+		}
+	}
+
+	return apiWebhook
+}
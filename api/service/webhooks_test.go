@@ -0,0 +1,71 @@
+package service
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestCheckAddress(t *testing.T) {
+	for _, tc := range []struct {
+		addr    string
+		refused string
+	}{
+		{addr: "93.184.215.14"},
+		{addr: "2606:2800:21f:cb07:6820:80da:af6b:8b2c"},
+		{addr: "127.0.0.1", refused: "local"},
+		{addr: "::1", refused: "local"},
+		{addr: "::ffff:127.0.0.1", refused: "local"},
+		{addr: "169.254.169.254", refused: "local"},
+		{addr: "fe80::1", refused: "local"},
+		{addr: "0.0.0.0", refused: "local"},
+		{addr: "10.1.2.3", refused: "private"},
+		{addr: "172.16.0.1", refused: "private"},
+		{addr: "172.31.255.255", refused: "private"},
+		{addr: "192.168.1.1", refused: "private"},
+		{addr: "::ffff:192.168.1.1", refused: "private"},
+		{addr: "fd00::1", refused: "private"},
+		{addr: "172.32.0.1"},
+		{addr: "100.64.0.1"},
+	} {
+		t.Run(tc.addr, func(t *testing.T) {
+			addr := netip.MustParseAddr(tc.addr)
+
+			err := WebhookConfig{}.CheckAddress(addr)
+			if tc.refused == "" && err != nil {
+				t.Errorf("CheckAddress() = %v, want it allowed", err)
+			}
+			if tc.refused != "" && (err == nil || !strings.Contains(err.Error(), tc.refused+" address")) {
+				t.Errorf("CheckAddress() = %v, want it refused as %s", err, tc.refused)
+			}
+
+			// each kind of address is allowed by its own setting only
+			config := WebhookConfig{AllowLocal: tc.refused == "local", AllowPrivate: tc.refused == "private"}
+			if err := config.CheckAddress(addr); err != nil {
+				t.Errorf("CheckAddress() with %+v = %v, want it allowed", config, err)
+			}
+			config.AllowLocal, config.AllowPrivate = config.AllowPrivate, config.AllowLocal
+			if err := config.CheckAddress(addr); (err == nil) != (tc.refused == "") {
+				t.Errorf("CheckAddress() with %+v = %v", config, err)
+			}
+		})
+	}
+}
+
+func TestWebhookConfigFromEnv(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_LOCAL", "")
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "true")
+
+	config, err := WebhookConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != (WebhookConfig{AllowPrivate: true}) {
+		t.Errorf("got %+v, want only private addresses allowed", config)
+	}
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "maybe")
+	if _, err := WebhookConfigFromEnv(); err == nil {
+		t.Error("WebhookConfigFromEnv() with an invalid bool: no error")
+	}
+}
@@ -19,18 +19,44 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TakeHomeService_GetItems_FullMethodName   = "/skip.platform.api.TakeHomeService/GetItems"
-	TakeHomeService_GetItem_FullMethodName    = "/skip.platform.api.TakeHomeService/GetItem"
-	TakeHomeService_CreateItem_FullMethodName = "/skip.platform.api.TakeHomeService/CreateItem"
+	TakeHomeService_GetItems_FullMethodName                 = "/skip.platform.api.TakeHomeService/GetItems"
+	TakeHomeService_GetItem_FullMethodName                  = "/skip.platform.api.TakeHomeService/GetItem"
+	TakeHomeService_CreateItem_FullMethodName               = "/skip.platform.api.TakeHomeService/CreateItem"
+	TakeHomeService_SearchItems_FullMethodName              = "/skip.platform.api.TakeHomeService/SearchItems"
+	TakeHomeService_UpdateItem_FullMethodName               = "/skip.platform.api.TakeHomeService/UpdateItem"
+	TakeHomeService_DeleteItem_FullMethodName               = "/skip.platform.api.TakeHomeService/DeleteItem"
+	TakeHomeService_WatchItems_FullMethodName               = "/skip.platform.api.TakeHomeService/WatchItems"
+	TakeHomeService_ImportItems_FullMethodName              = "/skip.platform.api.TakeHomeService/ImportItems"
+	TakeHomeService_ExportItems_FullMethodName              = "/skip.platform.api.TakeHomeService/ExportItems"
+	TakeHomeService_PutItemKind_FullMethodName              = "/skip.platform.api.TakeHomeService/PutItemKind"
+	TakeHomeService_GetItemKinds_FullMethodName             = "/skip.platform.api.TakeHomeService/GetItemKinds"
+	TakeHomeService_CreateWebhook_FullMethodName            = "/skip.platform.api.TakeHomeService/CreateWebhook"
+	TakeHomeService_GetWebhooks_FullMethodName              = "/skip.platform.api.TakeHomeService/GetWebhooks"
+	TakeHomeService_DeleteWebhook_FullMethodName            = "/skip.platform.api.TakeHomeService/DeleteWebhook"
+	TakeHomeService_GetWebhookDeliveries_FullMethodName     = "/skip.platform.api.TakeHomeService/GetWebhookDeliveries"
+	TakeHomeService_RedeliverWebhookDelivery_FullMethodName = "/skip.platform.api.TakeHomeService/RedeliverWebhookDelivery"
 )
 
 // TakeHomeServiceClient is the client API for TakeHomeService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TakeHomeServiceClient interface {
-	GetItems(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetItemsResponse, error)
+	GetItems(ctx context.Context, in *GetItemsRequest, opts ...grpc.CallOption) (*GetItemsResponse, error)
 	GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error)
 	CreateItem(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*CreateItemResponse, error)
+	SearchItems(ctx context.Context, in *SearchItemsRequest, opts ...grpc.CallOption) (*SearchItemsResponse, error)
+	UpdateItem(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*UpdateItemResponse, error)
+	DeleteItem(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	WatchItems(ctx context.Context, in *WatchItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchItemsResponse], error)
+	ImportItems(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportItemsRequest, ImportItemsResponse], error)
+	ExportItems(ctx context.Context, in *ExportItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportItemsResponse], error)
+	PutItemKind(ctx context.Context, in *PutItemKindRequest, opts ...grpc.CallOption) (*PutItemKindResponse, error)
+	GetItemKinds(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetItemKindsResponse, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	GetWebhooks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error)
+	RedeliverWebhookDelivery(ctx context.Context, in *RedeliverWebhookDeliveryRequest, opts ...grpc.CallOption) (*RedeliverWebhookDeliveryResponse, error)
 }
 
 type takeHomeServiceClient struct {
@@ -41,7 +67,7 @@ func NewTakeHomeServiceClient(cc grpc.ClientConnInterface) TakeHomeServiceClient
 	return &takeHomeServiceClient{cc}
 }
 
-func (c *takeHomeServiceClient) GetItems(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetItemsResponse, error) {
+func (c *takeHomeServiceClient) GetItems(ctx context.Context, in *GetItemsRequest, opts ...grpc.CallOption) (*GetItemsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetItemsResponse)
 	err := c.cc.Invoke(ctx, TakeHomeService_GetItems_FullMethodName, in, out, cOpts...)
@@ -71,13 +97,177 @@ func (c *takeHomeServiceClient) CreateItem(ctx context.Context, in *CreateItemRe
 	return out, nil
 }
 
+func (c *takeHomeServiceClient) SearchItems(ctx context.Context, in *SearchItemsRequest, opts ...grpc.CallOption) (*SearchItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchItemsResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_SearchItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) UpdateItem(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*UpdateItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateItemResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_UpdateItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) DeleteItem(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteItemResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_DeleteItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) WatchItems(ctx context.Context, in *WatchItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchItemsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TakeHomeService_ServiceDesc.Streams[0], TakeHomeService_WatchItems_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchItemsRequest, WatchItemsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TakeHomeService_WatchItemsClient = grpc.ServerStreamingClient[WatchItemsResponse]
+
+func (c *takeHomeServiceClient) ImportItems(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ImportItemsRequest, ImportItemsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TakeHomeService_ServiceDesc.Streams[1], TakeHomeService_ImportItems_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportItemsRequest, ImportItemsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TakeHomeService_ImportItemsClient = grpc.BidiStreamingClient[ImportItemsRequest, ImportItemsResponse]
+
+func (c *takeHomeServiceClient) ExportItems(ctx context.Context, in *ExportItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportItemsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TakeHomeService_ServiceDesc.Streams[2], TakeHomeService_ExportItems_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportItemsRequest, ExportItemsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TakeHomeService_ExportItemsClient = grpc.ServerStreamingClient[ExportItemsResponse]
+
+func (c *takeHomeServiceClient) PutItemKind(ctx context.Context, in *PutItemKindRequest, opts ...grpc.CallOption) (*PutItemKindResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutItemKindResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_PutItemKind_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) GetItemKinds(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetItemKindsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetItemKindsResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_GetItemKinds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) GetWebhooks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebhooksResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_GetWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_GetWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *takeHomeServiceClient) RedeliverWebhookDelivery(ctx context.Context, in *RedeliverWebhookDeliveryRequest, opts ...grpc.CallOption) (*RedeliverWebhookDeliveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedeliverWebhookDeliveryResponse)
+	err := c.cc.Invoke(ctx, TakeHomeService_RedeliverWebhookDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TakeHomeServiceServer is the server API for TakeHomeService service.
 // All implementations must embed UnimplementedTakeHomeServiceServer
 // for forward compatibility.
 type TakeHomeServiceServer interface {
-	GetItems(context.Context, *EmptyRequest) (*GetItemsResponse, error)
+	GetItems(context.Context, *GetItemsRequest) (*GetItemsResponse, error)
 	GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error)
 	CreateItem(context.Context, *CreateItemRequest) (*CreateItemResponse, error)
+	SearchItems(context.Context, *SearchItemsRequest) (*SearchItemsResponse, error)
+	UpdateItem(context.Context, *UpdateItemRequest) (*UpdateItemResponse, error)
+	DeleteItem(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	WatchItems(*WatchItemsRequest, grpc.ServerStreamingServer[WatchItemsResponse]) error
+	ImportItems(grpc.BidiStreamingServer[ImportItemsRequest, ImportItemsResponse]) error
+	ExportItems(*ExportItemsRequest, grpc.ServerStreamingServer[ExportItemsResponse]) error
+	PutItemKind(context.Context, *PutItemKindRequest) (*PutItemKindResponse, error)
+	GetItemKinds(context.Context, *EmptyRequest) (*GetItemKindsResponse, error)
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	GetWebhooks(context.Context, *EmptyRequest) (*GetWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error)
+	RedeliverWebhookDelivery(context.Context, *RedeliverWebhookDeliveryRequest) (*RedeliverWebhookDeliveryResponse, error)
 	mustEmbedUnimplementedTakeHomeServiceServer()
 }
 
@@ -88,7 +278,7 @@ type TakeHomeServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedTakeHomeServiceServer struct{}
 
-func (UnimplementedTakeHomeServiceServer) GetItems(context.Context, *EmptyRequest) (*GetItemsResponse, error) {
+func (UnimplementedTakeHomeServiceServer) GetItems(context.Context, *GetItemsRequest) (*GetItemsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetItems not implemented")
 }
 func (UnimplementedTakeHomeServiceServer) GetItem(context.Context, *GetItemRequest) (*GetItemResponse, error) {
@@ -97,6 +287,45 @@ func (UnimplementedTakeHomeServiceServer) GetItem(context.Context, *GetItemReque
 func (UnimplementedTakeHomeServiceServer) CreateItem(context.Context, *CreateItemRequest) (*CreateItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateItem not implemented")
 }
+func (UnimplementedTakeHomeServiceServer) SearchItems(context.Context, *SearchItemsRequest) (*SearchItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchItems not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) UpdateItem(context.Context, *UpdateItemRequest) (*UpdateItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateItem not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) DeleteItem(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteItem not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) WatchItems(*WatchItemsRequest, grpc.ServerStreamingServer[WatchItemsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchItems not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) ImportItems(grpc.BidiStreamingServer[ImportItemsRequest, ImportItemsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportItems not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) ExportItems(*ExportItemsRequest, grpc.ServerStreamingServer[ExportItemsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportItems not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) PutItemKind(context.Context, *PutItemKindRequest) (*PutItemKindResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutItemKind not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) GetItemKinds(context.Context, *EmptyRequest) (*GetItemKindsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItemKinds not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) GetWebhooks(context.Context, *EmptyRequest) (*GetWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhooks not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) GetWebhookDeliveries(context.Context, *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookDeliveries not implemented")
+}
+func (UnimplementedTakeHomeServiceServer) RedeliverWebhookDelivery(context.Context, *RedeliverWebhookDeliveryRequest) (*RedeliverWebhookDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhookDelivery not implemented")
+}
 func (UnimplementedTakeHomeServiceServer) mustEmbedUnimplementedTakeHomeServiceServer() {}
 func (UnimplementedTakeHomeServiceServer) testEmbeddedByValue()                         {}
 
@@ -119,7 +348,7 @@ func RegisterTakeHomeServiceServer(s grpc.ServiceRegistrar, srv TakeHomeServiceS
 }
 
 func _TakeHomeService_GetItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyRequest)
+	in := new(GetItemsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -131,7 +360,7 @@ func _TakeHomeService_GetItems_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: TakeHomeService_GetItems_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TakeHomeServiceServer).GetItems(ctx, req.(*EmptyRequest))
+		return srv.(TakeHomeServiceServer).GetItems(ctx, req.(*GetItemsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -172,6 +401,215 @@ func _TakeHomeService_CreateItem_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TakeHomeService_SearchItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).SearchItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_SearchItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).SearchItems(ctx, req.(*SearchItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_UpdateItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).UpdateItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_UpdateItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).UpdateItem(ctx, req.(*UpdateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_DeleteItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).DeleteItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_DeleteItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).DeleteItem(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_WatchItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TakeHomeServiceServer).WatchItems(m, &grpc.GenericServerStream[WatchItemsRequest, WatchItemsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TakeHomeService_WatchItemsServer = grpc.ServerStreamingServer[WatchItemsResponse]
+
+func _TakeHomeService_ImportItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TakeHomeServiceServer).ImportItems(&grpc.GenericServerStream[ImportItemsRequest, ImportItemsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TakeHomeService_ImportItemsServer = grpc.BidiStreamingServer[ImportItemsRequest, ImportItemsResponse]
+
+func _TakeHomeService_ExportItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TakeHomeServiceServer).ExportItems(m, &grpc.GenericServerStream[ExportItemsRequest, ExportItemsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TakeHomeService_ExportItemsServer = grpc.ServerStreamingServer[ExportItemsResponse]
+
+func _TakeHomeService_PutItemKind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutItemKindRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).PutItemKind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_PutItemKind_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).PutItemKind(ctx, req.(*PutItemKindRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_GetItemKinds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).GetItemKinds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_GetItemKinds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).GetItemKinds(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_CreateWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_GetWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).GetWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_GetWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).GetWebhooks(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_DeleteWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_GetWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).GetWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_GetWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).GetWebhookDeliveries(ctx, req.(*GetWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TakeHomeService_RedeliverWebhookDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TakeHomeServiceServer).RedeliverWebhookDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TakeHomeService_RedeliverWebhookDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TakeHomeServiceServer).RedeliverWebhookDelivery(ctx, req.(*RedeliverWebhookDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TakeHomeService_ServiceDesc is the grpc.ServiceDesc for TakeHomeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,7 +629,64 @@ var TakeHomeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateItem",
 			Handler:    _TakeHomeService_CreateItem_Handler,
 		},
+		{
+			MethodName: "SearchItems",
+			Handler:    _TakeHomeService_SearchItems_Handler,
+		},
+		{
+			MethodName: "UpdateItem",
+			Handler:    _TakeHomeService_UpdateItem_Handler,
+		},
+		{
+			MethodName: "DeleteItem",
+			Handler:    _TakeHomeService_DeleteItem_Handler,
+		},
+		{
+			MethodName: "PutItemKind",
+			Handler:    _TakeHomeService_PutItemKind_Handler,
+		},
+		{
+			MethodName: "GetItemKinds",
+			Handler:    _TakeHomeService_GetItemKinds_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _TakeHomeService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhooks",
+			Handler:    _TakeHomeService_GetWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _TakeHomeService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "GetWebhookDeliveries",
+			Handler:    _TakeHomeService_GetWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhookDelivery",
+			Handler:    _TakeHomeService_RedeliverWebhookDelivery_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchItems",
+			Handler:       _TakeHomeService_WatchItems_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportItems",
+			Handler:       _TakeHomeService_ImportItems_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportItems",
+			Handler:       _TakeHomeService_ExportItems_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/api.proto",
 }
@@ -9,6 +9,7 @@ import (
 
 	gomock "go.uber.org/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockTakeHomeServiceClient is a mock of TakeHomeServiceClient interface.
@@ -54,44 +55,1059 @@ func (mr *MockTakeHomeServiceClientMockRecorder) CreateItem(ctx, in interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).CreateItem), varargs...)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockTakeHomeServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateWebhook", varargs...)
+	ret0, _ := ret[0].(*CreateWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockTakeHomeServiceClientMockRecorder) CreateWebhook(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).CreateWebhook), varargs...)
+}
+
+// DeleteItem mocks base method.
+func (m *MockTakeHomeServiceClient) DeleteItem(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteItem", varargs...)
+	ret0, _ := ret[0].(*DeleteItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockTakeHomeServiceClientMockRecorder) DeleteItem(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).DeleteItem), varargs...)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockTakeHomeServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteWebhook", varargs...)
+	ret0, _ := ret[0].(*DeleteWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockTakeHomeServiceClientMockRecorder) DeleteWebhook(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).DeleteWebhook), varargs...)
+}
+
+// ExportItems mocks base method.
+func (m *MockTakeHomeServiceClient) ExportItems(ctx context.Context, in *ExportItemsRequest, opts ...grpc.CallOption) (TakeHomeService_ExportItemsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportItems", varargs...)
+	ret0, _ := ret[0].(TakeHomeService_ExportItemsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportItems indicates an expected call of ExportItems.
+func (mr *MockTakeHomeServiceClientMockRecorder) ExportItems(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportItems", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).ExportItems), varargs...)
+}
+
 // GetItem mocks base method.
 func (m *MockTakeHomeServiceClient) GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*GetItemResponse, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, in}
-	for _, a := range opts {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "GetItem", varargs...)
-	ret0, _ := ret[0].(*GetItemResponse)
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetItem", varargs...)
+	ret0, _ := ret[0].(*GetItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockTakeHomeServiceClientMockRecorder) GetItem(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetItem), varargs...)
+}
+
+// GetItemKinds mocks base method.
+func (m *MockTakeHomeServiceClient) GetItemKinds(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetItemKindsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetItemKinds", varargs...)
+	ret0, _ := ret[0].(*GetItemKindsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemKinds indicates an expected call of GetItemKinds.
+func (mr *MockTakeHomeServiceClientMockRecorder) GetItemKinds(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemKinds", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetItemKinds), varargs...)
+}
+
+// GetItems mocks base method.
+func (m *MockTakeHomeServiceClient) GetItems(ctx context.Context, in *GetItemsRequest, opts ...grpc.CallOption) (*GetItemsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetItems", varargs...)
+	ret0, _ := ret[0].(*GetItemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockTakeHomeServiceClientMockRecorder) GetItems(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetItems), varargs...)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockTakeHomeServiceClient) GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest, opts ...grpc.CallOption) (*GetWebhookDeliveriesResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", varargs...)
+	ret0, _ := ret[0].(*GetWebhookDeliveriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockTakeHomeServiceClientMockRecorder) GetWebhookDeliveries(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetWebhookDeliveries), varargs...)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockTakeHomeServiceClient) GetWebhooks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetWebhooksResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetWebhooks", varargs...)
+	ret0, _ := ret[0].(*GetWebhooksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockTakeHomeServiceClientMockRecorder) GetWebhooks(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetWebhooks), varargs...)
+}
+
+// ImportItems mocks base method.
+func (m *MockTakeHomeServiceClient) ImportItems(ctx context.Context, opts ...grpc.CallOption) (TakeHomeService_ImportItemsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ImportItems", varargs...)
+	ret0, _ := ret[0].(TakeHomeService_ImportItemsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportItems indicates an expected call of ImportItems.
+func (mr *MockTakeHomeServiceClientMockRecorder) ImportItems(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportItems", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).ImportItems), varargs...)
+}
+
+// PutItemKind mocks base method.
+func (m *MockTakeHomeServiceClient) PutItemKind(ctx context.Context, in *PutItemKindRequest, opts ...grpc.CallOption) (*PutItemKindResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PutItemKind", varargs...)
+	ret0, _ := ret[0].(*PutItemKindResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutItemKind indicates an expected call of PutItemKind.
+func (mr *MockTakeHomeServiceClientMockRecorder) PutItemKind(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutItemKind", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).PutItemKind), varargs...)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockTakeHomeServiceClient) RedeliverWebhookDelivery(ctx context.Context, in *RedeliverWebhookDeliveryRequest, opts ...grpc.CallOption) (*RedeliverWebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", varargs...)
+	ret0, _ := ret[0].(*RedeliverWebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockTakeHomeServiceClientMockRecorder) RedeliverWebhookDelivery(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).RedeliverWebhookDelivery), varargs...)
+}
+
+// SearchItems mocks base method.
+func (m *MockTakeHomeServiceClient) SearchItems(ctx context.Context, in *SearchItemsRequest, opts ...grpc.CallOption) (*SearchItemsResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SearchItems", varargs...)
+	ret0, _ := ret[0].(*SearchItemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchItems indicates an expected call of SearchItems.
+func (mr *MockTakeHomeServiceClientMockRecorder) SearchItems(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchItems", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).SearchItems), varargs...)
+}
+
+// UpdateItem mocks base method.
+func (m *MockTakeHomeServiceClient) UpdateItem(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*UpdateItemResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateItem", varargs...)
+	ret0, _ := ret[0].(*UpdateItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockTakeHomeServiceClientMockRecorder) UpdateItem(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).UpdateItem), varargs...)
+}
+
+// WatchItems mocks base method.
+func (m *MockTakeHomeServiceClient) WatchItems(ctx context.Context, in *WatchItemsRequest, opts ...grpc.CallOption) (TakeHomeService_WatchItemsClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WatchItems", varargs...)
+	ret0, _ := ret[0].(TakeHomeService_WatchItemsClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchItems indicates an expected call of WatchItems.
+func (mr *MockTakeHomeServiceClientMockRecorder) WatchItems(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchItems", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).WatchItems), varargs...)
+}
+
+// MockTakeHomeService_ExportItemsClient is a mock of TakeHomeService_ExportItemsClient interface.
+type MockTakeHomeService_ExportItemsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeService_ExportItemsClientMockRecorder
+}
+
+// MockTakeHomeService_ExportItemsClientMockRecorder is the mock recorder for MockTakeHomeService_ExportItemsClient.
+type MockTakeHomeService_ExportItemsClientMockRecorder struct {
+	mock *MockTakeHomeService_ExportItemsClient
+}
+
+// NewMockTakeHomeService_ExportItemsClient creates a new mock instance.
+func NewMockTakeHomeService_ExportItemsClient(ctrl *gomock.Controller) *MockTakeHomeService_ExportItemsClient {
+	mock := &MockTakeHomeService_ExportItemsClient{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeService_ExportItemsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeService_ExportItemsClient) EXPECT() *MockTakeHomeService_ExportItemsClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) Recv() (*ExportItemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*ExportItemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockTakeHomeService_ExportItemsClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockTakeHomeService_ExportItemsClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockTakeHomeService_ExportItemsClient)(nil).Trailer))
+}
+
+// MockTakeHomeService_ExportItemsServer is a mock of TakeHomeService_ExportItemsServer interface.
+type MockTakeHomeService_ExportItemsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeService_ExportItemsServerMockRecorder
+}
+
+// MockTakeHomeService_ExportItemsServerMockRecorder is the mock recorder for MockTakeHomeService_ExportItemsServer.
+type MockTakeHomeService_ExportItemsServerMockRecorder struct {
+	mock *MockTakeHomeService_ExportItemsServer
+}
+
+// NewMockTakeHomeService_ExportItemsServer creates a new mock instance.
+func NewMockTakeHomeService_ExportItemsServer(ctrl *gomock.Controller) *MockTakeHomeService_ExportItemsServer {
+	mock := &MockTakeHomeService_ExportItemsServer{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeService_ExportItemsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeService_ExportItemsServer) EXPECT() *MockTakeHomeService_ExportItemsServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) Send(arg0 *ExportItemsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockTakeHomeService_ExportItemsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockTakeHomeService_ExportItemsServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockTakeHomeService_ExportItemsServer)(nil).SetTrailer), arg0)
+}
+
+// MockTakeHomeService_ImportItemsClient is a mock of TakeHomeService_ImportItemsClient interface.
+type MockTakeHomeService_ImportItemsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeService_ImportItemsClientMockRecorder
+}
+
+// MockTakeHomeService_ImportItemsClientMockRecorder is the mock recorder for MockTakeHomeService_ImportItemsClient.
+type MockTakeHomeService_ImportItemsClientMockRecorder struct {
+	mock *MockTakeHomeService_ImportItemsClient
+}
+
+// NewMockTakeHomeService_ImportItemsClient creates a new mock instance.
+func NewMockTakeHomeService_ImportItemsClient(ctrl *gomock.Controller) *MockTakeHomeService_ImportItemsClient {
+	mock := &MockTakeHomeService_ImportItemsClient{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeService_ImportItemsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeService_ImportItemsClient) EXPECT() *MockTakeHomeService_ImportItemsClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) Recv() (*ImportItemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*ImportItemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) Send(arg0 *ImportItemsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockTakeHomeService_ImportItemsClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockTakeHomeService_ImportItemsClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockTakeHomeService_ImportItemsClient)(nil).Trailer))
+}
+
+// MockTakeHomeService_ImportItemsServer is a mock of TakeHomeService_ImportItemsServer interface.
+type MockTakeHomeService_ImportItemsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeService_ImportItemsServerMockRecorder
+}
+
+// MockTakeHomeService_ImportItemsServerMockRecorder is the mock recorder for MockTakeHomeService_ImportItemsServer.
+type MockTakeHomeService_ImportItemsServerMockRecorder struct {
+	mock *MockTakeHomeService_ImportItemsServer
+}
+
+// NewMockTakeHomeService_ImportItemsServer creates a new mock instance.
+func NewMockTakeHomeService_ImportItemsServer(ctrl *gomock.Controller) *MockTakeHomeService_ImportItemsServer {
+	mock := &MockTakeHomeService_ImportItemsServer{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeService_ImportItemsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeService_ImportItemsServer) EXPECT() *MockTakeHomeService_ImportItemsServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) Recv() (*ImportItemsRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*ImportItemsRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) Send(arg0 *ImportItemsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockTakeHomeService_ImportItemsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockTakeHomeService_ImportItemsServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockTakeHomeService_ImportItemsServer)(nil).SetTrailer), arg0)
+}
+
+// MockTakeHomeService_WatchItemsClient is a mock of TakeHomeService_WatchItemsClient interface.
+type MockTakeHomeService_WatchItemsClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeService_WatchItemsClientMockRecorder
+}
+
+// MockTakeHomeService_WatchItemsClientMockRecorder is the mock recorder for MockTakeHomeService_WatchItemsClient.
+type MockTakeHomeService_WatchItemsClientMockRecorder struct {
+	mock *MockTakeHomeService_WatchItemsClient
+}
+
+// NewMockTakeHomeService_WatchItemsClient creates a new mock instance.
+func NewMockTakeHomeService_WatchItemsClient(ctrl *gomock.Controller) *MockTakeHomeService_WatchItemsClient {
+	mock := &MockTakeHomeService_WatchItemsClient{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeService_WatchItemsClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeService_WatchItemsClient) EXPECT() *MockTakeHomeService_WatchItemsClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetItem indicates an expected call of GetItem.
-func (mr *MockTakeHomeServiceClientMockRecorder) GetItem(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+// Header indicates an expected call of Header.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) Header() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetItem), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).Header))
 }
 
-// GetItems mocks base method.
-func (m *MockTakeHomeServiceClient) GetItems(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*GetItemsResponse, error) {
+// Recv mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) Recv() (*WatchItemsResponse, error) {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, in}
-	for _, a := range opts {
-		varargs = append(varargs, a)
-	}
-	ret := m.ctrl.Call(m, "GetItems", varargs...)
-	ret0, _ := ret[0].(*GetItemsResponse)
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*WatchItemsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetItems indicates an expected call of GetItems.
-func (mr *MockTakeHomeServiceClientMockRecorder) GetItems(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+// Recv indicates an expected call of Recv.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) Recv() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, in}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockTakeHomeServiceClient)(nil).GetItems), varargs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).RecvMsg), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).SendMsg), arg0)
+}
+
+// Trailer mocks base method.
+func (m *MockTakeHomeService_WatchItemsClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockTakeHomeService_WatchItemsClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockTakeHomeService_WatchItemsClient)(nil).Trailer))
+}
+
+// MockTakeHomeService_WatchItemsServer is a mock of TakeHomeService_WatchItemsServer interface.
+type MockTakeHomeService_WatchItemsServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeService_WatchItemsServerMockRecorder
+}
+
+// MockTakeHomeService_WatchItemsServerMockRecorder is the mock recorder for MockTakeHomeService_WatchItemsServer.
+type MockTakeHomeService_WatchItemsServerMockRecorder struct {
+	mock *MockTakeHomeService_WatchItemsServer
+}
+
+// NewMockTakeHomeService_WatchItemsServer creates a new mock instance.
+func NewMockTakeHomeService_WatchItemsServer(ctrl *gomock.Controller) *MockTakeHomeService_WatchItemsServer {
+	mock := &MockTakeHomeService_WatchItemsServer{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeService_WatchItemsServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeService_WatchItemsServer) EXPECT() *MockTakeHomeService_WatchItemsServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) RecvMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecvMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) RecvMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).RecvMsg), arg0)
+}
+
+// Send mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) Send(arg0 *WatchItemsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) SendMsg(arg0 any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMsg", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) SendMsg(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).SendMsg), arg0)
+}
+
+// SetHeader mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockTakeHomeService_WatchItemsServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockTakeHomeService_WatchItemsServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockTakeHomeService_WatchItemsServer)(nil).SetTrailer), arg0)
 }
 
 // MockTakeHomeServiceServer is a mock of TakeHomeServiceServer interface.
@@ -132,6 +1148,65 @@ func (mr *MockTakeHomeServiceServerMockRecorder) CreateItem(ctx, in interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).CreateItem), ctx, in)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockTakeHomeServiceServer) CreateWebhook(ctx context.Context, in *CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, in)
+	ret0, _ := ret[0].(*CreateWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockTakeHomeServiceServerMockRecorder) CreateWebhook(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).CreateWebhook), ctx, in)
+}
+
+// DeleteItem mocks base method.
+func (m *MockTakeHomeServiceServer) DeleteItem(ctx context.Context, in *DeleteItemRequest) (*DeleteItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItem", ctx, in)
+	ret0, _ := ret[0].(*DeleteItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteItem indicates an expected call of DeleteItem.
+func (mr *MockTakeHomeServiceServerMockRecorder) DeleteItem(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).DeleteItem), ctx, in)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockTakeHomeServiceServer) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, in)
+	ret0, _ := ret[0].(*DeleteWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockTakeHomeServiceServerMockRecorder) DeleteWebhook(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).DeleteWebhook), ctx, in)
+}
+
+// ExportItems mocks base method.
+func (m *MockTakeHomeServiceServer) ExportItems(in *ExportItemsRequest, stream TakeHomeService_ExportItemsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportItems", in, stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportItems indicates an expected call of ExportItems.
+func (mr *MockTakeHomeServiceServerMockRecorder) ExportItems(in, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportItems", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).ExportItems), in, stream)
+}
+
 // GetItem mocks base method.
 func (m *MockTakeHomeServiceServer) GetItem(ctx context.Context, in *GetItemRequest) (*GetItemResponse, error) {
 	m.ctrl.T.Helper()
@@ -147,8 +1222,23 @@ func (mr *MockTakeHomeServiceServerMockRecorder) GetItem(ctx, in interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).GetItem), ctx, in)
 }
 
+// GetItemKinds mocks base method.
+func (m *MockTakeHomeServiceServer) GetItemKinds(ctx context.Context, in *EmptyRequest) (*GetItemKindsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemKinds", ctx, in)
+	ret0, _ := ret[0].(*GetItemKindsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemKinds indicates an expected call of GetItemKinds.
+func (mr *MockTakeHomeServiceServerMockRecorder) GetItemKinds(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemKinds", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).GetItemKinds), ctx, in)
+}
+
 // GetItems mocks base method.
-func (m *MockTakeHomeServiceServer) GetItems(ctx context.Context, in *EmptyRequest) (*GetItemsResponse, error) {
+func (m *MockTakeHomeServiceServer) GetItems(ctx context.Context, in *GetItemsRequest) (*GetItemsResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetItems", ctx, in)
 	ret0, _ := ret[0].(*GetItemsResponse)
@@ -161,3 +1251,121 @@ func (mr *MockTakeHomeServiceServerMockRecorder) GetItems(ctx, in interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).GetItems), ctx, in)
 }
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockTakeHomeServiceServer) GetWebhookDeliveries(ctx context.Context, in *GetWebhookDeliveriesRequest) (*GetWebhookDeliveriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, in)
+	ret0, _ := ret[0].(*GetWebhookDeliveriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockTakeHomeServiceServerMockRecorder) GetWebhookDeliveries(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).GetWebhookDeliveries), ctx, in)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockTakeHomeServiceServer) GetWebhooks(ctx context.Context, in *EmptyRequest) (*GetWebhooksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, in)
+	ret0, _ := ret[0].(*GetWebhooksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockTakeHomeServiceServerMockRecorder) GetWebhooks(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).GetWebhooks), ctx, in)
+}
+
+// ImportItems mocks base method.
+func (m *MockTakeHomeServiceServer) ImportItems(stream TakeHomeService_ImportItemsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportItems", stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportItems indicates an expected call of ImportItems.
+func (mr *MockTakeHomeServiceServerMockRecorder) ImportItems(stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportItems", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).ImportItems), stream)
+}
+
+// PutItemKind mocks base method.
+func (m *MockTakeHomeServiceServer) PutItemKind(ctx context.Context, in *PutItemKindRequest) (*PutItemKindResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutItemKind", ctx, in)
+	ret0, _ := ret[0].(*PutItemKindResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutItemKind indicates an expected call of PutItemKind.
+func (mr *MockTakeHomeServiceServerMockRecorder) PutItemKind(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutItemKind", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).PutItemKind), ctx, in)
+}
+
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockTakeHomeServiceServer) RedeliverWebhookDelivery(ctx context.Context, in *RedeliverWebhookDeliveryRequest) (*RedeliverWebhookDeliveryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", ctx, in)
+	ret0, _ := ret[0].(*RedeliverWebhookDeliveryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockTakeHomeServiceServerMockRecorder) RedeliverWebhookDelivery(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).RedeliverWebhookDelivery), ctx, in)
+}
+
+// SearchItems mocks base method.
+func (m *MockTakeHomeServiceServer) SearchItems(ctx context.Context, in *SearchItemsRequest) (*SearchItemsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchItems", ctx, in)
+	ret0, _ := ret[0].(*SearchItemsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchItems indicates an expected call of SearchItems.
+func (mr *MockTakeHomeServiceServerMockRecorder) SearchItems(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchItems", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).SearchItems), ctx, in)
+}
+
+// UpdateItem mocks base method.
+func (m *MockTakeHomeServiceServer) UpdateItem(ctx context.Context, in *UpdateItemRequest) (*UpdateItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, in)
+	ret0, _ := ret[0].(*UpdateItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockTakeHomeServiceServerMockRecorder) UpdateItem(ctx, in interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).UpdateItem), ctx, in)
+}
+
+// WatchItems mocks base method.
+func (m *MockTakeHomeServiceServer) WatchItems(in *WatchItemsRequest, stream TakeHomeService_WatchItemsServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchItems", in, stream)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchItems indicates an expected call of WatchItems.
+func (mr *MockTakeHomeServiceServerMockRecorder) WatchItems(in, stream interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchItems", reflect.TypeOf((*MockTakeHomeServiceServer)(nil).WatchItems), in, stream)
+}
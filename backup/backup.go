@@ -0,0 +1,215 @@
+// Package backup reads and writes the archives of the backup and restore
+// commands. An archive is a gzipped stream of JSON records: a manifest, then
+// the rows of each store table followed by the table's row count and SHA-256
+// checksum. Rows are encoded by the store models rather than the database, so
+// an archive restores into any backend.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+)
+
+// FormatVersion is the version of the archive layout itself, as opposed to
+// the store schema it holds.
+const FormatVersion = 1
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int `json:"format_version"`
+	// SchemaVersion is the store.SchemaVersion of the backed up store.
+	SchemaVersion int `json:"schema_version"`
+	// Backend is the database the backup was taken from, e.g. "sqlite".
+	Backend   string    `json:"backend"`
+	CreatedAt time.Time `json:"created_at"`
+	// Tables lists the tables in the archive, in order.
+	Tables []string `json:"tables"`
+}
+
+// Checksum closes the section of a table.
+type Checksum struct {
+	Table  string `json:"table"`
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+type record struct {
+	Manifest *Manifest       `json:"manifest,omitempty"`
+	Table    string          `json:"table,omitempty"`
+	Row      json.RawMessage `json:"row,omitempty"`
+	Checksum *Checksum       `json:"checksum,omitempty"`
+}
+
+// tableHash accumulates the checksum of one table's rows.
+type tableHash struct {
+	hash hash.Hash
+	rows int64
+}
+
+func newTableHash() *tableHash {
+	return &tableHash{hash: sha256.New()}
+}
+
+func (h *tableHash) add(row []byte) {
+	h.hash.Write(row)
+	h.hash.Write([]byte{'\n'})
+	h.rows++
+}
+
+func (h *tableHash) checksum(table string) *Checksum {
+	return &Checksum{Table: table, Rows: h.rows, SHA256: hex.EncodeToString(h.hash.Sum(nil))}
+}
+
+// Writer writes an archive. Rows must be written table by table in manifest
+// order, and Close must be called once all of them have been written.
+type Writer struct {
+	gz      *gzip.Writer
+	enc     *json.Encoder
+	tables  []string
+	current int
+	hash    *tableHash
+	buf     bytes.Buffer
+}
+
+func NewWriter(w io.Writer, manifest Manifest) (*Writer, error) {
+	manifest.FormatVersion = FormatVersion
+
+	gz := gzip.NewWriter(w)
+	writer := &Writer{gz: gz, enc: json.NewEncoder(gz), tables: manifest.Tables, hash: newTableHash()}
+
+	if err := writer.enc.Encode(record{Manifest: &manifest}); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// WriteRow appends a JSON encoded row of table.
+func (w *Writer) WriteRow(table string, row []byte) error {
+	for w.current < len(w.tables) && w.tables[w.current] != table {
+		if err := w.closeTable(); err != nil {
+			return err
+		}
+	}
+
+	if w.current == len(w.tables) {
+		return fmt.Errorf("table %q is not in the manifest or out of order", table)
+	}
+
+	// hash the row as it ends up in the archive
+	w.buf.Reset()
+	if err := json.Compact(&w.buf, row); err != nil {
+		return fmt.Errorf("%s: %w", table, err)
+	}
+	w.hash.add(w.buf.Bytes())
+
+	return w.enc.Encode(record{Table: table, Row: w.buf.Bytes()})
+}
+
+func (w *Writer) closeTable() error {
+	err := w.enc.Encode(record{Checksum: w.hash.checksum(w.tables[w.current])})
+	w.current++
+	w.hash = newTableHash()
+
+	return err
+}
+
+// Close writes the checksums of the remaining tables and flushes the archive.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	for w.current < len(w.tables) {
+		if err := w.closeTable(); err != nil {
+			return err
+		}
+	}
+
+	return w.gz.Close()
+}
+
+// Reader reads an archive.
+type Reader struct {
+	gz       *gzip.Reader
+	dec      *json.Decoder
+	manifest Manifest
+}
+
+// NewReader reads the manifest of the archive in r.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+
+	reader := &Reader{gz: gz, dec: json.NewDecoder(gz)}
+
+	var first record
+	if err := reader.dec.Decode(&first); err != nil || first.Manifest == nil {
+		return nil, errors.New("not a backup archive: missing manifest")
+	}
+
+	if first.Manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", first.Manifest.FormatVersion)
+	}
+
+	reader.manifest = *first.Manifest
+
+	return reader, nil
+}
+
+func (r *Reader) Manifest() Manifest {
+	return r.manifest
+}
+
+// ReadRows passes every row of the archive to fn, table by table. It fails,
+// possibly after passing some rows on, if a table's rows don't match its
+// checksum or the archive is truncated, so fn should only commit to the rows
+// once ReadRows returned nil.
+func (r *Reader) ReadRows(fn func(table string, row []byte) error) error {
+	for _, table := range r.manifest.Tables {
+		hash := newTableHash()
+
+		for {
+			var rec record
+			if err := r.dec.Decode(&rec); err != nil {
+				if errors.Is(err, io.EOF) {
+					return fmt.Errorf("backup archive is truncated in table %s", table)
+				}
+				return err
+			}
+
+			if rec.Checksum != nil {
+				want := hash.checksum(table)
+				if *rec.Checksum != *want {
+					return fmt.Errorf("checksum mismatch in table %s: archive has %d rows with sha256 %s, read %d rows with sha256 %s",
+						table, rec.Checksum.Rows, rec.Checksum.SHA256, want.Rows, want.SHA256)
+				}
+				break
+			}
+
+			if rec.Table != table {
+				return fmt.Errorf("unexpected row of table %q in table %s", rec.Table, table)
+			}
+
+			hash.add(rec.Row)
+
+			if err := fn(table, rec.Row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close releases the decompressor. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
@@ -0,0 +1,217 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testRows = []struct {
+	table string
+	row   string
+}{
+	{"items", `{"id": 1, "name": "Kettle"}`},
+	{"items", `{"id":2,"name":"Teapot"}`},
+	{"webhooks", `{"id":1,"url":"https://example.com/hook"}`},
+}
+
+func writeArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	writer, err := NewWriter(&buf, Manifest{
+		SchemaVersion: 1,
+		Backend:       "sqlite",
+		CreatedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tables:        []string{"items", "item_labels", "webhooks", "webhook_deliveries"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range testRows {
+		if err := writer.WriteRow(row.table, []byte(row.row)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// rewrite applies edit to the decompressed records of archive.
+func rewrite(t *testing.T, archive []byte, edit func(records string) string) []byte {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(edit(string(records)))); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func readArchive(archive []byte) ([]string, error) {
+	reader, err := NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var rows []string
+	err = reader.ReadRows(func(table string, row []byte) error {
+		rows = append(rows, table+" "+string(row))
+		return nil
+	})
+
+	return rows, err
+}
+
+func TestRoundTrip(t *testing.T) {
+	archive := writeArchive(t)
+
+	reader, err := NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := reader.Manifest()
+	if manifest.FormatVersion != FormatVersion || manifest.SchemaVersion != 1 || manifest.Backend != "sqlite" ||
+		!manifest.CreatedAt.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) || len(manifest.Tables) != 4 {
+		t.Errorf("got manifest %+v", manifest)
+	}
+
+	rows, err := readArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rows come back compacted
+	want := []string{
+		`items {"id":1,"name":"Kettle"}`,
+		`items {"id":2,"name":"Teapot"}`,
+		`webhooks {"id":1,"url":"https://example.com/hook"}`,
+	}
+	if fmt.Sprint(rows) != fmt.Sprint(want) {
+		t.Errorf("got rows %q, want %q", rows, want)
+	}
+}
+
+func TestReadRowsInvalid(t *testing.T) {
+	archive := writeArchive(t)
+
+	for _, tc := range []struct {
+		name string
+		edit func(string) string
+		want string
+	}{
+		{
+			name: "changed row",
+			edit: func(s string) string { return strings.Replace(s, "Kettle", "Kettles", 1) },
+			want: "checksum mismatch in table items",
+		},
+		{
+			name: "dropped row",
+			edit: func(s string) string {
+				return strings.Replace(s, `{"table":"items","row":{"id":2,"name":"Teapot"}}`+"\n", "", 1)
+			},
+			want: "checksum mismatch in table items: archive has 2 rows",
+		},
+		{
+			name: "changed checksum",
+			edit: func(s string) string { return strings.Replace(s, `"rows":1`, `"rows":2`, 1) },
+			want: "checksum mismatch in table webhooks",
+		},
+		{
+			name: "truncated",
+			edit: func(s string) string { return s[:strings.Index(s, `{"table":"webhooks"`)] },
+			want: "truncated in table webhooks",
+		},
+		{
+			name: "row of another table",
+			edit: func(s string) string { return strings.Replace(s, `{"table":"webhooks"`, `{"table":"item_labels"`, 1) },
+			want: `unexpected row of table "item_labels" in table webhooks`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := readArchive(rewrite(t, archive, tc.edit))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewReaderInvalid(t *testing.T) {
+	archive := writeArchive(t)
+
+	for name, tc := range map[string]struct {
+		archive []byte
+		want    string
+	}{
+		"not gzip":         {archive: []byte(`{"manifest":{}}`), want: "not a backup archive"},
+		"no manifest":      {archive: rewrite(t, archive, func(s string) string { return s[strings.Index(s, "\n")+1:] }), want: "missing manifest"},
+		"empty":            {archive: rewrite(t, archive, func(string) string { return "" }), want: "missing manifest"},
+		"format version 2": {archive: rewrite(t, archive, func(s string) string { return strings.Replace(s, `"format_version":1`, `"format_version":2`, 1) }), want: "unsupported backup format version 2"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tc.archive))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteRowOutOfOrder(t *testing.T) {
+	writer, err := NewWriter(io.Discard, Manifest{Tables: []string{"items", "webhooks"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.WriteRow("webhooks", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.WriteRow("items", []byte(`{}`)); err == nil {
+		t.Error("writing a table after a later one: got no error")
+	}
+
+}
+
+func TestWriteRowInvalid(t *testing.T) {
+	writer, err := NewWriter(io.Discard, Manifest{Tables: []string{"items"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.WriteRow("items", []byte(`{"id":`)); err == nil {
+		t.Error("writing invalid JSON: got no error")
+	}
+
+	if err := writer.WriteRow("unknown", []byte(`{}`)); err == nil {
+		t.Error("writing a table that isn't in the manifest: got no error")
+	}
+}
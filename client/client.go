@@ -0,0 +1,264 @@
+// Package client is a Go client for TakeHomeService. It sets up the
+// connection, authenticates, retries idempotent RPCs with backoff, applies
+// deadlines, pages through GetItems and turns failed RPCs into *Error values
+// that match ErrNotFound, ErrConflict, ErrRateLimited and the other sentinel
+// errors of this package.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultTimeout        = 30 * time.Second
+	defaultMaxAttempts    = 4
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// idempotentMethods are retried on UNAVAILABLE and RESOURCE_EXHAUSTED.
+// Creating, updating and deleting items are not: a retried write whose first
+// attempt did commit would be applied twice or fail spuriously.
+var idempotentMethods = []string{
+	"GetItems", "GetItem", "SearchItems", "PutItemKind", "GetItemKinds", "GetWebhooks", "GetWebhookDeliveries",
+}
+
+// Config configures a Client. Only Address is required.
+type Config struct {
+	// Address is the gRPC address of the server, e.g. "localhost:9008".
+	Address string
+
+	// TLS configures the TLS connection to the server; nil uses the system
+	// roots. Insecure connects in plaintext instead, e.g. to a local server.
+	TLS      *tls.Config
+	Insecure bool
+
+	// Token is sent as a bearer token with every request.
+	Token string
+	// Headers are sent with every request.
+	Headers map[string]string
+	// Namespace selects the namespace requests act in; empty uses the
+	// server's default, or the namespace of the TLS client certificate.
+	Namespace string
+
+	// Timeout caps the deadline of unary RPCs, 30 seconds by default; a
+	// context deadline that is sooner still applies. Retries count against
+	// the deadline of the call.
+	Timeout time.Duration
+	// MaxAttempts bounds the attempts of idempotent RPCs, 4 by default; 1
+	// disables retries. Attempts back off exponentially from InitialBackoff,
+	// 100ms by default, up to MaxBackoff, 5s by default.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// DialOptions are appended to the options New dials with.
+	DialOptions []grpc.DialOption
+}
+
+// Client is a TakeHomeServiceClient whose errors are *Error values.
+type Client struct {
+	types.TakeHomeServiceClient
+	conn *grpc.ClientConn
+}
+
+// New connects to the server at config.Address. Like grpc.NewClient, it
+// does not wait for the connection to be established.
+func New(config Config) (*Client, error) {
+	if config.Address == "" {
+		return nil, errors.New("client: missing address")
+	}
+
+	if config.Namespace != "" {
+		if err := tenancy.Validate(config.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	creds := insecure.NewCredentials()
+	if !config.Insecure {
+		creds = credentials.NewTLS(config.TLS)
+	}
+
+	serviceConfig, err := config.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.MD{}
+	for key, value := range config.Headers {
+		md.Append(strings.ToLower(key), value)
+	}
+	if config.Namespace != "" {
+		md.Set(strings.ToLower(tenancy.Header), config.Namespace)
+	}
+
+	options := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(unaryInterceptor(md)),
+		grpc.WithChainStreamInterceptor(streamInterceptor(md)),
+	}
+
+	if config.Token != "" {
+		options = append(options, grpc.WithPerRPCCredentials(bearerToken{token: config.Token, requireTLS: !config.Insecure}))
+	}
+
+	conn, err := grpc.NewClient(config.Address, append(options, config.DialOptions...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{TakeHomeServiceClient: types.NewTakeHomeServiceClient(conn), conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// serviceConfig returns the gRPC service config that sets the deadlines and
+// retry policy, see https://github.com/grpc/grpc/blob/master/doc/service_config.md.
+func (c Config) serviceConfig() (string, error) {
+	timeout := valueOr(c.Timeout, defaultTimeout)
+	maxAttempts := valueOr(c.MaxAttempts, defaultMaxAttempts)
+
+	if maxAttempts < 1 {
+		return "", fmt.Errorf("client: invalid MaxAttempts %d", maxAttempts)
+	}
+
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method,omitempty"`
+	}
+
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+
+	type methodConfig struct {
+		Name        []name       `json:"name"`
+		Timeout     string       `json:"timeout,omitempty"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	service := types.TakeHomeService_ServiceDesc.ServiceName
+	seconds := func(d time.Duration) string { return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s" }
+	names := func(methods ...string) []name {
+		out := make([]name, 0, len(methods))
+		for _, method := range methods {
+			out = append(out, name{Service: service, Method: method})
+		}
+		return out
+	}
+
+	var policy *retryPolicy
+	if maxAttempts > 1 {
+		policy = &retryPolicy{
+			// gRPC caps maxAttempts at 5
+			MaxAttempts:          min(maxAttempts, 5),
+			InitialBackoff:       seconds(valueOr(c.InitialBackoff, defaultInitialBackoff)),
+			MaxBackoff:           seconds(valueOr(c.MaxBackoff, defaultMaxBackoff)),
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+		}
+	}
+
+	// the entry naming a method overrides the service-wide one
+	configs := []methodConfig{
+		{Name: []name{{Service: service}}, Timeout: seconds(timeout)},
+		{Name: names(idempotentMethods...), Timeout: seconds(timeout), RetryPolicy: policy},
+		{Name: names("WatchItems", "ImportItems")},
+		{Name: names("ExportItems"), RetryPolicy: policy},
+	}
+
+	data, err := json.Marshal(struct {
+		MethodConfig []methodConfig `json:"methodConfig"`
+	}{configs})
+
+	return string(data), err
+}
+
+func valueOr[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
+
+// bearerToken sends a token in the authorization header.
+type bearerToken struct {
+	token      string
+	requireTLS bool
+}
+
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return t.requireTLS
+}
+
+// unaryInterceptor adds md to outgoing requests and decodes errors.
+func unaryInterceptor(md metadata.MD) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return decodeError(invoker(withMetadata(ctx, md), method, req, reply, cc, opts...))
+	}
+}
+
+func streamInterceptor(md metadata.MD) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(withMetadata(ctx, md), desc, cc, method, opts...)
+		if err != nil {
+			return nil, decodeError(err)
+		}
+
+		return decodingStream{stream}, nil
+	}
+}
+
+// withMetadata adds md to ctx, unless ctx already sets the same keys.
+func withMetadata(ctx context.Context, md metadata.MD) context.Context {
+	if len(md) == 0 {
+		return ctx
+	}
+
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	merged := md.Copy()
+	for key, values := range outgoing {
+		merged[key] = values
+	}
+
+	return metadata.NewOutgoingContext(ctx, merged)
+}
+
+type decodingStream struct {
+	grpc.ClientStream
+}
+
+func (s decodingStream) SendMsg(m interface{}) error {
+	return decodeError(s.ClientStream.SendMsg(m))
+}
+
+func (s decodingStream) RecvMsg(m interface{}) error {
+	return decodeError(s.ClientStream.RecvMsg(m))
+}
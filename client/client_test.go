@@ -0,0 +1,433 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestDecodeError(t *testing.T) {
+	if err := decodeError(nil); err != nil {
+		t.Errorf("decodeError(nil) = %v", err)
+	}
+
+	if err := decodeError(io.EOF); err != io.EOF {
+		t.Errorf("decodeError(io.EOF) = %v, want io.EOF", err)
+	}
+
+	plain := errors.New("connection closed")
+	if err := decodeError(plain); err != plain {
+		t.Errorf("decodeError of an error without a status = %v, want it unchanged", err)
+	}
+
+	s, err := status.New(codes.ResourceExhausted, "slow down").WithDetails(
+		&errdetails.ErrorInfo{Reason: "RATE_LIMITED", Domain: "platform-take-home", Metadata: map[string]string{"limit": "10"}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+		&errdetails.RequestInfo{RequestId: "req-1"},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "item.name", Description: "required"}}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded *Error
+	if !errors.As(decodeError(s.Err()), &decoded) {
+		t.Fatalf("decodeError returned %T, want *Error", decodeError(s.Err()))
+	}
+
+	if got := status.Code(decoded); got != codes.ResourceExhausted {
+		t.Errorf("status.Code of the decoded error = %v, want ResourceExhausted", got)
+	}
+
+	if got, want := decoded.Error(), "rpc error: code = ResourceExhausted desc = slow down"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	want := &Error{
+		Code:            codes.ResourceExhausted,
+		Message:         "slow down",
+		Reason:          "RATE_LIMITED",
+		Domain:          "platform-take-home",
+		Metadata:        map[string]string{"limit": "10"},
+		RetryDelay:      1500 * time.Millisecond,
+		FieldViolations: map[string]string{"item.name": "required"},
+		RequestID:       "req-1",
+	}
+	decoded.status = nil
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("got %+v, want %+v", decoded, want)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrConflict, ErrRateLimited, ErrUnavailable, ErrInvalidArgument, ErrPermissionDenied}
+
+	for code, want := range map[codes.Code]error{
+		codes.NotFound:          ErrNotFound,
+		codes.Aborted:           ErrConflict,
+		codes.AlreadyExists:     ErrConflict,
+		codes.ResourceExhausted: ErrRateLimited,
+		codes.Unavailable:       ErrUnavailable,
+		codes.InvalidArgument:   ErrInvalidArgument,
+		codes.PermissionDenied:  ErrPermissionDenied,
+		codes.Unauthenticated:   ErrPermissionDenied,
+		codes.Internal:          nil,
+		codes.DeadlineExceeded:  nil,
+	} {
+		t.Run(code.String(), func(t *testing.T) {
+			// wrapped, as callers often return it
+			err := fmt.Errorf("get item: %w", decodeError(status.Error(code, "failed")))
+
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == want) {
+					t.Errorf("errors.Is(%v, %v) = %v", code, sentinel, got)
+				}
+			}
+		})
+	}
+}
+
+// fakeServer serves GetItems from a list of item IDs, a page at a time, with
+// page tokens of its own making, and records the requests it gets.
+type fakeServer struct {
+	types.UnimplementedTakeHomeServiceServer
+	ids []uint64
+
+	mu       sync.Mutex
+	requests []*types.GetItemsRequest
+	metadata []metadata.MD
+	// failures are returned, in order, before the calls that succeed
+	failures []error
+}
+
+func (s *fakeServer) record(ctx context.Context, req *types.GetItemsRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.metadata = append(s.metadata, md)
+	s.requests = append(s.requests, req)
+
+	if len(s.failures) > 0 {
+		err := s.failures[0]
+		s.failures = s.failures[1:]
+		return err
+	}
+
+	return nil
+}
+
+func (s *fakeServer) GetItems(ctx context.Context, req *types.GetItemsRequest) (*types.GetItemsResponse, error) {
+	if err := s.record(ctx, req); err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if req.PageToken != "" {
+		offset, ok := strings.CutPrefix(req.PageToken, "offset-")
+		if !ok {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		start, _ = strconv.Atoi(offset)
+	}
+
+	end := min(start+int(req.PageSize), len(s.ids))
+	res := &types.GetItemsResponse{}
+	for _, id := range s.ids[start:end] {
+		res.Items = append(res.Items, &types.Item{Id: id})
+	}
+	if end < len(s.ids) {
+		res.NextPageToken = fmt.Sprintf("offset-%d", end)
+	}
+
+	return res, nil
+}
+
+func (s *fakeServer) CreateItem(ctx context.Context, req *types.CreateItemRequest) (*types.CreateItemResponse, error) {
+	if err := s.record(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	return &types.CreateItemResponse{ItemId: 1}, nil
+}
+
+func (s *fakeServer) pageTokens() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := make([]string, 0, len(s.requests))
+	for _, req := range s.requests {
+		tokens = append(tokens, req.PageToken)
+	}
+
+	return tokens
+}
+
+func (s *fakeServer) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.requests)
+}
+
+// newFakeClient serves fake over an in-memory connection and returns a
+// client of it configured by config.
+func newFakeClient(t *testing.T, fake *fakeServer, config Config) *Client {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	types.RegisterTakeHomeServiceServer(server, fake)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	config.Address = "passthrough:///fake"
+	config.Insecure = true
+	config.DialOptions = append(config.DialOptions, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}))
+
+	c, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func ids(n int) []uint64 {
+	ids := make([]uint64, 0, n)
+	for i := 1; i <= n; i++ {
+		ids = append(ids, uint64(i))
+	}
+	return ids
+}
+
+func TestPages(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		items    int
+		pageSize uint32
+		pages    [][]uint64
+		tokens   []string
+	}{
+		{name: "last page partial", items: 5, pageSize: 2, pages: [][]uint64{{1, 2}, {3, 4}, {5}}, tokens: []string{"", "offset-2", "offset-4"}},
+		{name: "last page full", items: 4, pageSize: 2, pages: [][]uint64{{1, 2}, {3, 4}}, tokens: []string{"", "offset-2"}},
+		{name: "single page", items: 3, pageSize: 10, pages: [][]uint64{{1, 2, 3}}, tokens: []string{""}},
+		{name: "empty", items: 0, pageSize: 10, pages: [][]uint64{nil}, tokens: []string{""}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeServer{ids: ids(tc.items)}
+			c := newFakeClient(t, fake, Config{})
+
+			var pages [][]uint64
+			for page, err := range c.Pages(context.Background(), &types.GetItemsRequest{PageSize: tc.pageSize}) {
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var pageIDs []uint64
+				for _, item := range page.Items {
+					pageIDs = append(pageIDs, item.Id)
+				}
+				pages = append(pages, pageIDs)
+			}
+
+			if fmt.Sprint(pages) != fmt.Sprint(tc.pages) {
+				t.Errorf("got pages %v, want %v", pages, tc.pages)
+			}
+
+			if got := fake.pageTokens(); !slices.Equal(got, tc.tokens) {
+				t.Errorf("got page tokens %q, want %q", got, tc.tokens)
+			}
+		})
+	}
+}
+
+func TestPagesStartsAtPageToken(t *testing.T) {
+	fake := &fakeServer{ids: ids(5)}
+	c := newFakeClient(t, fake, Config{})
+
+	req := &types.GetItemsRequest{PageToken: "offset-3", LabelSelector: "env=prod"}
+
+	var got []uint64
+	for item, err := range c.Items(context.Background(), req) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, item.Id)
+	}
+
+	if !slices.Equal(got, []uint64{4, 5}) {
+		t.Errorf("got items %v, want [4 5]", got)
+	}
+
+	if req.PageToken != "offset-3" || req.PageSize != 0 {
+		t.Errorf("Items changed the request to %v", req)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if sent := fake.requests[0]; sent.PageSize != DefaultPageSize || sent.LabelSelector != "env=prod" {
+		t.Errorf("sent %v, want the request with the default page size", sent)
+	}
+}
+
+func TestItems(t *testing.T) {
+	fake := &fakeServer{ids: ids(7)}
+	c := newFakeClient(t, fake, Config{})
+
+	var got []uint64
+	for item, err := range c.Items(context.Background(), &types.GetItemsRequest{PageSize: 3}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, item.Id)
+	}
+
+	if !slices.Equal(got, ids(7)) {
+		t.Errorf("got items %v, want %v", got, ids(7))
+	}
+
+	t.Run("break", func(t *testing.T) {
+		fake := &fakeServer{ids: ids(7)}
+		c := newFakeClient(t, fake, Config{})
+
+		var got []uint64
+		for item, err := range c.Items(context.Background(), &types.GetItemsRequest{PageSize: 3}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got = append(got, item.Id); len(got) == 4 {
+				break
+			}
+		}
+
+		if !slices.Equal(got, []uint64{1, 2, 3, 4}) {
+			t.Errorf("got items %v, want [1 2 3 4]", got)
+		}
+
+		// the page holding item 4 was needed, the one after it wasn't
+		if calls := fake.calls(); calls != 2 {
+			t.Errorf("got %d calls, want 2", calls)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		fake := &fakeServer{ids: ids(7)}
+		c := newFakeClient(t, fake, Config{})
+
+		var got []uint64
+		var errs []error
+		for item, err := range c.Items(context.Background(), &types.GetItemsRequest{PageSize: 3, PageToken: "bogus"}) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			got = append(got, item.Id)
+		}
+
+		if len(got) != 0 || len(errs) != 1 || !errors.Is(errs[0], ErrInvalidArgument) {
+			t.Errorf("got items %v and errors %v, want a single invalid argument error", got, errs)
+		}
+	})
+}
+
+func TestRetries(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "try again")
+	config := Config{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	t.Run("idempotent", func(t *testing.T) {
+		fake := &fakeServer{ids: ids(1), failures: []error{unavailable, unavailable}}
+		c := newFakeClient(t, fake, config)
+
+		if _, err := c.GetItems(context.Background(), &types.GetItemsRequest{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if calls := fake.calls(); calls != 3 {
+			t.Errorf("got %d calls, want 3", calls)
+		}
+	})
+
+	t.Run("not idempotent", func(t *testing.T) {
+		fake := &fakeServer{failures: []error{unavailable}}
+		c := newFakeClient(t, fake, config)
+
+		_, err := c.CreateItem(context.Background(), &types.CreateItemRequest{Item: &types.Item{Name: "Kettle"}})
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("got %v, want ErrUnavailable", err)
+		}
+
+		if calls := fake.calls(); calls != 1 {
+			t.Errorf("got %d calls, want 1", calls)
+		}
+	})
+
+	t.Run("attempts", func(t *testing.T) {
+		fake := &fakeServer{failures: []error{unavailable, unavailable, unavailable}}
+		config := config
+		config.MaxAttempts = 2
+		c := newFakeClient(t, fake, config)
+
+		if _, err := c.GetItems(context.Background(), &types.GetItemsRequest{}); !errors.Is(err, ErrUnavailable) {
+			t.Errorf("got %v, want ErrUnavailable", err)
+		}
+
+		if calls := fake.calls(); calls != 2 {
+			t.Errorf("got %d calls, want 2", calls)
+		}
+	})
+}
+
+func TestMetadata(t *testing.T) {
+	fake := &fakeServer{}
+	c := newFakeClient(t, fake, Config{Token: "t0ken", Namespace: "team-a", Headers: map[string]string{"X-Client": "test"}})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-client", "override")
+	if _, err := c.GetItems(ctx, &types.GetItemsRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	md := fake.metadata[0]
+	for key, want := range map[string]string{"authorization": "Bearer t0ken", "x-namespace": "team-a", "x-client": "override"} {
+		if got := md.Get(key); !slices.Equal(got, []string{want}) {
+			t.Errorf("%s: got %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for name, config := range map[string]Config{
+		"no address":        {},
+		"invalid namespace": {Address: "localhost:9008", Namespace: "Team A"},
+		"invalid attempts":  {Address: "localhost:9008", MaxAttempts: -1},
+	} {
+		if _, err := New(config); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
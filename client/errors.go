@@ -0,0 +1,117 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Sentinel errors an *Error matches with errors.Is, by status code.
+var (
+	// ErrNotFound: the item, kind or webhook doesn't exist (NOT_FOUND).
+	ErrNotFound = errors.New("not found")
+	// ErrConflict: a concurrent write won, or the record already exists
+	// (ABORTED, ALREADY_EXISTS). Re-read and try again.
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited: too many requests (RESOURCE_EXHAUSTED). Error.RetryDelay
+	// says how long to wait if the server said so.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnavailable: the server or its database is unavailable (UNAVAILABLE).
+	ErrUnavailable = errors.New("unavailable")
+	// ErrInvalidArgument: the request is invalid (INVALID_ARGUMENT).
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrPermissionDenied: the caller may not act in the namespace
+	// (PERMISSION_DENIED, UNAUTHENTICATED).
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Error is a failed RPC, decoded from its status and status details.
+type Error struct {
+	Code    codes.Code
+	Message string
+
+	// Reason and Metadata come from a google.rpc.ErrorInfo detail, e.g.
+	// the NOT_FOUND or CONFLICT reasons of the service.
+	Reason   string
+	Domain   string
+	Metadata map[string]string
+
+	// RetryDelay comes from a google.rpc.RetryInfo detail.
+	RetryDelay time.Duration
+
+	// FieldViolations come from a google.rpc.BadRequest detail, keyed by
+	// field.
+	FieldViolations map[string]string
+
+	// RequestID comes from a google.rpc.RequestInfo detail, and identifies
+	// the call in the server's logs and traces.
+	RequestID string
+
+	status *status.Status
+}
+
+func (e *Error) Error() string {
+	return "rpc error: code = " + e.Code.String() + " desc = " + e.Message
+}
+
+// Is matches the sentinel error of e's code.
+func (e *Error) Is(target error) bool {
+	switch e.Code {
+	case codes.NotFound:
+		return target == ErrNotFound
+	case codes.Aborted, codes.AlreadyExists:
+		return target == ErrConflict
+	case codes.ResourceExhausted:
+		return target == ErrRateLimited
+	case codes.Unavailable:
+		return target == ErrUnavailable
+	case codes.InvalidArgument:
+		return target == ErrInvalidArgument
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return target == ErrPermissionDenied
+	}
+
+	return false
+}
+
+// GRPCStatus lets status.FromError and status.Code see through e.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// decodeError turns a status error into an *Error. It leaves nil and io.EOF,
+// which ends a stream, alone.
+func decodeError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	decoded := &Error{Code: s.Code(), Message: s.Message(), status: s}
+
+	for _, detail := range s.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			decoded.Reason, decoded.Domain, decoded.Metadata = detail.Reason, detail.Domain, detail.Metadata
+		case *errdetails.RetryInfo:
+			decoded.RetryDelay = detail.GetRetryDelay().AsDuration()
+		case *errdetails.RequestInfo:
+			decoded.RequestID = detail.RequestId
+		case *errdetails.BadRequest:
+			decoded.FieldViolations = make(map[string]string, len(detail.FieldViolations))
+			for _, violation := range detail.FieldViolations {
+				decoded.FieldViolations[violation.Field] = violation.Description
+			}
+		}
+	}
+
+	return decoded
+}
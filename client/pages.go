@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"iter"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultPageSize is the page size of Items and Pages when the request sets
+// none.
+const DefaultPageSize = 100
+
+// Pages iterates over the pages of GetItems, starting at req.PageToken. It
+// stops after the first error, which it yields with a nil page. Every page
+// is a separate RPC with its own deadline and retries.
+func (c *Client) Pages(ctx context.Context, req *types.GetItemsRequest, opts ...grpc.CallOption) iter.Seq2[*types.GetItemsResponse, error] {
+	return func(yield func(*types.GetItemsResponse, error) bool) {
+		req := proto.Clone(req).(*types.GetItemsRequest)
+		if req.PageSize == 0 {
+			req.PageSize = DefaultPageSize
+		}
+
+		for {
+			page, err := c.GetItems(ctx, req, opts...)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(page, nil) || page.NextPageToken == "" {
+				return
+			}
+
+			req.PageToken = page.NextPageToken
+		}
+	}
+}
+
+// Items iterates over the items GetItems returns for req, fetching them a
+// page at a time:
+//
+//	for item, err := range c.Items(ctx, &types.GetItemsRequest{LabelSelector: "env=prod"}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Items(ctx context.Context, req *types.GetItemsRequest, opts ...grpc.CallOption) iter.Seq2[*types.Item, error] {
+	return func(yield func(*types.Item, error) bool) {
+		for page, err := range c.Pages(ctx, req, opts...) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
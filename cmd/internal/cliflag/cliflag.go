@@ -0,0 +1,29 @@
+// Package cliflag holds the flag types shared by the command-line tools.
+package cliflag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Headers collects repeated -H key:value flags.
+type Headers map[string]string
+
+func (h Headers) String() string {
+	pairs := make([]string, 0, len(h))
+	for key, value := range h {
+		pairs = append(pairs, key+":"+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (h Headers) Set(value string) error {
+	key, v, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("invalid header %q: must be key:value", value)
+	}
+	h[strings.TrimSpace(key)] = strings.TrimSpace(v)
+	return nil
+}
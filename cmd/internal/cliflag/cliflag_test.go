@@ -0,0 +1,40 @@
+package cliflag
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestHeaders(t *testing.T) {
+	headers := Headers{}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(headers, "H", "")
+
+	if err := flags.Parse([]string{"-H", "X-Team: a", "-H", "x-empty:", "-H", "Authorization: Bearer a:b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Headers{"X-Team": "a", "x-empty": "", "Authorization": "Bearer a:b"}
+	if !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %v, want %v", headers, want)
+	}
+
+	if got, want := headers.String(), "Authorization:Bearer a:b,X-Team:a,x-empty:"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestHeadersInvalid(t *testing.T) {
+	for _, value := range []string{"no-colon", ":value", " :value"} {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		flags.Var(Headers{}, "H", "")
+
+		if err := flags.Parse([]string{"-H", value}); err == nil {
+			t.Errorf("-H %q: no error", value)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/client"
+	"github.com/skip-mev/platform-take-home/cmd/internal/cliflag"
+	"github.com/skip-mev/platform-take-home/tenancy"
+)
+
+// options are the flags shared by every command.
+type options struct {
+	flags      *flag.FlagSet
+	configPath string
+	profile    string
+	override   Profile
+	headers    cliflag.Headers
+	timeout    time.Duration
+}
+
+func newOptions(flags *flag.FlagSet) *options {
+	o := &options{flags: flags, headers: cliflag.Headers{}}
+
+	flags.StringVar(&o.configPath, "config", configPath(), "configuration file with profiles ($ITEMCTL_CONFIG)")
+	flags.StringVar(&o.profile, "profile", "", "profile to use ($ITEMCTL_PROFILE, default: current_profile of the config)")
+	flags.StringVar(&o.override.Address, "address", "", "server gRPC address (default "+defaultAddress+")")
+	flags.StringVar(&o.override.Namespace, "namespace", "", "namespace to act in (default: the server's default)")
+	flags.StringVar(&o.override.Token, "token", "", "bearer token sent in the authorization header")
+	flags.Var(o.headers, "H", "header sent with every request as key:value; may be repeated")
+	flags.BoolVar(&o.override.TLS, "tls", false, "connect with TLS")
+	flags.StringVar(&o.override.CACert, "ca-cert", "", "CA certificate to verify the server with (implies -tls)")
+	flags.StringVar(&o.override.Cert, "cert", "", "client certificate (implies -tls)")
+	flags.StringVar(&o.override.Key, "key", "", "client certificate key")
+	flags.StringVar(&o.override.ServerName, "server-name", "", "server name to verify instead of the address host")
+	flags.BoolVar(&o.override.InsecureSkipVerify, "insecure-skip-verify", false, "do not verify the server certificate")
+	flags.StringVar(&o.override.Output, "o", "", "output format: table, json or yaml (default table)")
+	flags.DurationVar(&o.timeout, "timeout", 30*time.Second, "request timeout")
+
+	return o
+}
+
+// parse parses args, which may mix flags and positional arguments, and
+// returns the positional ones.
+func (o *options) parse(args []string) []string {
+	var positional []string
+
+	for {
+		o.flags.Parse(args)
+		args = o.flags.Args()
+
+		if len(args) == 0 {
+			return positional
+		}
+
+		if args[0] == "--" {
+			return append(positional, args[1:]...)
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolve overlays the flags that were set on the selected profile.
+func (o *options) resolve() (Profile, error) {
+	config, err := loadConfig(o.configPath)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	profile, err := config.profile(o.profile)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	o.flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "address":
+			profile.Address = o.override.Address
+		case "namespace":
+			profile.Namespace = o.override.Namespace
+		case "token":
+			profile.Token, profile.TokenEnv = o.override.Token, ""
+		case "tls":
+			profile.TLS = o.override.TLS
+		case "ca-cert":
+			profile.CACert = o.override.CACert
+		case "cert":
+			profile.Cert = o.override.Cert
+		case "key":
+			profile.Key = o.override.Key
+		case "server-name":
+			profile.ServerName = o.override.ServerName
+		case "insecure-skip-verify":
+			profile.InsecureSkipVerify = o.override.InsecureSkipVerify
+		case "o":
+			profile.Output = o.override.Output
+		}
+	})
+
+	if profile.Address == "" {
+		profile.Address = defaultAddress
+	}
+
+	if profile.TokenEnv != "" {
+		if profile.Token = os.Getenv(profile.TokenEnv); profile.Token == "" {
+			return Profile{}, fmt.Errorf("%s is not set", profile.TokenEnv)
+		}
+	}
+
+	headers := make(map[string]string, len(profile.Headers)+len(o.headers))
+	for key, value := range profile.Headers {
+		headers[key] = value
+	}
+	for key, value := range o.headers {
+		headers[key] = value
+	}
+	profile.Headers = headers
+
+	if profile.Namespace != "" {
+		if err := tenancy.Validate(profile.Namespace); err != nil {
+			return Profile{}, err
+		}
+	}
+
+	if _, err := newPrinter(profile.Output); err != nil {
+		return Profile{}, err
+	}
+
+	return profile, nil
+}
+
+// connect dials the server of the resolved profile. The returned context
+// carries the request timeout.
+func (o *options) connect(ctx context.Context) (context.Context, *client.Client, *printer, func(), error) {
+	profile, err := o.resolve()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	tlsConfig, err := tlsConfig(profile)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	c, err := client.New(client.Config{
+		Address:   profile.Address,
+		TLS:       tlsConfig,
+		Insecure:  tlsConfig == nil,
+		Token:     profile.Token,
+		Headers:   profile.Headers,
+		Namespace: profile.Namespace,
+		Timeout:   o.timeout,
+	})
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	printer, _ := newPrinter(profile.Output)
+
+	return ctx, c, printer, func() {
+		cancel()
+		c.Close()
+	}, nil
+}
+
+// tlsConfig returns the TLS settings of profile, or nil to connect in
+// plaintext.
+func tlsConfig(profile Profile) (*tls.Config, error) {
+	if !profile.TLS && profile.CACert == "" && profile.Cert == "" && !profile.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         profile.ServerName,
+		InsecureSkipVerify: profile.InsecureSkipVerify,
+	}
+
+	if profile.CACert != "" {
+		pem, err := os.ReadFile(profile.CACert)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found", profile.CACert)
+		}
+	}
+
+	if profile.Cert != "" || profile.Key != "" {
+		if profile.Cert == "" || profile.Key == "" {
+			return nil, errors.New("a client certificate needs both cert and key")
+		}
+
+		cert, err := tls.LoadX509KeyPair(profile.Cert, profile.Key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultAddress = "localhost:9008"
+
+// Profile holds the connection settings of one environment. Flags override
+// the fields of the selected profile.
+type Profile struct {
+	Address   string `yaml:"address"`
+	Namespace string `yaml:"namespace"`
+	// Token is sent as a bearer token in the authorization header. TokenEnv
+	// names an environment variable to read it from instead, to keep it out
+	// of the file.
+	Token    string `yaml:"token"`
+	TokenEnv string `yaml:"token_env"`
+	// Headers are sent with every request.
+	Headers map[string]string `yaml:"headers"`
+
+	TLS bool `yaml:"tls"`
+	// CACert verifies the server instead of the system roots.
+	CACert string `yaml:"ca_cert"`
+	// Cert and Key are a client certificate, which a server with
+	// TLS_CLIENT_CA_FILE requires. The first Organization of its subject
+	// then selects the namespace, which Namespace may only repeat.
+	Cert               string `yaml:"cert"`
+	Key                string `yaml:"key"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+
+	// Output is the default output format.
+	Output string `yaml:"output"`
+}
+
+// Config is the itemctl configuration file:
+//
+//	current_profile: local
+//	profiles:
+//	  local:
+//	    address: localhost:9008
+//	  staging:
+//	    address: items.staging.example.com:443
+//	    tls: true
+//	    token_env: ITEMS_STAGING_TOKEN
+//	    namespace: team-a
+type Config struct {
+	CurrentProfile string             `yaml:"current_profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// configPath returns $ITEMCTL_CONFIG, or config.yaml in the user's itemctl
+// config directory.
+func configPath() string {
+	if path := os.Getenv("ITEMCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "itemctl", "config.yaml")
+}
+
+// loadConfig reads the configuration file at path. A missing file is an
+// empty configuration.
+func loadConfig(path string) (Config, error) {
+	var config Config
+
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return config, err
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// profile returns the named profile, $ITEMCTL_PROFILE or the current profile,
+// in that order. Without any of them it returns the zero Profile.
+func (c Config) profile(name string) (Profile, error) {
+	if name == "" {
+		name = os.Getenv("ITEMCTL_PROFILE")
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+
+	return profile, nil
+}
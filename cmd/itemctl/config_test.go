@@ -0,0 +1,175 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConfig = `current_profile: local
+profiles:
+  local:
+    address: localhost:1234
+    headers:
+      x-team: a
+      x-env: local
+  staging:
+    address: items.staging.example.com:443
+    tls: true
+    token_env: ITEMS_STAGING_TOKEN
+    namespace: team-a
+    output: json
+`
+
+func writeConfig(t *testing.T, config string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestResolve(t *testing.T) {
+	path := writeConfig(t, testConfig)
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		env  map[string]string
+		want Profile
+		err  string
+	}{
+		{
+			name: "current profile",
+			want: Profile{Address: "localhost:1234", Headers: map[string]string{"x-team": "a", "x-env": "local"}},
+		},
+		{
+			name: "named profile",
+			args: []string{"-profile", "staging"},
+			env:  map[string]string{"ITEMS_STAGING_TOKEN": "s3cret"},
+			want: Profile{Address: "items.staging.example.com:443", TLS: true, Token: "s3cret", TokenEnv: "ITEMS_STAGING_TOKEN", Namespace: "team-a", Output: "json", Headers: map[string]string{}},
+		},
+		{
+			name: "profile from the environment",
+			env:  map[string]string{"ITEMCTL_PROFILE": "staging", "ITEMS_STAGING_TOKEN": "s3cret"},
+			want: Profile{Address: "items.staging.example.com:443", TLS: true, Token: "s3cret", TokenEnv: "ITEMS_STAGING_TOKEN", Namespace: "team-a", Output: "json", Headers: map[string]string{}},
+		},
+		{
+			name: "flags override the profile",
+			args: []string{"-profile", "staging", "-token", "t0ken", "-namespace", "team-b", "-o", "yaml", "-tls=false", "-H", "x-team:b"},
+			want: Profile{Address: "items.staging.example.com:443", Token: "t0ken", Namespace: "team-b", Output: "yaml", Headers: map[string]string{"x-team": "b"}},
+		},
+		{
+			name: "header flags add to the profile's",
+			args: []string{"-H", "x-team:b", "-H", "x-trace:1"},
+			want: Profile{Address: "localhost:1234", Headers: map[string]string{"x-team": "b", "x-env": "local", "x-trace": "1"}},
+		},
+		{
+			name: "token env unset",
+			args: []string{"-profile", "staging"},
+			err:  "ITEMS_STAGING_TOKEN is not set",
+		},
+		{
+			name: "unknown profile",
+			args: []string{"-profile", "prod"},
+			err:  `unknown profile "prod"`,
+		},
+		{
+			name: "invalid namespace",
+			args: []string{"-namespace", "Team A"},
+			err:  "namespace",
+		},
+		{
+			name: "invalid output",
+			args: []string{"-o", "xml"},
+			err:  `unknown output format "xml"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("ITEMCTL_PROFILE", "")
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			opts := newOptions(flag.NewFlagSet("test", flag.ContinueOnError))
+			opts.parse(append([]string{"-config", path}, tc.args...))
+
+			profile, err := opts.resolve()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("resolve() error = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(profile, tc.want) {
+				t.Errorf("resolve() = %+v, want %+v", profile, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolveWithoutConfig(t *testing.T) {
+	t.Setenv("ITEMCTL_PROFILE", "")
+
+	opts := newOptions(flag.NewFlagSet("test", flag.ContinueOnError))
+	opts.parse([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")})
+
+	profile, err := opts.resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.Address != defaultAddress {
+		t.Errorf("address = %q, want %q", profile.Address, defaultAddress)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	path := writeConfig(t, "profiles: [")
+
+	if _, err := loadConfig(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("loadConfig() error = %v, want one naming %s", err, path)
+	}
+}
+
+func TestParseMixedArgs(t *testing.T) {
+	opts := newOptions(flag.NewFlagSet("test", flag.ContinueOnError))
+
+	args := opts.parse([]string{"1", "-o", "json", "2", "--", "-3"})
+	if want := []string{"1", "2", "-3"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("parse() = %q, want %q", args, want)
+	}
+	if opts.override.Output != "json" {
+		t.Errorf("output = %q, want json", opts.override.Output)
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	config, err := tlsConfig(Profile{})
+	if err != nil || config != nil {
+		t.Errorf("tlsConfig() without TLS = %v, %v, want plaintext", config, err)
+	}
+
+	config, err = tlsConfig(Profile{TLS: true, ServerName: "items.example.com"})
+	if err != nil || config == nil || config.ServerName != "items.example.com" {
+		t.Errorf("tlsConfig() with TLS = %v, %v", config, err)
+	}
+
+	if _, err := tlsConfig(Profile{Cert: "client.pem"}); err == nil || !strings.Contains(err.Error(), "both cert and key") {
+		t.Errorf("tlsConfig() with cert only: error %v", err)
+	}
+
+	if _, err := tlsConfig(Profile{CACert: filepath.Join(t.TempDir(), "missing.pem")}); err == nil {
+		t.Error("tlsConfig() with a missing CA: no error")
+	}
+}
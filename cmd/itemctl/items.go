@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"gopkg.in/yaml.v3"
+)
+
+// consistencyToken is the header item writes return; passing it on makes
+// the following read see the write even if it is served by a replica.
+const consistencyToken = "x-consistency-token"
+
+func runGet(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("get", flag.ExitOnError)
+	opts := newOptions(flags)
+	readMask := flags.String("read-mask", "", "comma separated fields to return, e.g. name,labels")
+	flags.Usage = usage(flags, "get [flags] <id>...")
+	ids, err := parseIDs(opts.parse(args))
+	if err != nil {
+		return err
+	}
+
+	ctx, client, printer, done, err := opts.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	response := &types.GetItemsResponse{}
+	for _, id := range ids {
+		item, err := client.GetItem(ctx, &types.GetItemRequest{Id: id, ReadMask: fieldMask(*readMask)})
+		if err != nil {
+			return err
+		}
+		response.Items = append(response.Items, item.Item)
+	}
+
+	if len(ids) == 1 && printer.format != "table" {
+		return printer.message(response.Items[0])
+	}
+
+	return printer.items(response, response.Items)
+}
+
+func runList(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	opts := newOptions(flags)
+	selector := flags.String("l", "", "label selector, e.g. env=prod,team in (a,b)")
+	attributeFilter := flags.String("attributes", "", "attribute filter, e.g. ram_gb>=16")
+	readMask := flags.String("read-mask", "", "comma separated fields to return, e.g. name,labels")
+	flags.Usage = usage(flags, "list [flags]")
+	if args := opts.parse(args); len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+
+	ctx, client, printer, done, err := opts.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	response, err := client.GetItems(ctx, &types.GetItemsRequest{
+		LabelSelector:   *selector,
+		AttributeFilter: *attributeFilter,
+		ReadMask:        fieldMask(*readMask),
+	})
+	if err != nil {
+		return err
+	}
+
+	return printer.items(response, response.Items)
+}
+
+func runCreate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	opts := newOptions(flags)
+	file := flags.String("f", "-", "JSON or YAML file with an item or a list of items, or - for stdin")
+	flags.Usage = usage(flags, "create [flags]")
+	if args := opts.parse(args); len(args) > 0 {
+		return fmt.Errorf("unexpected arguments %q", args)
+	}
+
+	items, err := readItems(*file)
+	if err != nil {
+		return err
+	}
+
+	ctx, client, printer, done, err := opts.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	response := &types.GetItemsResponse{}
+	for _, item := range items {
+		var header metadata.MD
+		created, err := client.CreateItem(ctx, &types.CreateItemRequest{Item: item}, grpc.Header(&header))
+		if err != nil {
+			return err
+		}
+
+		readCtx := ctx
+		if tokens := header.Get(consistencyToken); len(tokens) > 0 {
+			readCtx = metadata.AppendToOutgoingContext(ctx, consistencyToken, tokens[0])
+		}
+
+		got, err := client.GetItem(readCtx, &types.GetItemRequest{Id: created.ItemId})
+		if err != nil {
+			return fmt.Errorf("created item %d, but could not read it back: %w", created.ItemId, err)
+		}
+		response.Items = append(response.Items, got.Item)
+	}
+
+	if len(items) == 1 && printer.format != "table" {
+		return printer.message(response.Items[0])
+	}
+
+	return printer.items(response, response.Items)
+}
+
+func runDelete(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("delete", flag.ExitOnError)
+	opts := newOptions(flags)
+	flags.Usage = usage(flags, "delete [flags] <id>...")
+	ids, err := parseIDs(opts.parse(args))
+	if err != nil {
+		return err
+	}
+
+	ctx, client, printer, done, err := opts.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	for _, id := range ids {
+		response, err := client.DeleteItem(ctx, &types.DeleteItemRequest{Id: id})
+		if err != nil {
+			return err
+		}
+
+		if printer.format == "table" {
+			fmt.Fprintf(printer.w, "item %d deleted\n", id)
+		} else if err := printer.message(response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func usage(flags *flag.FlagSet, synopsis string) func() {
+	return func() {
+		fmt.Fprintf(flags.Output(), "usage: itemctl %s\n\nflags:\n", synopsis)
+		flags.PrintDefaults()
+	}
+}
+
+func parseIDs(args []string) ([]uint64, error) {
+	if len(args) == 0 {
+		return nil, errors.New("missing item ID")
+	}
+
+	ids := make([]uint64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid item ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func fieldMask(paths string) *fieldmaskpb.FieldMask {
+	if paths == "" {
+		return nil
+	}
+
+	mask := &fieldmaskpb.FieldMask{}
+	for _, path := range strings.Split(paths, ",") {
+		mask.Paths = append(mask.Paths, strings.TrimSpace(path))
+	}
+
+	return mask
+}
+
+// readItems reads the items to create from path. Every YAML document, and
+// JSON is YAML, holds an item or a list of items in the JSON form of the REST
+// API.
+func readItems(path string) ([]*types.Item, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var items []*types.Item
+
+	decoder := yaml.NewDecoder(r)
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if doc == nil {
+			continue
+		}
+
+		docs, ok := doc.([]interface{})
+		if !ok {
+			docs = []interface{}{doc}
+		}
+
+		for _, doc := range docs {
+			data, err := json.Marshal(doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+
+			item := &types.Item{}
+			if err := protojson.Unmarshal(data, item); err != nil {
+				return nil, fmt.Errorf("%s: item %d: %w", path, len(items)+1, err)
+			}
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s: no items", path)
+	}
+
+	return items, nil
+}
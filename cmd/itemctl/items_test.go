@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/testutil"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// run runs a command against s and returns what it printed.
+func run(t *testing.T, s *testutil.Server, cmd func(ctx context.Context, args []string) error, args ...string) (string, error) {
+	t.Helper()
+
+	var out bytes.Buffer
+	stdout = &out
+	t.Cleanup(func() { stdout = os.Stdout })
+
+	args = append([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml"), "-address", s.GRPCAddress}, args...)
+	err := cmd(context.Background(), args)
+
+	return out.String(), err
+}
+
+func TestCommands(t *testing.T) {
+	t.Setenv("ITEMCTL_PROFILE", "")
+	s := testutil.Start(t, testutil.Config{})
+
+	path := filepath.Join(t.TempDir(), "items.yaml")
+	items := `name: Kettle
+labels:
+  env: prod
+---
+- name: Toaster
+  labels:
+    env: dev
+- name: Mug
+`
+	if err := os.WriteFile(path, []byte(items), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, s, runCreate, "-f", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Kettle", "Toaster", "Mug", "env=prod"} {
+		if !strings.Contains(out, name) {
+			t.Errorf("create printed %q, want %s in it", out, name)
+		}
+	}
+
+	out, err = run(t, s, runList, "-l", "env=prod", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list types.GetItemsResponse
+	if err := protojson.Unmarshal([]byte(out), &list); err != nil {
+		t.Fatalf("list printed invalid JSON %q: %v", out, err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "Kettle" {
+		t.Fatalf("list -l env=prod = %v, want Kettle", list.Items)
+	}
+	id := list.Items[0].Id
+
+	out, err = run(t, s, runGet, "-o", "json", "-read-mask", "name", itoa(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &fields); err != nil {
+		t.Fatalf("get printed invalid JSON %q: %v", out, err)
+	}
+	if want := map[string]interface{}{"id": itoa(id), "name": "Kettle"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("get -read-mask name = %v, want %v", fields, want)
+	}
+
+	out, err = run(t, s, runDelete, itoa(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "item " + itoa(id) + " deleted\n"; out != want {
+		t.Errorf("delete printed %q, want %q", out, want)
+	}
+
+	if _, err := run(t, s, runGet, itoa(id)); err == nil {
+		t.Error("get of a deleted item succeeded")
+	}
+}
+
+func TestParseIDs(t *testing.T) {
+	ids, err := parseIDs([]string{"1", "42"})
+	if err != nil || !reflect.DeepEqual(ids, []uint64{1, 42}) {
+		t.Errorf("parseIDs() = %v, %v, want [1 42]", ids, err)
+	}
+
+	for _, args := range [][]string{nil, {"1", "x"}, {"-1"}} {
+		if _, err := parseIDs(args); err == nil {
+			t.Errorf("parseIDs(%q): no error", args)
+		}
+	}
+}
+
+func TestFieldMask(t *testing.T) {
+	if mask := fieldMask(""); mask != nil {
+		t.Errorf("fieldMask(\"\") = %v, want nil", mask)
+	}
+
+	if mask := fieldMask("name, labels"); !reflect.DeepEqual(mask.Paths, []string{"name", "labels"}) {
+		t.Errorf("fieldMask() paths = %q", mask.Paths)
+	}
+}
+
+func TestReadItems(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tc := range []struct {
+		name  string
+		file  string
+		names []string
+		err   string
+	}{
+		{name: "json", file: `{"name": "Kettle", "labels": {"env": "prod"}}`, names: []string{"Kettle"}},
+		{name: "json list", file: `[{"name": "Kettle"}, {"name": "Mug"}]`, names: []string{"Kettle", "Mug"}},
+		{name: "yaml documents", file: "name: Kettle\n---\n---\n- name: Mug\n", names: []string{"Kettle", "Mug"}},
+		{name: "empty", file: "", err: "no items"},
+		{name: "unknown field", file: "name: Kettle\n---\ncolour: red\n", err: "item 2"},
+		{name: "invalid yaml", file: "name: [", err: "items.yaml"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "items.yaml")
+			if err := os.WriteFile(path, []byte(tc.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			items, err := readItems(path)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("readItems() error = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make([]string, 0, len(items))
+			for _, item := range items {
+				names = append(names, item.Name)
+			}
+			if !reflect.DeepEqual(names, tc.names) {
+				t.Errorf("readItems() = %q, want %q", names, tc.names)
+			}
+		})
+	}
+}
+
+func itoa(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
@@ -0,0 +1,76 @@
+// Command itemctl is a command line client for TakeHomeService.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/skip-mev/platform-take-home/client"
+	"google.golang.org/grpc/status"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"get":    {usage: "get an item by ID", run: runGet},
+	"list":   {usage: "list items, optionally filtered by labels and attributes", run: runList},
+	"create": {usage: "create an item from a JSON or YAML file or stdin", run: runCreate},
+	"delete": {usage: "delete an item by ID", run: runDelete},
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	name, args := os.Args[1], os.Args[2:]
+
+	cmd, ok := commands[name]
+	if !ok {
+		if name != "help" && name != "-h" && name != "-help" {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		}
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(ctx, args); err != nil {
+		// show what the server said rather than the whole status
+		var clientErr *client.Error
+		if errors.As(err, &clientErr) && clientErr.RequestID != "" {
+			fmt.Fprintf(os.Stderr, "itemctl %s: %s: %s (request ID %s)\n", name, clientErr.Code, clientErr.Message, clientErr.RequestID)
+		} else if s, ok := status.FromError(err); ok {
+			fmt.Fprintf(os.Stderr, "itemctl %s: %s: %s\n", name, s.Code(), s.Message())
+		} else {
+			fmt.Fprintf(os.Stderr, "itemctl %s: %v\n", name, err)
+		}
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags] [args]\n\ncommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].usage)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nRun %s <command> -h for the flags of a command.\n", os.Args[0])
+}
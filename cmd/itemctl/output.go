@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// stdout is where printers write, replaced by tests.
+var stdout io.Writer = os.Stdout
+
+// printer writes responses in the selected output format.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string) (*printer, error) {
+	switch format {
+	case "":
+		format = "table"
+	case "table", "json", "yaml":
+	default:
+		return nil, fmt.Errorf("unknown output format %q: must be table, json or yaml", format)
+	}
+
+	return &printer{format: format, w: stdout}, nil
+}
+
+// items prints items as a table, or message, the response carrying them, as
+// JSON or YAML.
+func (p *printer) items(message proto.Message, items []*types.Item) error {
+	if p.format != "table" {
+		return p.message(message)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAMESPACE\tNAME\tKIND\tLABELS")
+
+	for _, item := range items {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n",
+			item.Id, orNone(item.Namespace), orNone(item.Name), orNone(item.Kind), orNone(formatLabels(item.Labels)))
+	}
+
+	return tw.Flush()
+}
+
+// message prints message as JSON or YAML, with the field names of the REST
+// API. Tables fall back to YAML.
+func (p *printer) message(message proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if p.format == "json" {
+		_, err = fmt.Fprintf(p.w, "%s\n", data)
+		return err
+	}
+
+	// JSON is YAML; decoding it into a node keeps the field order, and
+	// clearing the styles turns the flow style of JSON into block style
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(p.w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
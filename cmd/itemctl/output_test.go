@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+)
+
+func TestPrinter(t *testing.T) {
+	items := []*types.Item{
+		{Id: 1, Namespace: "default", Name: "Kettle", Labels: map[string]string{"team": "a", "env": "prod"}},
+		{Id: 2, Namespace: "default"},
+	}
+	response := &types.GetItemsResponse{Items: items}
+
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{format: "table", want: `ID  NAMESPACE  NAME    KIND    LABELS
+1   default    Kettle  <none>  env=prod,team=a
+2   default    <none>  <none>  <none>
+`},
+		{format: "yaml", want: `items:
+  - id: "1"
+    name: Kettle
+    labels:
+      env: prod
+      team: a
+    namespace: default
+  - id: "2"
+    namespace: default
+`},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			p, err := newPrinter(tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			p.w = &out
+
+			if err := p.items(response, items); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.String() != tc.want {
+				t.Errorf("printed\n%s\nwant\n%s", out.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestNewPrinterDefault(t *testing.T) {
+	p, err := newPrinter("")
+	if err != nil || p.format != "table" {
+		t.Errorf("newPrinter(\"\") = %v, %v, want a table printer", p, err)
+	}
+}
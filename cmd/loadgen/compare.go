@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxPrinted bounds the mismatches printed when they aren't written to a
+// file.
+const maxPrinted = 20
+
+// comparer diffs responses against the recorded ones.
+type comparer struct {
+	// ignore holds field names left out of the comparison wherever they
+	// appear, e.g. item_id, which differs when replaying into another
+	// database.
+	ignore map[string]bool
+
+	mu      sync.Mutex
+	out     io.Writer
+	jsonl   bool
+	printed int
+}
+
+type mismatch struct {
+	Method      string          `json:"method"`
+	Namespace   string          `json:"namespace,omitempty"`
+	Request     json.RawMessage `json:"request"`
+	Differences []string        `json:"differences"`
+}
+
+func newComparer(ignore string, out io.Writer, jsonl bool) *comparer {
+	c := &comparer{ignore: map[string]bool{}, out: out, jsonl: jsonl}
+
+	for _, field := range strings.Split(ignore, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			c.ignore[field] = true
+		}
+	}
+
+	return c
+}
+
+// compare reports whether the outcome of a replayed call differs from the
+// recorded one, and writes the differences if so.
+func (c *comparer) compare(call call, response proto.Message, err error) (bool, error) {
+	var differences []string
+
+	code := status.Code(err).String()
+	if code != call.recorded.code {
+		differences = append(differences, fmt.Sprintf("code: recorded %s, got %s", call.recorded.code, code))
+	} else if err == nil {
+		got, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(response)
+		if err != nil {
+			return false, err
+		}
+
+		differences = c.diff("", decodeJSON(call.recorded.response), decodeJSON(got), differences)
+	}
+
+	if len(differences) == 0 {
+		return false, nil
+	}
+
+	request, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(call.request)
+	if err != nil {
+		return true, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.jsonl {
+		line, err := json.Marshal(mismatch{Method: call.method, Namespace: call.namespace, Request: request, Differences: differences})
+		if err != nil {
+			return true, err
+		}
+
+		_, err = c.out.Write(append(line, '\n'))
+		return true, err
+	}
+
+	if c.printed++; c.printed <= maxPrinted {
+		fmt.Fprintf(c.out, "mismatch: %s %s\n", call.method, request)
+		for _, difference := range differences {
+			fmt.Fprintf(c.out, "  %s\n", difference)
+		}
+	}
+
+	return true, nil
+}
+
+func decodeJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return map[string]interface{}{}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+
+	return value
+}
+
+// diff appends the differences between the recorded and the new value at
+// path to differences.
+func (c *comparer) diff(path string, recorded, got interface{}, differences []string) []string {
+	recordedObject, ok1 := recorded.(map[string]interface{})
+	gotObject, ok2 := got.(map[string]interface{})
+
+	if ok1 && ok2 {
+		keys := map[string]bool{}
+		for key := range recordedObject {
+			keys[key] = true
+		}
+		for key := range gotObject {
+			keys[key] = true
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			if !c.ignore[key] {
+				sorted = append(sorted, key)
+			}
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			differences = c.diff(join(path, key), recordedObject[key], gotObject[key], differences)
+		}
+
+		return differences
+	}
+
+	recordedList, ok1 := recorded.([]interface{})
+	gotList, ok2 := got.([]interface{})
+
+	if ok1 && ok2 {
+		if len(recordedList) != len(gotList) {
+			differences = append(differences, fmt.Sprintf("%s: recorded %d elements, got %d", path, len(recordedList), len(gotList)))
+		}
+
+		for i := 0; i < min(len(recordedList), len(gotList)); i++ {
+			differences = c.diff(fmt.Sprintf("%s[%d]", path, i), recordedList[i], gotList[i], differences)
+		}
+
+		return differences
+	}
+
+	if !reflect.DeepEqual(recorded, got) {
+		differences = append(differences, fmt.Sprintf("%s: recorded %s, got %s", path, encode(recorded), encode(got)))
+	}
+
+	return differences
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func encode(value interface{}) string {
+	if value == nil {
+		return "nothing"
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		ignore   string
+		recorded string
+		got      string
+		want     []string
+	}{
+		{
+			name:     "equal",
+			recorded: `{"item": {"id": "1", "labels": {"env": "prod"}}}`,
+			got:      `{"item": {"labels": {"env": "prod"}, "id": "1"}}`,
+		},
+		{
+			name:     "changed",
+			recorded: `{"item": {"id": "1", "name": "Kettle"}}`,
+			got:      `{"item": {"id": "1", "name": "Mug"}}`,
+			want:     []string{`item.name: recorded "Kettle", got "Mug"`},
+		},
+		{
+			name:     "missing and added fields",
+			recorded: `{"item": {"id": "1", "name": "Kettle"}}`,
+			got:      `{"item": {"id": "1", "kind": "appliance"}}`,
+			want:     []string{`item.kind: recorded nothing, got "appliance"`, `item.name: recorded "Kettle", got nothing`},
+		},
+		{
+			name:     "ignored everywhere",
+			ignore:   "id, updated_at",
+			recorded: `{"items": [{"id": "1", "updated_at": "a"}, {"id": "2", "name": "Mug"}]}`,
+			got:      `{"items": [{"id": "3", "updated_at": "b"}, {"id": "4", "name": "Mug"}]}`,
+		},
+		{
+			name:     "list lengths",
+			recorded: `{"items": [{"id": "1"}, {"id": "2"}]}`,
+			got:      `{"items": [{"id": "1"}]}`,
+			want:     []string{"items: recorded 2 elements, got 1"},
+		},
+		{
+			name:     "list elements",
+			recorded: `{"ids": ["1", "2"]}`,
+			got:      `{"ids": ["1", "3"]}`,
+			want:     []string{`ids[1]: recorded "2", got "3"`},
+		},
+		{
+			name:     "changed type",
+			recorded: `{"item": {"id": "1"}}`,
+			got:      `{"item": ["1"]}`,
+			want:     []string{`item: recorded {"id":"1"}, got ["1"]`},
+		},
+		{
+			name:     "empty response",
+			recorded: ``,
+			got:      `{}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newComparer(tc.ignore, &bytes.Buffer{}, false)
+
+			got := c.diff("", decodeJSON([]byte(tc.recorded)), decodeJSON([]byte(tc.got)), nil)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("diff() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	recorded := call{
+		method:   "GetItem",
+		request:  &types.GetItemRequest{Id: 1},
+		recorded: &outcome{code: "OK", response: json.RawMessage(`{"item": {"id": "1", "name": "Kettle"}}`)},
+	}
+	same := &types.GetItemResponse{Item: &types.Item{Id: 1, Name: "Kettle"}}
+
+	t.Run("match", func(t *testing.T) {
+		var out bytes.Buffer
+		mismatched, err := newComparer("", &out, false).compare(recorded, same, nil)
+		if err != nil || mismatched || out.Len() > 0 {
+			t.Errorf("compare() = %v, %v, printed %q, want a match", mismatched, err, out.String())
+		}
+	})
+
+	t.Run("code", func(t *testing.T) {
+		var out bytes.Buffer
+		mismatched, err := newComparer("", &out, true).compare(recorded, nil, status.Error(codes.NotFound, "item not found"))
+		if err != nil || !mismatched {
+			t.Fatalf("compare() = %v, %v, want a mismatch", mismatched, err)
+		}
+
+		var m mismatch
+		if err := json.Unmarshal(out.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"code: recorded OK, got NotFound"}; m.Method != "GetItem" || !reflect.DeepEqual(m.Differences, want) {
+			t.Errorf("mismatch = %+v, want %q", m, want)
+		}
+		if string(m.Request) != `{"id":"1"}` {
+			t.Errorf("request = %s", m.Request)
+		}
+	})
+
+	t.Run("error without status", func(t *testing.T) {
+		mismatched, err := newComparer("", &bytes.Buffer{}, false).compare(recorded, nil, errors.New("connection refused"))
+		if err != nil || !mismatched {
+			t.Errorf("compare() = %v, %v, want a mismatch", mismatched, err)
+		}
+	})
+
+	t.Run("prints the first mismatches", func(t *testing.T) {
+		var out bytes.Buffer
+		c := newComparer("", &out, false)
+
+		different := &types.GetItemResponse{Item: &types.Item{Id: 1, Name: "Mug"}}
+		for i := 0; i < maxPrinted+5; i++ {
+			if mismatched, err := c.compare(recorded, different, nil); err != nil || !mismatched {
+				t.Fatalf("compare() = %v, %v, want a mismatch", mismatched, err)
+			}
+		}
+
+		if n := strings.Count(out.String(), "mismatch: GetItem"); n != maxPrinted {
+			t.Errorf("printed %d mismatches, want %d", n, maxPrinted)
+		}
+		if !strings.Contains(out.String(), `  item.name: recorded "Kettle", got "Mug"`) {
+			t.Errorf("printed %q, want the difference", out.String())
+		}
+	})
+}
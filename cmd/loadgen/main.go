@@ -0,0 +1,262 @@
+// Command loadgen benchmarks the server. It replays a JSONL file of recorded
+// requests, or sends a synthetic mix of GetItems, GetItem and CreateItem,
+// against the gRPC server or the REST gateway at a given rate and
+// concurrency, and reports latency percentiles, throughput and errors.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/client"
+	"github.com/skip-mev/platform-take-home/cmd/internal/cliflag"
+	"github.com/skip-mev/platform-take-home/tenancy"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "loadgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	headers := cliflag.Headers{}
+
+	flags := flag.NewFlagSet("loadgen", flag.ExitOnError)
+	transport := flags.String("target", "grpc", "endpoint to load: grpc or rest")
+	address := flags.String("address", "localhost:9008", "gRPC server address")
+	baseURL := flags.String("url", "http://localhost:8080", "REST gateway URL")
+	useTLS := flags.Bool("tls", false, "connect to the gRPC server with TLS")
+	token := flags.String("token", "", "bearer token sent in the authorization header")
+	flags.Var(headers, "H", "header sent with every request as key:value; may be repeated")
+	namespace := flags.String("namespace", "", "namespace of requests that don't name one (default: the server's default)")
+	file := flags.String("file", "", "JSONL file of requests to replay, or - for stdin (default: a synthetic mix)")
+	loop := flags.Bool("loop", false, "replay the file again once it is exhausted")
+	mix := flags.String("mix", "GetItems=20,GetItem=70,CreateItem=10", "weights of the synthetic mix")
+	seed := flags.Uint64("seed", 1, "random seed of the synthetic mix")
+	rate := flags.Float64("rate", 0, "requests per second; 0 sends as fast as the workers allow")
+	concurrency := flags.Int("concurrency", 10, "number of requests in flight at most")
+	duration := flags.Duration("duration", 30*time.Second, "how long to send requests for")
+	requests := flags.Int("requests", 0, "stop after this many requests; 0 runs for -duration")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of each request")
+	compare := flags.Bool("compare", false, "diff the responses against those recorded in -file")
+	ignore := flags.String("ignore", "", "comma separated field names left out of -compare, e.g. item_id")
+	diffFile := flags.String("diff", "", "write every -compare mismatch as JSONL to this file (default: print the first 20)")
+	jsonReport := flags.String("json", "", "also write the report as JSON to this file, or - to print it instead of the text report")
+	flags.Parse(args)
+
+	if *concurrency < 1 {
+		return fmt.Errorf("invalid -concurrency %d", *concurrency)
+	}
+	if *namespace != "" {
+		if err := tenancy.Validate(*namespace); err != nil {
+			return err
+		}
+	}
+	if *compare && *file == "" {
+		return errors.New("-compare needs a recording to replay with -file")
+	}
+
+	var cmp *comparer
+	if *compare {
+		var out io.Writer = os.Stderr
+
+		if *diffFile != "" {
+			file, err := os.Create(*diffFile)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+
+		cmp = newComparer(*ignore, out, *diffFile != "")
+	}
+
+	var w workload
+	var workloadName string
+	var err error
+
+	if *file != "" {
+		w, err = loadReplay(*file, *loop)
+		workloadName = "replay " + *file
+	} else {
+		w, err = newSynthetic(*mix, *seed)
+		workloadName = "synthetic " + *mix
+	}
+	if err != nil {
+		return err
+	}
+
+	var t target
+	var targetName string
+
+	switch *transport {
+	case "grpc":
+		c, err := client.New(client.Config{
+			Address:   *address,
+			Insecure:  !*useTLS,
+			Token:     *token,
+			Headers:   headers,
+			Namespace: *namespace,
+			Timeout:   *timeout,
+			// measure every attempt
+			MaxAttempts: 1,
+		})
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		t, targetName = grpcTarget{client: c}, "grpc "+*address
+	case "rest":
+		if *token != "" {
+			headers["Authorization"] = "Bearer " + *token
+		}
+
+		httpClient := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency}}
+		t, targetName = restTarget{http: httpClient, base: strings.TrimSuffix(*baseURL, "/"), namespace: *namespace, headers: headers}, "rest "+*baseURL
+	default:
+		return fmt.Errorf("invalid -target %q: must be grpc or rest", *transport)
+	}
+
+	fmt.Fprintf(os.Stderr, "loadgen: sending %s to %s\n", workloadName, targetName)
+
+	s := newStats()
+	elapsed := generate(ctx, t, w, s, cmp, generateOptions{
+		rate:        *rate,
+		concurrency: *concurrency,
+		duration:    *duration,
+		requests:    *requests,
+		timeout:     *timeout,
+	})
+
+	report := s.report(targetName, workloadName, elapsed)
+
+	if *jsonReport != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		if *jsonReport == "-" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+
+		if err := os.WriteFile(*jsonReport, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return report.writeText(os.Stdout)
+}
+
+type generateOptions struct {
+	rate        float64
+	concurrency int
+	duration    time.Duration
+	requests    int
+	timeout     time.Duration
+}
+
+// generate sends the calls of w until the duration is up, the request count
+// is reached, the workload is exhausted or ctx is cancelled, and returns how
+// long that took. Requests in flight at the end are waited for and counted.
+// If cmp is set, responses to recorded requests are compared with it.
+func generate(ctx context.Context, t target, w workload, s *stats, cmp *comparer, opts generateOptions) time.Duration {
+	var reportCompareError sync.Once
+
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	calls := make(chan call)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for c := range calls {
+				// not ctx: a request sent before the end is let finish
+				reqCtx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+				start := time.Now()
+				response, err := t.send(reqCtx, c)
+				latency := time.Since(start)
+				cancel()
+
+				s.record(c.method, latency, err)
+				if err == nil {
+					w.observe(c, response)
+				}
+
+				if cmp != nil && c.recorded != nil {
+					mismatched, compareErr := cmp.compare(c, response, err)
+					if compareErr != nil {
+						reportCompareError.Do(func() { fmt.Fprintf(os.Stderr, "loadgen: error comparing responses: %v\n", compareErr) })
+					}
+					s.compared(c.method, mismatched)
+				}
+			}
+		}()
+	}
+
+	start := time.Now()
+
+	var interval time.Duration
+	if opts.rate > 0 {
+		interval = time.Duration(float64(time.Second) / opts.rate)
+	}
+
+dispatch:
+	for i := 0; opts.requests == 0 || i < opts.requests; i++ {
+		if interval > 0 {
+			// pace against the start, so slow sends don't lower the rate
+			wait := time.Until(start.Add(time.Duration(i) * interval))
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					break dispatch
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		c, ok := w.next()
+		if !ok {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case calls <- c:
+		}
+	}
+
+	close(calls)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		fmt.Fprintln(os.Stderr, "loadgen: interrupted")
+	}
+
+	return time.Since(start)
+}
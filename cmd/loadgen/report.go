@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// stats collects the outcome of every request.
+type stats struct {
+	mu      sync.Mutex
+	methods map[string]*methodStats
+}
+
+type methodStats struct {
+	latencies  []time.Duration
+	errors     map[string]int
+	compared   int
+	mismatches int
+}
+
+func newStats() *stats {
+	return &stats{methods: map[string]*methodStats{}}
+}
+
+func (s *stats) record(method string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.method(method)
+	m.latencies = append(m.latencies, latency)
+	if err != nil {
+		m.errors[errorClass(err)]++
+	}
+}
+
+// compared counts a response compared against the recorded one.
+func (s *stats) compared(method string, mismatched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.method(method)
+	m.compared++
+	if mismatched {
+		m.mismatches++
+	}
+}
+
+func (s *stats) method(method string) *methodStats {
+	m, ok := s.methods[method]
+	if !ok {
+		m = &methodStats{errors: map[string]int{}}
+		s.methods[method] = m
+	}
+
+	return m
+}
+
+// errorClass groups errors by status code, or by message if they have none.
+func errorClass(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Code().String()
+	}
+
+	return err.Error()
+}
+
+// Latency summarizes latencies in milliseconds.
+type Latency struct {
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	P999 float64 `json:"p99_9_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+// MethodReport is the outcome of the requests of one method, or of all of
+// them.
+type MethodReport struct {
+	Requests   int            `json:"requests"`
+	Errors     int            `json:"errors"`
+	Throughput float64        `json:"throughput_per_second"`
+	Latency    Latency        `json:"latency"`
+	ErrorCodes map[string]int `json:"error_codes,omitempty"`
+	// Compared and Mismatches count the responses compared against recorded
+	// ones, with -compare, and those that differed.
+	Compared   int `json:"compared,omitempty"`
+	Mismatches int `json:"mismatches,omitempty"`
+}
+
+// Report is the result of a run, as printed by -json.
+type Report struct {
+	Target   string                  `json:"target"`
+	Workload string                  `json:"workload"`
+	Duration float64                 `json:"duration_seconds"`
+	Total    MethodReport            `json:"total"`
+	Methods  map[string]MethodReport `json:"methods"`
+}
+
+func (s *stats) report(target, workload string, elapsed time.Duration) Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := Report{Target: target, Workload: workload, Duration: elapsed.Seconds(), Methods: map[string]MethodReport{}}
+
+	var all []time.Duration
+	allErrors := map[string]int{}
+
+	var compared, mismatches int
+
+	for method, m := range s.methods {
+		methodReport := summarize(m.latencies, m.errors, elapsed)
+		methodReport.Compared, methodReport.Mismatches = m.compared, m.mismatches
+		report.Methods[method] = methodReport
+
+		all = append(all, m.latencies...)
+		for class, n := range m.errors {
+			allErrors[class] += n
+		}
+		compared += m.compared
+		mismatches += m.mismatches
+	}
+
+	report.Total = summarize(all, allErrors, elapsed)
+	report.Total.Compared, report.Total.Mismatches = compared, mismatches
+
+	return report
+}
+
+func summarize(latencies []time.Duration, errorCodes map[string]int, elapsed time.Duration) MethodReport {
+	report := MethodReport{Requests: len(latencies), ErrorCodes: errorCodes}
+	for _, n := range errorCodes {
+		report.Errors += n
+	}
+
+	if len(latencies) == 0 {
+		return report
+	}
+
+	if elapsed > 0 {
+		report.Throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, latency := range sorted {
+		sum += latency
+	}
+
+	// nearest rank
+	percentile := func(p float64) float64 {
+		rank := int(p/100*float64(len(sorted))+0.5) - 1
+		return milliseconds(sorted[min(max(rank, 0), len(sorted)-1)])
+	}
+
+	report.Latency = Latency{
+		Mean: milliseconds(sum / time.Duration(len(sorted))),
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P95:  percentile(95),
+		P99:  percentile(99),
+		P999: percentile(99.9),
+		Max:  milliseconds(sorted[len(sorted)-1]),
+	}
+
+	return report
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (r Report) writeText(w io.Writer) error {
+	fmt.Fprintf(w, "target:     %s\n", r.Target)
+	fmt.Fprintf(w, "workload:   %s\n", r.Workload)
+	fmt.Fprintf(w, "duration:   %.1fs\n", r.Duration)
+	fmt.Fprintf(w, "requests:   %d (%.1f/s)\n", r.Total.Requests, r.Total.Throughput)
+
+	errorRate := 0.0
+	if r.Total.Requests > 0 {
+		errorRate = 100 * float64(r.Total.Errors) / float64(r.Total.Requests)
+	}
+	fmt.Fprintf(w, "errors:     %d (%.2f%%)\n", r.Total.Errors, errorRate)
+	if r.Total.Compared > 0 {
+		fmt.Fprintf(w, "mismatches: %d of %d compared\n", r.Total.Mismatches, r.Total.Compared)
+	}
+	fmt.Fprintln(w)
+
+	methods := make([]string, 0, len(r.Methods))
+	for method := range r.Methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "method\trequests\terrors\treq/s\tmean\tp50\tp90\tp95\tp99\tp99.9\tmax\t")
+
+	row := func(name string, m MethodReport) {
+		l := m.Latency
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			name, m.Requests, m.Errors, m.Throughput, l.Mean, l.P50, l.P90, l.P95, l.P99, l.P999, l.Max)
+	}
+
+	for _, method := range methods {
+		row(method, r.Methods[method])
+	}
+	row("total", r.Total)
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nlatencies in milliseconds")
+
+	if r.Total.Mismatches > 0 {
+		fmt.Fprintln(w, "\nmismatches:")
+		for _, method := range methods {
+			if m := r.Methods[method]; m.Mismatches > 0 {
+				fmt.Fprintf(w, "  %-12s %d of %d\n", method, m.Mismatches, m.Compared)
+			}
+		}
+	}
+
+	if r.Total.Errors == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w, "\nerrors:")
+	for _, method := range methods {
+		codes := make([]string, 0, len(r.Methods[method].ErrorCodes))
+		for code := range r.Methods[method].ErrorCodes {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+
+		for _, code := range codes {
+			fmt.Fprintf(w, "  %-12s %-20s %d\n", method, code, r.Methods[method].ErrorCodes[code])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSummarize(t *testing.T) {
+	// 1ms to 100ms, shuffled
+	var latencies []time.Duration
+	for i := 0; i < 100; i++ {
+		latencies = append(latencies, time.Duration((i*37)%100+1)*time.Millisecond)
+	}
+
+	report := summarize(latencies, map[string]int{"NotFound": 2, "Unavailable": 1}, 10*time.Second)
+
+	want := Latency{Mean: 50.5, P50: 50, P90: 90, P95: 95, P99: 99, P999: 100, Max: 100}
+	if report.Latency != want {
+		t.Errorf("latency = %+v, want %+v", report.Latency, want)
+	}
+	if report.Requests != 100 || report.Errors != 3 || report.Throughput != 10 {
+		t.Errorf("report = %+v, want 100 requests, 3 errors and 10/s", report)
+	}
+}
+
+func TestSummarizePercentiles(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		latencies := make([]time.Duration, 0, len(values))
+		for _, v := range values {
+			latencies = append(latencies, time.Duration(v)*time.Millisecond)
+		}
+		return latencies
+	}
+
+	for _, tc := range []struct {
+		name      string
+		latencies []time.Duration
+		want      Latency
+	}{
+		{name: "one", latencies: ms(7), want: Latency{Mean: 7, P50: 7, P90: 7, P95: 7, P99: 7, P999: 7, Max: 7}},
+		// nearest rank: p50 of 4 is the 2nd, p90 and above the 4th
+		{name: "four", latencies: ms(4, 1, 3, 2), want: Latency{Mean: 2.5, P50: 2, P90: 4, P95: 4, P99: 4, P999: 4, Max: 4}},
+		{name: "ten", latencies: ms(10, 9, 8, 7, 6, 5, 4, 3, 2, 1), want: Latency{Mean: 5.5, P50: 5, P90: 9, P95: 10, P99: 10, P999: 10, Max: 10}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := summarize(tc.latencies, nil, 0).Latency; got != tc.want {
+				t.Errorf("latency = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	report := summarize(nil, nil, time.Second)
+	if report.Requests != 0 || report.Throughput != 0 || report.Latency != (Latency{}) {
+		t.Errorf("report = %+v, want an empty one", report)
+	}
+}
+
+func TestReport(t *testing.T) {
+	s := newStats()
+	s.record("GetItem", time.Millisecond, nil)
+	s.record("GetItem", 3*time.Millisecond, status.Error(codes.NotFound, "item not found"))
+	s.record("CreateItem", 2*time.Millisecond, errors.New("connection refused"))
+	s.compared("GetItem", true)
+	s.compared("GetItem", false)
+
+	report := s.report("grpc localhost:9008", "replay requests.jsonl", time.Second)
+
+	if report.Total.Requests != 3 || report.Total.Errors != 2 || report.Total.Latency.Max != 3 {
+		t.Errorf("total = %+v", report.Total)
+	}
+	if getItem := report.Methods["GetItem"]; getItem.Compared != 2 || getItem.Mismatches != 1 || getItem.ErrorCodes["NotFound"] != 1 {
+		t.Errorf("GetItem = %+v", getItem)
+	}
+	if createItem := report.Methods["CreateItem"]; createItem.ErrorCodes["connection refused"] != 1 {
+		t.Errorf("CreateItem = %+v, want the error counted by message", createItem)
+	}
+
+	var out bytes.Buffer
+	if err := report.writeText(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"requests:   3 (3.0/s)", "errors:     2 (66.67%)", "mismatches: 1 of 2 compared", "NotFound"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report\n%s\nlacks %q", out.String(), want)
+		}
+	}
+}
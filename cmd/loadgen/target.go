@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/client"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// target sends calls to the server and returns the response.
+type target interface {
+	send(ctx context.Context, c call) (proto.Message, error)
+}
+
+type grpcTarget struct {
+	client *client.Client
+}
+
+func (t grpcTarget) send(ctx context.Context, c call) (proto.Message, error) {
+	if c.namespace != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(tenancy.Header), c.namespace)
+	}
+
+	switch req := c.request.(type) {
+	case *types.GetItemsRequest:
+		return t.client.GetItems(ctx, req)
+	case *types.GetItemRequest:
+		return t.client.GetItem(ctx, req)
+	case *types.CreateItemRequest:
+		return t.client.CreateItem(ctx, req)
+	case *types.SearchItemsRequest:
+		return t.client.SearchItems(ctx, req)
+	case *types.UpdateItemRequest:
+		return t.client.UpdateItem(ctx, req)
+	case *types.DeleteItemRequest:
+		return t.client.DeleteItem(ctx, req)
+	}
+
+	return nil, fmt.Errorf("unsupported method %s", c.method)
+}
+
+// restTarget sends calls to the REST gateway, as the routes of api.proto
+// map them.
+type restTarget struct {
+	http      *http.Client
+	base      string
+	namespace string
+	headers   map[string]string
+}
+
+func (t restTarget) send(ctx context.Context, c call) (proto.Message, error) {
+	var method, path string
+	var body proto.Message
+	query := url.Values{}
+
+	var response proto.Message
+
+	switch req := c.request.(type) {
+	case *types.GetItemsRequest:
+		method, path, response = http.MethodGet, "/items", &types.GetItemsResponse{}
+		setQuery(query, "label_selector", req.LabelSelector)
+		setQuery(query, "attribute_filter", req.AttributeFilter)
+		setQuery(query, "read_mask", strings.Join(req.GetReadMask().GetPaths(), ","))
+		setQuery(query, "page_token", req.PageToken)
+		if req.PageSize > 0 {
+			query.Set("page_size", strconv.FormatUint(uint64(req.PageSize), 10))
+		}
+	case *types.GetItemRequest:
+		method, path, response = http.MethodGet, fmt.Sprintf("/items/%d", req.Id), &types.GetItemResponse{}
+		setQuery(query, "read_mask", strings.Join(req.GetReadMask().GetPaths(), ","))
+	case *types.CreateItemRequest:
+		method, path, body, response = http.MethodPost, "/items", req, &types.CreateItemResponse{}
+	case *types.SearchItemsRequest:
+		method, path, response = http.MethodGet, "/items:search", &types.SearchItemsResponse{}
+		setQuery(query, "q", req.Q)
+		if req.Limit > 0 {
+			query.Set("limit", strconv.FormatUint(uint64(req.Limit), 10))
+		}
+	case *types.UpdateItemRequest:
+		method, path, body, response = http.MethodPut, fmt.Sprintf("/items/%d", req.GetItem().GetId()), req.GetItem(), &types.UpdateItemResponse{}
+		setQuery(query, "update_mask", strings.Join(req.GetUpdateMask().GetPaths(), ","))
+	case *types.DeleteItemRequest:
+		method, path, response = http.MethodDelete, fmt.Sprintf("/items/%d", req.Id), &types.DeleteItemResponse{}
+	default:
+		return nil, fmt.Errorf("unsupported method %s", c.method)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := protojson.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := t.base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	if namespace := valueOr(c.namespace, t.namespace); namespace != "" {
+		req.Header.Set(tenancy.Header, namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, gatewayError(resp.StatusCode, data)
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+// gatewayError recovers the gRPC status of a failed REST call from the
+// gateway's error body, so both targets report errors by status code.
+func gatewayError(statusCode int, body []byte) error {
+	var gatewayStatus struct {
+		Code    *int   `json:"code"`
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &gatewayStatus); err != nil || gatewayStatus.Code == nil {
+		return fmt.Errorf("HTTP %d", statusCode)
+	}
+
+	return status.Error(codes.Code(*gatewayStatus.Code), gatewayStatus.Message)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/recording"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// requestTypes are the RPCs loadgen can send, by method name.
+var requestTypes = map[string]func() proto.Message{
+	"GetItems":    func() proto.Message { return &types.GetItemsRequest{} },
+	"GetItem":     func() proto.Message { return &types.GetItemRequest{} },
+	"CreateItem":  func() proto.Message { return &types.CreateItemRequest{} },
+	"SearchItems": func() proto.Message { return &types.SearchItemsRequest{} },
+	"UpdateItem":  func() proto.Message { return &types.UpdateItemRequest{} },
+	"DeleteItem":  func() proto.Message { return &types.DeleteItemRequest{} },
+}
+
+// call is one request to send.
+type call struct {
+	method string
+	// namespace overrides the namespace of the run if set.
+	namespace string
+	request   proto.Message
+	// recorded is what the request got when it was recorded, if known.
+	recorded *outcome
+}
+
+// outcome is the status code of a response and, if it succeeded, its JSON.
+type outcome struct {
+	code     string
+	response json.RawMessage
+}
+
+// A request file holds a recording.Record per line, of which replays only
+// need the method and request:
+//
+//	{"method": "GetItems", "request": {"label_selector": "env=prod"}}
+//	{"method": "GetItem", "namespace": "team-a", "request": {"id": "12"}}
+//
+// Requests are in the JSON form of the REST API. The method may also be a
+// full gRPC method name, such as /skip.platform.api.TakeHomeService/GetItem,
+// as recorded. The code and response of recordings are compared against with
+// -compare.
+
+// workload produces the calls of a run. next is called concurrently and
+// returns false once the workload is exhausted.
+type workload interface {
+	next() (call, bool)
+	observe(c call, response proto.Message)
+}
+
+// replay sends the requests of a file in order, looping over them if asked
+// to.
+type replay struct {
+	mu    sync.Mutex
+	calls []call
+	pos   int
+	loop  bool
+}
+
+func loadReplay(path string, loop bool) (*replay, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	var calls []call
+	skipped := map[string]int{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var rec recording.Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+
+		method := rec.Method[strings.LastIndex(rec.Method, "/")+1:]
+		newRequest, ok := requestTypes[method]
+		if !ok {
+			// recordings hold every unary RPC, e.g. webhook calls too
+			skipped[method]++
+			continue
+		}
+
+		request := newRequest()
+		if len(rec.Request) > 0 {
+			if err := protojson.Unmarshal(rec.Request, request); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+		}
+
+		c := call{method: method, namespace: rec.Namespace, request: request}
+		if rec.Code != "" {
+			c.recorded = &outcome{code: rec.Code, response: rec.Response}
+		}
+
+		calls = append(calls, c)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for method, n := range skipped {
+		fmt.Fprintf(os.Stderr, "loadgen: skipping %d %s requests, which can't be replayed\n", n, method)
+	}
+
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("%s: no requests", path)
+	}
+
+	return &replay{calls: calls, loop: loop}, nil
+}
+
+func (r *replay) next() (call, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pos == len(r.calls) {
+		if !r.loop {
+			return call{}, false
+		}
+		r.pos = 0
+	}
+
+	c := r.calls[r.pos]
+	r.pos++
+
+	return c, true
+}
+
+func (r *replay) observe(call, proto.Message) {}
+
+// synthetic sends a weighted random mix of reads and creates. GetItem reads
+// the IDs of items listed or created earlier in the run.
+type synthetic struct {
+	mu      sync.Mutex
+	random  *rand.Rand
+	methods []string
+	weights []int
+	total   int
+	ids     []uint64
+}
+
+var (
+	environments = []string{"dev", "staging", "prod"}
+	teams        = []string{"payments", "search", "platform", "growth"}
+)
+
+// parseMix parses a mix such as GetItems=20,GetItem=70,CreateItem=10.
+func parseMix(mix string) ([]string, []int, error) {
+	var methods []string
+	var weights []int
+
+	for _, part := range strings.Split(mix, ",") {
+		method, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		w, err := strconv.Atoi(weight)
+		if !ok || err != nil || w < 0 {
+			return nil, nil, fmt.Errorf("invalid mix entry %q: must be method=weight", part)
+		}
+
+		switch method {
+		case "GetItems", "GetItem", "CreateItem":
+		default:
+			return nil, nil, fmt.Errorf("invalid mix entry %q: the synthetic mix supports GetItems, GetItem and CreateItem", part)
+		}
+
+		if w > 0 {
+			methods = append(methods, method)
+			weights = append(weights, w)
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, nil, fmt.Errorf("invalid mix %q: no method has a positive weight", mix)
+	}
+
+	return methods, weights, nil
+}
+
+func newSynthetic(mix string, seed uint64) (*synthetic, error) {
+	methods, weights, err := parseMix(mix)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &synthetic{random: rand.New(rand.NewPCG(seed, seed)), methods: methods, weights: weights}
+	for _, w := range weights {
+		s.total += w
+	}
+
+	return s, nil
+}
+
+func (s *synthetic) next() (call, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	method := s.methods[len(s.methods)-1]
+	for i, n := 0, s.random.IntN(s.total); i < len(s.weights); i++ {
+		if n < s.weights[i] {
+			method = s.methods[i]
+			break
+		}
+		n -= s.weights[i]
+	}
+
+	// nothing to read by ID yet; list instead, which finds some
+	if method == "GetItem" && len(s.ids) == 0 {
+		method = "GetItems"
+	}
+
+	switch method {
+	case "GetItem":
+		return call{method: method, request: &types.GetItemRequest{Id: s.ids[s.random.IntN(len(s.ids))]}}, true
+	case "CreateItem":
+		n := s.random.Uint32()
+		return call{method: method, request: &types.CreateItemRequest{Item: &types.Item{
+			Name:        fmt.Sprintf("loadgen-%08x", n),
+			Description: "created by loadgen",
+			Labels: map[string]string{
+				"loadgen": "true",
+				"env":     environments[s.random.IntN(len(environments))],
+				"team":    teams[s.random.IntN(len(teams))],
+			},
+		}}}, true
+	default:
+		selector := "env=" + environments[s.random.IntN(len(environments))]
+		if s.random.IntN(2) == 0 {
+			selector += ",team=" + teams[s.random.IntN(len(teams))]
+		}
+		return call{method: method, request: &types.GetItemsRequest{LabelSelector: selector, PageSize: 100}}, true
+	}
+}
+
+// maxKnownIDs bounds the IDs kept for GetItem.
+const maxKnownIDs = 10000
+
+func (s *synthetic) observe(c call, response proto.Message) {
+	var ids []uint64
+
+	switch response := response.(type) {
+	case *types.CreateItemResponse:
+		ids = append(ids, response.ItemId)
+	case *types.GetItemsResponse:
+		for _, item := range response.Items {
+			ids = append(ids, item.Id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if len(s.ids) < maxKnownIDs {
+			s.ids = append(s.ids, id)
+		} else {
+			s.ids[s.random.IntN(maxKnownIDs)] = id
+		}
+	}
+}
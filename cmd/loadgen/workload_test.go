@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/recording"
+	"github.com/skip-mev/platform-take-home/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func writeRequests(t *testing.T, lines string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadReplay(t *testing.T) {
+	path := writeRequests(t, `{"method": "GetItems", "request": {"label_selector": "env=prod"}}
+
+{"method": "/skip.platform.api.TakeHomeService/GetItem", "namespace": "team-a", "request": {"id": "12"}, "code": "OK", "response": {"item": {"id": "12"}}}
+{"method": "GetWebhooks", "request": {}}
+`)
+
+	r, err := loadReplay(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(r.calls))
+	}
+
+	first, second := r.calls[0], r.calls[1]
+	if first.method != "GetItems" || first.recorded != nil || !proto.Equal(first.request, &types.GetItemsRequest{LabelSelector: "env=prod"}) {
+		t.Errorf("first call = %+v", first)
+	}
+	if second.method != "GetItem" || second.namespace != "team-a" || !proto.Equal(second.request, &types.GetItemRequest{Id: 12}) {
+		t.Errorf("second call = %+v", second)
+	}
+	if second.recorded == nil || second.recorded.code != "OK" || string(second.recorded.response) != `{"item": {"id": "12"}}` {
+		t.Errorf("second call recorded = %+v", second.recorded)
+	}
+}
+
+func TestLoadReplayInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		lines string
+		err   string
+	}{
+		{name: "invalid JSON", lines: "{}\n{", err: "requests.jsonl:2"},
+		{name: "invalid request", lines: `{"method": "GetItem", "request": {"colour": "red"}}`, err: "requests.jsonl:1"},
+		{name: "nothing to replay", lines: `{"method": "GetWebhooks"}`, err: "no requests"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := loadReplay(writeRequests(t, tc.lines), false); err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("loadReplay() error = %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestReplayLoop(t *testing.T) {
+	r := &replay{calls: []call{{method: "GetItem"}, {method: "GetItems"}}, loop: true}
+
+	var methods []string
+	for i := 0; i < 5; i++ {
+		c, ok := r.next()
+		if !ok {
+			t.Fatal("looping replay ended")
+		}
+		methods = append(methods, c.method)
+	}
+
+	if want := []string{"GetItem", "GetItems", "GetItem", "GetItems", "GetItem"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("replayed %q, want %q", methods, want)
+	}
+
+	r = &replay{calls: []call{{method: "GetItem"}}}
+	r.next()
+	if _, ok := r.next(); ok {
+		t.Error("replay continued past the end")
+	}
+}
+
+// TestReplayRecording replays what the server recorded against it, and
+// expects the same responses.
+func TestReplayRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	recorder, err := recording.New(recording.Config{Path: path, SampleRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { recorder.Close() })
+
+	s := testutil.Start(t, testutil.Config{Recorder: recorder})
+	ctx := context.Background()
+
+	created, err := s.Client.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Labels: map[string]string{"env": "prod"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Client.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Client.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId + 1000}); err == nil {
+		t.Fatal("got a missing item")
+	}
+	if _, err := s.Client.GetWebhooks(ctx, &types.EmptyRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := loadReplay(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(w.calls) != 3 {
+		t.Fatalf("loaded %d calls from the recording, want 3", len(w.calls))
+	}
+
+	var out bytes.Buffer
+	cmp := newComparer("item_id", &out, true)
+	stats := newStats()
+
+	generate(ctx, grpcTarget{client: s.Client}, w, stats, cmp, generateOptions{concurrency: 1, duration: time.Minute, timeout: 10 * time.Second})
+
+	report := stats.report("", "", time.Second)
+	if report.Total.Compared != 3 || report.Total.Mismatches != 0 {
+		t.Errorf("compared %d, %d mismatches, want 3 and none:\n%s", report.Total.Compared, report.Total.Mismatches, out.String())
+	}
+	if report.Total.Errors != 1 {
+		t.Errorf("%d errors, want the missing item's", report.Total.Errors)
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	methods, weights, err := parseMix("GetItems=20, GetItem=0,CreateItem=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(methods, []string{"GetItems", "CreateItem"}) || !reflect.DeepEqual(weights, []int{20, 10}) {
+		t.Errorf("parseMix() = %q, %v", methods, weights)
+	}
+
+	for _, mix := range []string{"GetItems", "GetItems=x", "GetItems=-1", "DeleteItem=1", "GetItem=0"} {
+		if _, _, err := parseMix(mix); err == nil {
+			t.Errorf("parseMix(%q): no error", mix)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/backup"
+	"github.com/skip-mev/platform-take-home/itemio"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+)
+
+func runBackup(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ExitOnError)
+	file := flags.String("file", "-", "archive to write, or - for stdout")
+	flags.Parse(args)
+
+	dbStore, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so a failed backup doesn't leave a
+	// partial archive that looks like a good one
+	out := io.Writer(os.Stdout)
+	var tmp *os.File
+	if *file != "-" {
+		tmp, err = os.CreateTemp(filepath.Dir(*file), filepath.Base(*file)+".*.tmp")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		out = tmp
+	}
+
+	writer, err := backup.NewWriter(out, backup.Manifest{
+		SchemaVersion: store.SchemaVersion,
+		Backend:       dbStore.Dialector.Name(),
+		CreatedAt:     time.Now().UTC(),
+		Tables:        store.BackupTables(),
+	})
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx).With(zap.String("file", *file))
+	progress := itemio.NewProgress(logger, progressInterval)
+
+	err = dbStore.Backup(ctx, func(table string, row []byte) error {
+		progress.Add(1)
+		return writer.WriteRow(table, row)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if tmp != nil {
+		if err := tmp.Sync(); err != nil {
+			return err
+		}
+
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp.Name(), *file); err != nil {
+			return err
+		}
+	}
+
+	progress.Done()
+
+	return nil
+}
+
+func runRestore(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	file := flags.String("file", "-", "archive to restore, or - for stdin")
+	flags.Parse(args)
+
+	in := io.Reader(os.Stdin)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	reader, err := backup.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	manifest := reader.Manifest()
+	if manifest.SchemaVersion > store.SchemaVersion {
+		return fmt.Errorf("backup has schema version %d, newer than this server's %d", manifest.SchemaVersion, store.SchemaVersion)
+	}
+
+	dbStore, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx).With(
+		zap.String("file", *file),
+		zap.String("backend", manifest.Backend),
+		zap.Time("created_at", manifest.CreatedAt),
+	)
+	progress := itemio.NewProgress(logger, progressInterval)
+
+	// checksums are only verified as each table ends, but the restore is a
+	// single transaction that a mismatch rolls back
+	err = dbStore.Restore(ctx, func(restore func(table string, row []byte) error) error {
+		return reader.ReadRows(func(table string, row []byte) error {
+			progress.Add(1)
+			return restore(table, row)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	progress.Done()
+
+	return nil
+}
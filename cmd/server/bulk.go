@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/service"
+	"github.com/skip-mev/platform-take-home/itemio"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	progressInterval = 5 * time.Second
+
+	// fingerprintSize is how much of the start of an import its fingerprint
+	// hashes.
+	fingerprintSize = 64 << 10
+)
+
+func runImport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("file", "-", "file to import, or - for stdin")
+	formatName := flags.String("format", "", "input format: jsonl, csv or proto (default: from the file extension)")
+	batchSize := flags.Int("batch-size", 500, "number of items committed per transaction")
+	source := flags.String("checkpoint", "", "name the import's progress is recorded under in the database, to resume it if interrupted (default: the file's absolute path)")
+	namespace := flags.String("namespace", tenancy.DefaultNamespace, "namespace to import the items into")
+	flags.Parse(args)
+
+	format, err := resolveFormat(*formatName, *file)
+	if err != nil {
+		return err
+	}
+
+	if err := tenancy.Validate(*namespace); err != nil {
+		return err
+	}
+	ctx = tenancy.WithNamespace(ctx, *namespace)
+
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch-size must be positive")
+	}
+
+	if *source == "" && *file != "-" {
+		if *source, err = filepath.Abs(*file); err != nil {
+			return err
+		}
+	}
+
+	in, size := os.Stdin, int64(-1)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		size = info.Size()
+	}
+
+	buffered := bufio.NewReaderSize(in, fingerprintSize)
+
+	var fingerprint string
+	if *source != "" {
+		if fingerprint, err = fingerprintOf(buffered, size); err != nil {
+			return err
+		}
+	}
+
+	reader, err := itemio.NewReader(buffered, format)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx).With(zap.String("file", *file), zap.String("format", string(format)))
+
+	dbStore, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the checkpoint moves in the transaction of each batch, so resuming
+	// from it never imports a row twice
+	var committed uint64
+	if *source != "" {
+		if committed, err = dbStore.ImportCheckpoint(ctx, *source, fingerprint); err != nil {
+			return err
+		}
+	}
+
+	if committed > 0 {
+		logger.Info("resuming import from checkpoint", zap.Uint64("skip", committed))
+
+		for i := uint64(0); i < committed; i++ {
+			if _, err := reader.Read(); err != nil {
+				return fmt.Errorf("error skipping checkpointed rows: %w", err)
+			}
+		}
+	}
+
+	validator := service.NewTakeHomeService(dbStore)
+	progress := itemio.NewProgress(logger, progressInterval)
+	batch := make([]store.Item, 0, *batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		var err error
+		if *source != "" {
+			err = dbStore.ImportItems(ctx, *source, fingerprint, committed, batch)
+		} else {
+			err = dbStore.UpsertItems(ctx, batch)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error importing rows %d-%d: %w", committed+1, committed+uint64(len(batch)), err)
+		}
+
+		committed += uint64(len(batch))
+		progress.Add(len(batch))
+		batch = batch[:0]
+
+		return nil
+	}
+
+	for {
+		item, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		storeItem := service.ItemFromAPI(item)
+		if err := storeItem.Labels.Validate(); err != nil {
+			return fmt.Errorf("row %d: %w", committed+uint64(len(batch))+1, err)
+		}
+
+		if err := validator.ValidateAttributes(ctx, &storeItem); err != nil {
+			return fmt.Errorf("row %d: %s", committed+uint64(len(batch))+1, status.Convert(err).Message())
+		}
+
+		batch = append(batch, storeItem)
+
+		if len(batch) == *batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	progress.Done()
+
+	if *source != "" {
+		return dbStore.DeleteImportCheckpoint(ctx, *source)
+	}
+
+	return nil
+}
+
+// fingerprintOf identifies the content of an import by its size, -1 if it's
+// unknown, and a hash of its first fingerprintSize bytes, which it peeks at
+// without consuming them. A checkpoint recorded for another fingerprint isn't
+// resumed, so replacing the file under the same name starts over.
+func fingerprintOf(in *bufio.Reader, size int64) (string, error) {
+	head, err := in.Peek(fingerprintSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	sum := sha256.Sum256(head)
+
+	return fmt.Sprintf("%d:%x", size, sum[:16]), nil
+}
+
+func runExport(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	file := flags.String("file", "-", "file to write, or - for stdout")
+	formatName := flags.String("format", "", "output format: jsonl, csv or proto (default: from the file extension)")
+	batchSize := flags.Int("batch-size", 500, "number of items fetched per query")
+	namespace := flags.String("namespace", tenancy.DefaultNamespace, "namespace to export the items of")
+	flags.Parse(args)
+
+	format, err := resolveFormat(*formatName, *file)
+	if err != nil {
+		return err
+	}
+
+	if err := tenancy.Validate(*namespace); err != nil {
+		return err
+	}
+	ctx = tenancy.WithNamespace(ctx, *namespace)
+
+	if *batchSize <= 0 {
+		return fmt.Errorf("batch-size must be positive")
+	}
+
+	dbStore, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if *file != "-" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	writer, err := itemio.NewWriter(out, format)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx).With(zap.String("file", *file), zap.String("format", string(format)))
+	progress := itemio.NewProgress(logger, progressInterval)
+
+	err = dbStore.StreamItems(ctx, *batchSize, func(item *store.Item) error {
+		progress.Add(1)
+		return writer.Write(service.ItemToAPI(item))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	progress.Done()
+
+	return nil
+}
+
+func resolveFormat(name, file string) (itemio.Format, error) {
+	if name != "" {
+		return itemio.ParseFormat(name)
+	}
+
+	return itemio.FormatFromPath(file), nil
+}
+
+func openStore(ctx context.Context) (*store.DBStore, error) {
+	dbStore, err := store.NewStoreFromEnv(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating database connection: %w", err)
+	}
+
+	if err := dbStore.Migrate(); err != nil {
+		return nil, fmt.Errorf("error migrating database: %w", err)
+	}
+
+	return dbStore, nil
+}
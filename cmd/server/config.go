@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/server"
+	"github.com/skip-mev/platform-take-home/api/service"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/recording"
+	"github.com/skip-mev/platform-take-home/seed"
+	"github.com/skip-mev/platform-take-home/store"
+)
+
+// effectiveConfig collects the config serve runs with, read from the
+// environment as the packages using it read it, for the admin server's
+// /config, which redacts it.
+func effectiveConfig(listeners map[string]net.Listener, shutdown server.ShutdownConfig, tlsConfig server.TLSConfig, recordingConfig recording.Config) (map[string]any, error) {
+	addresses := map[string]string{}
+	for name, listener := range listeners {
+		addresses[name] = listener.Addr().String()
+	}
+
+	database := map[string]any{}
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		dbConfig, err := store.DBConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		var replicaDSNs []string
+		for _, replicaDSN := range strings.Split(os.Getenv("POSTGRES_REPLICA_DSNS"), ",") {
+			if replicaDSN = strings.TrimSpace(replicaDSN); replicaDSN != "" {
+				replicaDSNs = append(replicaDSNs, replicaDSN)
+			}
+		}
+
+		database["PostgresDSN"] = dsn
+		database["PostgresReplicaDSNs"] = replicaDSNs
+		database["Connections"] = dbConfig
+	} else {
+		sqliteConfig, err := store.SQLiteConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		database["SQLite"] = sqliteConfig
+	}
+
+	cacheConfig, err := store.CacheConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	seedConfig, err := seed.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	loggingConfig, err := logging.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	webhookConfig, err := service.WebhookConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"Listeners": addresses,
+		"Database":  database,
+		"ItemCache": cacheConfig,
+		"Seed":      seedConfig,
+		"Recording": recordingConfig,
+		"Shutdown":  shutdown,
+		"TLS":       tlsConfig,
+		"Logging":   loggingConfig,
+		"Webhooks":  webhookConfig,
+		"Admin":     service.AdminConfigFromEnv(),
+	}, nil
+}
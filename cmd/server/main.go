@@ -2,15 +2,35 @@ package main
 
 import (
 	"context"
-	"github.com/skip-mev/platform-take-home/api/server"
+	"fmt"
 	"github.com/skip-mev/platform-take-home/observability/logging"
-	"github.com/skip-mev/platform-take-home/observability/metrics"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
+	"os"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
+	"time"
 )
 
+// logFlushTimeout bounds how long exiting waits for logs to be exported
+// over OTLP.
+const logFlushTimeout = 5 * time.Second
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"serve":   {usage: "run the gRPC server, REST gateway and metrics server (default)", run: runServe},
+	"import":  {usage: "bulk import items from a JSONL, CSV or protobuf file", run: runImport},
+	"export":  {usage: "bulk export items to a JSONL, CSV or protobuf file", run: runExport},
+	"backup":  {usage: "write a consistent archive of every store table", run: runBackup},
+	"restore": {usage: "load a backup archive into an empty store", run: runRestore},
+	"seed":    {usage: "load fixture files or generate synthetic items, idempotently", run: runSeed},
+}
+
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
@@ -21,31 +41,44 @@ func main() {
 		panic(err)
 	}
 
-	eg, ctx := errgroup.WithContext(ctx)
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
 
-	eg.Go(func() error {
-		grpcServer := server.NewServer()
-		grpcServer.Start(ctx, "0.0.0.0:9008")
-		return nil
-	})
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		os.Exit(2)
+	}
 
-	eg.Go(func() error {
-		if err := server.StartGRPCGateway(ctx, "0.0.0.0", 8080); err != nil {
-			return err
-		}
+	err = cmd.run(ctx, args)
+	if err != nil {
+		logging.FromContext(ctx).Error("command failed", zap.String("command", name), zap.Error(err))
+	}
 
-		return nil
-	})
+	// export the logs still buffered, including the error, before exiting
+	flushCtx, cancel := context.WithTimeout(context.Background(), logFlushTimeout)
+	defer cancel()
+	if err := logging.Shutdown(flushCtx); err != nil {
+		fmt.Fprintln(os.Stderr, "error flushing logs:", err)
+	}
 
-	eg.Go(func() error {
-		if err := metrics.ServeMetrics(ctx, "0.0.0.0", 8081); err != nil {
-			return err
-		}
+	if err != nil {
+		os.Exit(1)
+	}
+}
 
-		return nil
-	})
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	if err := eg.Wait(); err != nil {
-		logging.FromContext(ctx).Fatal("error during startup", zap.Error(err))
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].usage)
 	}
 }
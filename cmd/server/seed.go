@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/service"
+	"github.com/skip-mev/platform-take-home/seed"
+	"github.com/skip-mev/platform-take-home/tenancy"
+)
+
+// fileList collects a repeatable flag.
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func runSeed(ctx context.Context, args []string) error {
+	var config seed.Config
+
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	flags.Var((*fileList)(&config.Files), "file", "YAML or JSON fixture file to load; may be repeated")
+	flags.IntVar(&config.Generate, "generate", 0, "number of synthetic items to generate")
+	flags.Uint64Var(&config.RandomSeed, "random-seed", 1, "seed of the synthetic items; the same seed yields the same items")
+	flags.StringVar(&config.Namespace, "namespace", tenancy.DefaultNamespace, "namespace of the synthetic items")
+	flags.Parse(args)
+
+	if err := tenancy.Validate(config.Namespace); err != nil {
+		return err
+	}
+
+	dbStore, err := openStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = seed.Run(ctx, dbStore, service.NewTakeHomeService(dbStore).ValidateAttributes, config)
+
+	return err
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/skip-mev/platform-take-home/api/server"
+	"github.com/skip-mev/platform-take-home/handoff"
+	"github.com/skip-mev/platform-take-home/observability/admin"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/observability/metrics"
+	"github.com/skip-mev/platform-take-home/recording"
+	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	grpcAddress    = "0.0.0.0:9008"
+	gatewayAddress = "0.0.0.0:8080"
+	metricsAddress = "0.0.0.0:8081"
+
+	// upgradeTimeout bounds how long a new process handed the listeners
+	// has to connect to the database, migrate it and start serving.
+	upgradeTimeout = 2 * time.Minute
+)
+
+func runServe(ctx context.Context, _ []string) error {
+	logger := logging.FromContext(ctx)
+	defer logger.Sync()
+
+	shutdownConfig, err := server.ShutdownConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := server.TLSConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	if !tlsConfig.Authenticated() {
+		logger.Warn("TLS_CLIENT_CA_FILE is unset, so clients choose their namespace with the " + tenancy.Header + " header unauthenticated")
+	}
+
+	recordingConfig, err := recording.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	recorder, err := recording.New(recordingConfig)
+	if err != nil {
+		return err
+	}
+	defer recorder.Close()
+
+	// the store and the gRPC server record into the global meter provider
+	meterProvider, err := metrics.NewProvider()
+	if err != nil {
+		return err
+	}
+	otel.SetMeterProvider(meterProvider)
+
+	adminConfig, err := admin.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// bind every port before serving any, so that a port in use stops the
+	// process before it starts taking requests
+	listeners, err := handoff.Listen(map[string]string{
+		"grpc":    grpcAddress,
+		"gateway": gatewayAddress,
+		"metrics": metricsAddress,
+		"admin":   adminConfig.Address,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, listener := range listeners {
+		defer listener.Close()
+	}
+
+	config, err := effectiveConfig(listeners, shutdownConfig, tlsConfig, recordingConfig)
+	if err != nil {
+		return err
+	}
+
+	dbStore, err := store.NewStoreFromEnv(ctx)
+	if err != nil {
+		return fmt.Errorf("error creating database connection: %w", err)
+	}
+
+	// closed last, once the servers and their background work have stopped
+	defer func() {
+		if err := dbStore.Close(); err != nil {
+			logger.Error("error closing database", zap.Error(err))
+		}
+	}()
+
+	// ctx is done on SIGINT or SIGTERM, but the servers are stopped one
+	// after another by shutdown instead
+	serveCtx := context.WithoutCancel(ctx)
+	// the metrics and admin servers stop last, to watch the others stop
+	telemetryCtx, stopTelemetry := context.WithCancel(serveCtx)
+	defer stopTelemetry()
+
+	grpcServer, err := server.NewServer(logger, recorder, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	gateway, err := server.NewGateway(serveCtx, localEndpoint(listeners["grpc"].Addr()), recorder, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	// listen before reporting readiness, after which SIGUSR2 may arrive
+	upgrades := make(chan os.Signal, 1)
+	signal.Notify(upgrades, syscall.SIGUSR2)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error {
+		return grpcServer.Serve(serveCtx, listeners["grpc"], dbStore)
+	})
+
+	eg.Go(func() error {
+		return gateway.Serve(listeners["gateway"])
+	})
+
+	eg.Go(func() error {
+		return metrics.Serve(telemetryCtx, listeners["metrics"], meterProvider)
+	})
+
+	eg.Go(func() error {
+		return admin.Serve(telemetryCtx, listeners["admin"], config)
+	})
+
+	eg.Go(func() error {
+		select {
+		case <-grpcServer.Serving():
+		case <-egCtx.Done():
+			return nil
+		}
+
+		if err := handoff.Ready(); err != nil {
+			logger.Error("error reporting readiness", zap.Error(err))
+		}
+		return nil
+	})
+
+	handedOff := make(chan struct{})
+
+	eg.Go(func() error {
+		// a SIGUSR2 mustn't kill the process once it's stopping
+		defer signal.Ignore(syscall.SIGUSR2)
+
+		for {
+			select {
+			case <-upgrades:
+			case <-egCtx.Done():
+				return nil
+			}
+
+			logger.Info("handing listeners off to a new process")
+
+			upgradeCtx, cancel := context.WithTimeout(egCtx, upgradeTimeout)
+			err := handoff.Upgrade(upgradeCtx, listeners)
+			cancel()
+
+			if err != nil {
+				logger.Error("error handing off listeners", zap.Error(err))
+				continue
+			}
+
+			close(handedOff)
+			return nil
+		}
+	})
+
+	eg.Go(func() error {
+		drain := false
+
+		select {
+		case <-egCtx.Done():
+			// drain on a signal, but not when a server failed
+			drain = ctx.Err() != nil
+		case <-handedOff:
+			// the new process accepts connections from now on, so there's
+			// nothing to drain
+			logger.Info("handed listeners off, stopping")
+		}
+
+		shutdown(logger, shutdownConfig, grpcServer, gateway, drain)
+		stopTelemetry()
+		return nil
+	})
+
+	return eg.Wait()
+}
+
+// shutdown, if drain is set, marks the servers unready and waits out the
+// drain period. Then it stops the gateway, so that no new requests reach the
+// gRPC server, and the gRPC server, each gracefully until the shutdown
+// timeout.
+func shutdown(logger *zap.Logger, config server.ShutdownConfig, grpcServer *server.Server, gateway *server.Gateway, drain bool) {
+	if drain {
+		grpcServer.MarkUnready()
+
+		logger.Info("draining before shutdown", zap.Duration("drain_period", config.DrainPeriod))
+		time.Sleep(config.DrainPeriod)
+	}
+
+	logger.Info("stopping gateway")
+	if err := stop(gateway.Shutdown, config.Timeout); err != nil {
+		logger.Warn("gateway didn't stop gracefully", zap.Error(err))
+	}
+
+	logger.Info("stopping grpc server")
+	if err := stop(grpcServer.Shutdown, config.Timeout); err != nil {
+		logger.Warn("grpc server didn't stop gracefully", zap.Error(err))
+	}
+}
+
+// localEndpoint is the address to reach a listener on from this host: the
+// loopback address if it listens on every interface. Inherited listeners
+// may be on other addresses and ports than the default ones.
+func localEndpoint(addr net.Addr) string {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsUnspecified() {
+		return addr.String()
+	}
+
+	return net.JoinHostPort("localhost", strconv.Itoa(tcpAddr.Port))
+}
+
+func stop(shutdown func(context.Context) error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return shutdown(ctx)
+}
@@ -0,0 +1,449 @@
+// Package e2e tests the whole server, booted in process by testutil, through
+// its public APIs: gRPC and the REST gateway.
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/server"
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/client"
+	"github.com/skip-mev/platform-take-home/requestid"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"github.com/skip-mev/platform-take-home/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// forEachTransport runs test against a fresh server, once over gRPC and once
+// over the REST gateway.
+func forEachTransport(t *testing.T, test func(t *testing.T, c types.TakeHomeServiceClient, s *testutil.Server)) {
+	t.Run("grpc", func(t *testing.T) {
+		s := testutil.Start(t, testutil.Config{})
+		test(t, s.Client, s)
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		s := testutil.Start(t, testutil.Config{})
+		test(t, newRESTClient(s), s)
+	})
+}
+
+func TestCreateAndGetItem(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		created, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{
+			Name:        "Kettle",
+			Description: "Boils water",
+			Labels:      map[string]string{"env": "prod"},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &types.Item{
+			Id:           created.ItemId,
+			Name:         "Kettle",
+			Description:  "Boils water",
+			Labels:       map[string]string{"env": "prod"},
+			Namespace:    tenancy.DefaultNamespace,
+			ResourceName: fmt.Sprintf("namespaces/%s/items/%d", tenancy.DefaultNamespace, created.ItemId),
+		}
+		assertItem(t, got.Item, want)
+
+		masked, err := c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId, ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertItem(t, masked.Item, &types.Item{Id: created.ItemId, Name: "Kettle"})
+
+		_, err = c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId + 1})
+		assertCode(t, err, codes.NotFound)
+
+		_, err = c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Bad", Labels: map[string]string{"not a key": "x"}}})
+		assertCode(t, err, codes.InvalidArgument)
+	})
+}
+
+func TestGetItems(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		var prod []uint64
+		for i := 0; i < 5; i++ {
+			env := "prod"
+			if i%2 == 1 {
+				env = "dev"
+			}
+
+			created, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: fmt.Sprintf("item-%d", i), Labels: map[string]string{"env": env}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if env == "prod" {
+				prod = append(prod, created.ItemId)
+			}
+		}
+
+		res, err := c.GetItems(ctx, &types.GetItemsRequest{LabelSelector: "env=prod"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "env=prod", res.Items, prod)
+
+		// page through every item, two at a time
+		var all []*types.Item
+		pages := 0
+		for token := ""; ; {
+			res, err := c.GetItems(ctx, &types.GetItemsRequest{PageSize: 2, PageToken: token})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			all = append(all, res.Items...)
+			pages++
+
+			if token = res.NextPageToken; token == "" {
+				break
+			}
+		}
+
+		if len(all) != 5 || pages != 3 {
+			t.Errorf("paging: got %d items in %d pages, want 5 in 3", len(all), pages)
+		}
+
+		_, err = c.GetItems(ctx, &types.GetItemsRequest{LabelSelector: "env in (prod"})
+		assertCode(t, err, codes.InvalidArgument)
+
+		_, err = c.GetItems(ctx, &types.GetItemsRequest{PageToken: "not a token"})
+		assertCode(t, err, codes.InvalidArgument)
+	})
+}
+
+func TestUpdateItem(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		created, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Description: "Boils water", Labels: map[string]string{"env": "prod"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// only the masked name is written
+		updated, err := c.UpdateItem(ctx, &types.UpdateItemRequest{
+			Item:       &types.Item{Id: created.ItemId, Name: "Electric kettle"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if updated.Item.Name != "Electric kettle" || updated.Item.Description != "Boils water" || updated.Item.Labels["env"] != "prod" {
+			t.Errorf("partial update: got %v", updated.Item)
+		}
+
+		// no mask replaces every field
+		if _, err := c.UpdateItem(ctx, &types.UpdateItemRequest{Item: &types.Item{Id: created.ItemId, Name: "Teapot"}}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Item.Name != "Teapot" || got.Item.Description != "" || len(got.Item.Labels) != 0 {
+			t.Errorf("full update: got %v", got.Item)
+		}
+
+		_, err = c.UpdateItem(ctx, &types.UpdateItemRequest{Item: &types.Item{Id: created.ItemId + 1, Name: "Missing"}})
+		assertCode(t, err, codes.NotFound)
+
+		_, err = c.UpdateItem(ctx, &types.UpdateItemRequest{
+			Item:       &types.Item{Id: created.ItemId},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"id"}},
+		})
+		assertCode(t, err, codes.InvalidArgument)
+	})
+}
+
+func TestDeleteItem(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		created, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.DeleteItem(ctx, &types.DeleteItemRequest{Id: created.ItemId}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		assertCode(t, err, codes.NotFound)
+
+		_, err = c.DeleteItem(ctx, &types.DeleteItemRequest{Id: created.ItemId})
+		assertCode(t, err, codes.NotFound)
+	})
+}
+
+func TestSearchItems(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		for _, name := range []string{"Red bicycle", "Kettle"} {
+			if _, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: name}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		res, err := c.SearchItems(ctx, &types.SearchItemsRequest{Q: "bicy", Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(res.Results) != 1 || res.Results[0].Item.Name != "Red bicycle" || res.Results[0].NameHighlight != "Red <mark>bicycle</mark>" {
+			t.Errorf("got %v, want the bicycle, highlighted", res.Results)
+		}
+	})
+}
+
+func TestItemKinds(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "s3cret")
+
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+		admin := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer s3cret")
+
+		schema, err := structpb.NewStruct(map[string]interface{}{
+			"type":       "object",
+			"required":   []interface{}{"color"},
+			"properties": map[string]interface{}{"color": map[string]interface{}{"type": "string"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = c.PutItemKind(ctx, &types.PutItemKindRequest{Kind: &types.ItemKind{Name: "gadget", Schema: schema}})
+		assertCode(t, err, codes.PermissionDenied)
+
+		wrong := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer guess")
+		_, err = c.PutItemKind(wrong, &types.PutItemKindRequest{Kind: &types.ItemKind{Name: "gadget", Schema: schema}})
+		assertCode(t, err, codes.PermissionDenied)
+
+		if _, err := c.PutItemKind(admin, &types.PutItemKindRequest{Kind: &types.ItemKind{Name: "gadget", Schema: schema}}); err != nil {
+			t.Fatal(err)
+		}
+
+		kinds, err := c.GetItemKinds(ctx, &types.EmptyRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(kinds.Kinds) != 1 || kinds.Kinds[0].Name != "gadget" || !proto.Equal(kinds.Kinds[0].Schema, schema) {
+			t.Errorf("GetItemKinds: got %v", kinds.Kinds)
+		}
+
+		valid, err := structpb.NewStruct(map[string]interface{}{"color": "red"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		created, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Kind: "gadget", Attributes: valid}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := c.GetItems(ctx, &types.GetItemsRequest{AttributeFilter: `color="red"`})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "color=red", res.Items, []uint64{created.ItemId})
+
+		invalid, err := structpb.NewStruct(map[string]interface{}{"color": 1.0})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Kind: "gadget", Attributes: invalid}})
+		assertCode(t, err, codes.InvalidArgument)
+
+		_, err = c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle", Kind: "unknown"}})
+		assertCode(t, err, codes.InvalidArgument)
+
+		_, err = c.PutItemKind(admin, &types.PutItemKindRequest{Kind: &types.ItemKind{Name: "Not a name"}})
+		assertCode(t, err, codes.InvalidArgument)
+	})
+}
+
+func TestNamespaces(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		teamA := metadata.AppendToOutgoingContext(context.Background(), tenancy.Header, "team-a")
+		teamB := metadata.AppendToOutgoingContext(context.Background(), tenancy.Header, "team-b")
+
+		created, err := c.CreateItem(teamA, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.GetItem(teamA, &types.GetItemRequest{Id: created.ItemId})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Item.Namespace != "team-a" {
+			t.Errorf("got namespace %q, want team-a", got.Item.Namespace)
+		}
+
+		_, err = c.GetItem(teamB, &types.GetItemRequest{Id: created.ItemId})
+		assertCode(t, err, codes.NotFound)
+
+		res, err := c.GetItems(teamB, &types.GetItemsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, "team-b", res.Items, nil)
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+
+	t.Run("grpc", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), requestid.Header, "req-1")
+
+		var header metadata.MD
+		_, err := s.Client.GetItem(ctx, &types.GetItemRequest{Id: 999}, grpc.Header(&header))
+
+		var clientErr *client.Error
+		if !errors.As(err, &clientErr) || clientErr.RequestID != "req-1" {
+			t.Errorf("got error %v, want one with request ID req-1", err)
+		}
+		if got := header.Get(requestid.Header); len(got) != 1 || got[0] != "req-1" {
+			t.Errorf("got request ID header %v, want req-1", got)
+		}
+
+		_, err = s.Client.GetItem(context.Background(), &types.GetItemRequest{Id: 999})
+		if !errors.As(err, &clientErr) || clientErr.RequestID == "" {
+			t.Errorf("got error %v, want one with a generated request ID", err)
+		}
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, s.URL+"/items/999", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set(requestid.Header, "req-2")
+
+		res, err := s.HTTP.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := res.Header.Values(requestid.Header); len(got) != 1 || got[0] != "req-2" {
+			t.Errorf("got request ID header %v, want req-2", got)
+		}
+		if !strings.Contains(string(body), `"request_id":"req-2"`) {
+			t.Errorf("got %s: %s, want the request ID in the details", res.Status, body)
+		}
+
+		// IDs are generated for requests that don't reach the gRPC server too
+		res, err = s.HTTP.Get(s.URL + "/no-such-route")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if res.Header.Get(requestid.Header) == "" {
+			t.Errorf("got %s without a request ID header", res.Status)
+		}
+	})
+}
+
+func TestConsistencyHeaders(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		created, err := c.CreateItem(context.Background(), &types.CreateItemRequest{Item: &types.Item{Name: "Kettle"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, tc := range []struct {
+			header, value string
+			want          codes.Code
+		}{
+			{header: server.ReadConsistencyHeader, value: "primary", want: codes.OK},
+			{header: server.ReadConsistencyHeader, value: "eventual", want: codes.InvalidArgument},
+			{header: server.ConsistencyTokenHeader, value: "0/0", want: codes.OK},
+			{header: server.ConsistencyTokenHeader, value: "16/B374D848", want: codes.OK},
+			{header: server.ConsistencyTokenHeader, value: "latest", want: codes.InvalidArgument},
+		} {
+			t.Run(tc.header+"="+tc.value, func(t *testing.T) {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), tc.header, tc.value)
+
+				got, err := c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+				assertCode(t, err, tc.want)
+				if err == nil && got.Item.Name != "Kettle" {
+					t.Errorf("got item %q, want Kettle", got.Item.Name)
+				}
+			})
+		}
+	})
+}
+
+func assertItem(t *testing.T, got, want *types.Item) {
+	t.Helper()
+
+	if !proto.Equal(got, want) {
+		t.Errorf("got item %v, want %v", got, want)
+	}
+}
+
+func assertIDs(t *testing.T, name string, items []*types.Item, want []uint64) {
+	t.Helper()
+
+	got := make([]uint64, 0, len(items))
+	for _, item := range items {
+		got = append(got, item.Id)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s: got IDs %v, want %v", name, got, want)
+	}
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Errorf("got %v (%v), want %v", got, err, want)
+	}
+}
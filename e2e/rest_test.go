@@ -0,0 +1,204 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/testutil"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// restClient calls the unary RPCs through the gateway, so that the same tests
+// run over both transports. Outgoing gRPC metadata is sent as headers, and
+// gateway errors are turned back into gRPC status errors. Streams differ too
+// much between the transports and are tested separately.
+type restClient struct {
+	http *http.Client
+	url  string
+}
+
+var _ types.TakeHomeServiceClient = restClient{}
+
+func newRESTClient(s *testutil.Server) restClient {
+	return restClient{http: s.HTTP, url: s.URL}
+}
+
+func (c restClient) GetItems(ctx context.Context, in *types.GetItemsRequest, _ ...grpc.CallOption) (*types.GetItemsResponse, error) {
+	query := url.Values{}
+	set(query, "label_selector", in.LabelSelector)
+	set(query, "attribute_filter", in.AttributeFilter)
+	set(query, "read_mask", paths(in.ReadMask))
+	set(query, "page_token", in.PageToken)
+	if in.PageSize > 0 {
+		query.Set("page_size", strconv.FormatUint(uint64(in.PageSize), 10))
+	}
+
+	out := &types.GetItemsResponse{}
+	return out, c.do(ctx, http.MethodGet, "/items", query, nil, out)
+}
+
+func (c restClient) GetItem(ctx context.Context, in *types.GetItemRequest, _ ...grpc.CallOption) (*types.GetItemResponse, error) {
+	query := url.Values{}
+	set(query, "read_mask", paths(in.ReadMask))
+
+	out := &types.GetItemResponse{}
+	return out, c.do(ctx, http.MethodGet, fmt.Sprintf("/items/%d", in.Id), query, nil, out)
+}
+
+func (c restClient) CreateItem(ctx context.Context, in *types.CreateItemRequest, _ ...grpc.CallOption) (*types.CreateItemResponse, error) {
+	out := &types.CreateItemResponse{}
+	return out, c.do(ctx, http.MethodPost, "/items", nil, in, out)
+}
+
+func (c restClient) SearchItems(ctx context.Context, in *types.SearchItemsRequest, _ ...grpc.CallOption) (*types.SearchItemsResponse, error) {
+	query := url.Values{}
+	set(query, "q", in.Q)
+	if in.Limit > 0 {
+		query.Set("limit", strconv.FormatUint(uint64(in.Limit), 10))
+	}
+
+	out := &types.SearchItemsResponse{}
+	return out, c.do(ctx, http.MethodGet, "/items:search", query, nil, out)
+}
+
+func (c restClient) UpdateItem(ctx context.Context, in *types.UpdateItemRequest, _ ...grpc.CallOption) (*types.UpdateItemResponse, error) {
+	query := url.Values{}
+	set(query, "update_mask", paths(in.UpdateMask))
+
+	out := &types.UpdateItemResponse{}
+	return out, c.do(ctx, http.MethodPut, fmt.Sprintf("/items/%d", in.GetItem().GetId()), query, in.Item, out)
+}
+
+func (c restClient) DeleteItem(ctx context.Context, in *types.DeleteItemRequest, _ ...grpc.CallOption) (*types.DeleteItemResponse, error) {
+	out := &types.DeleteItemResponse{}
+	return out, c.do(ctx, http.MethodDelete, fmt.Sprintf("/items/%d", in.Id), nil, nil, out)
+}
+
+func (c restClient) WatchItems(context.Context, *types.WatchItemsRequest, ...grpc.CallOption) (grpc.ServerStreamingClient[types.WatchItemsResponse], error) {
+	return nil, status.Error(codes.Unimplemented, "streams are tested per transport")
+}
+
+func (c restClient) ImportItems(context.Context, ...grpc.CallOption) (grpc.BidiStreamingClient[types.ImportItemsRequest, types.ImportItemsResponse], error) {
+	return nil, status.Error(codes.Unimplemented, "streams are tested per transport")
+}
+
+func (c restClient) ExportItems(context.Context, *types.ExportItemsRequest, ...grpc.CallOption) (grpc.ServerStreamingClient[types.ExportItemsResponse], error) {
+	return nil, status.Error(codes.Unimplemented, "streams are tested per transport")
+}
+
+func (c restClient) PutItemKind(ctx context.Context, in *types.PutItemKindRequest, _ ...grpc.CallOption) (*types.PutItemKindResponse, error) {
+	out := &types.PutItemKindResponse{}
+	return out, c.do(ctx, http.MethodPut, "/kinds/"+url.PathEscape(in.GetKind().GetName()), nil, in.Kind, out)
+}
+
+func (c restClient) GetItemKinds(ctx context.Context, _ *types.EmptyRequest, _ ...grpc.CallOption) (*types.GetItemKindsResponse, error) {
+	out := &types.GetItemKindsResponse{}
+	return out, c.do(ctx, http.MethodGet, "/kinds", nil, nil, out)
+}
+
+func (c restClient) CreateWebhook(ctx context.Context, in *types.CreateWebhookRequest, _ ...grpc.CallOption) (*types.CreateWebhookResponse, error) {
+	out := &types.CreateWebhookResponse{}
+	return out, c.do(ctx, http.MethodPost, "/webhooks", nil, in, out)
+}
+
+func (c restClient) GetWebhooks(ctx context.Context, _ *types.EmptyRequest, _ ...grpc.CallOption) (*types.GetWebhooksResponse, error) {
+	out := &types.GetWebhooksResponse{}
+	return out, c.do(ctx, http.MethodGet, "/webhooks", nil, nil, out)
+}
+
+func (c restClient) DeleteWebhook(ctx context.Context, in *types.DeleteWebhookRequest, _ ...grpc.CallOption) (*types.DeleteWebhookResponse, error) {
+	out := &types.DeleteWebhookResponse{}
+	return out, c.do(ctx, http.MethodDelete, fmt.Sprintf("/webhooks/%d", in.Id), nil, nil, out)
+}
+
+func (c restClient) GetWebhookDeliveries(ctx context.Context, in *types.GetWebhookDeliveriesRequest, _ ...grpc.CallOption) (*types.GetWebhookDeliveriesResponse, error) {
+	query := url.Values{}
+	if in.Status != types.DeliveryStatus_DELIVERY_STATUS_UNSPECIFIED {
+		query.Set("status", in.Status.String())
+	}
+	if in.Limit > 0 {
+		query.Set("limit", strconv.FormatUint(uint64(in.Limit), 10))
+	}
+
+	out := &types.GetWebhookDeliveriesResponse{}
+	return out, c.do(ctx, http.MethodGet, fmt.Sprintf("/webhooks/%d/deliveries", in.WebhookId), query, nil, out)
+}
+
+func (c restClient) RedeliverWebhookDelivery(ctx context.Context, in *types.RedeliverWebhookDeliveryRequest, _ ...grpc.CallOption) (*types.RedeliverWebhookDeliveryResponse, error) {
+	out := &types.RedeliverWebhookDeliveryResponse{}
+	return out, c.do(ctx, http.MethodPost, fmt.Sprintf("/webhooks/deliveries/%d:redeliver", in.Id), nil, nil, out)
+}
+
+// do sends body, if any, as JSON and decodes the response into out.
+func (c restClient) do(ctx context.Context, method, path string, query url.Values, body, out proto.Message) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := protojson.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	target := c.url + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var s spb.Status
+		if err := protojson.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("%s %s: %s: %s", method, path, res.Status, data)
+		}
+		return status.ErrorProto(&s)
+	}
+
+	return protojson.Unmarshal(data, out)
+}
+
+func set(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func paths(mask *fieldmaskpb.FieldMask) string {
+	return strings.Join(mask.GetPaths(), ",")
+}
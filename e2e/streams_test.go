@@ -0,0 +1,364 @@
+package e2e
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/testutil"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var importedItems = []*types.Item{
+	{Name: "Kettle", Labels: map[string]string{"env": "prod"}},
+	{Name: "Teapot"},
+	{Name: "Toaster", Description: "Two slots"},
+}
+
+func TestImportExportGRPC(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+	ctx := context.Background()
+
+	stream, err := s.Client.ImportItems(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range importedItems {
+		if err := stream.Send(&types.ImportItemsRequest{Item: item}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	var imported uint64
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		imported = res.Imported
+	}
+
+	if imported != uint64(len(importedItems)) {
+		t.Errorf("imported %d items, want %d", imported, len(importedItems))
+	}
+
+	export, err := s.Client.ExportItems(ctx, &types.ExportItemsRequest{BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported []*types.Item
+	for {
+		res, err := export.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		exported = append(exported, res.Item)
+	}
+
+	assertExported(t, exported)
+}
+
+func TestImportExportREST(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+
+	var body strings.Builder
+	for _, item := range importedItems {
+		line, err := protojson.Marshal(&types.ImportItemsRequest{Item: item})
+		if err != nil {
+			t.Fatal(err)
+		}
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+
+	res, err := s.HTTP.Post(s.URL+"/items:import", "application/json", strings.NewReader(body.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var imported uint64
+	readStream(t, res, func(result []byte) bool {
+		progress := &types.ImportItemsResponse{}
+		if err := protojson.Unmarshal(result, progress); err != nil {
+			t.Fatal(err)
+		}
+		imported = progress.Imported
+		return true
+	})
+
+	if imported != uint64(len(importedItems)) {
+		t.Errorf("imported %d items, want %d", imported, len(importedItems))
+	}
+
+	res, err = s.HTTP.Get(s.URL + "/items:export?batch_size=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exported []*types.Item
+	readStream(t, res, func(result []byte) bool {
+		item := &types.ExportItemsResponse{}
+		if err := protojson.Unmarshal(result, item); err != nil {
+			t.Fatal(err)
+		}
+		exported = append(exported, item.Item)
+		return true
+	})
+
+	assertExported(t, exported)
+}
+
+func TestWatchItemsGRPC(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id := createItem(t, s, "Kettle")
+
+	stream, err := s.Client.WatchItems(ctx, &types.WatchItemsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() *types.WatchItemsResponse {
+		t.Helper()
+
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+
+	assertEvent(t, next(), types.EventType_EVENT_TYPE_ADDED, id)
+
+	changeItem(t, s, id)
+	modified := next()
+	assertEvent(t, modified, types.EventType_EVENT_TYPE_MODIFIED, id)
+	assertEvent(t, next(), types.EventType_EVENT_TYPE_DELETED, id)
+
+	// resuming skips what was seen
+	cancel()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err = s.Client.WatchItems(ctx, &types.WatchItemsRequest{ResourceVersion: modified.ResourceVersion})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEvent(t, next(), types.EventType_EVENT_TYPE_DELETED, id)
+}
+
+func TestWatchItemsREST(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+
+	id := createItem(t, s, "Kettle")
+
+	for _, accept := range []string{"application/json", "text/event-stream"} {
+		t.Run(accept, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/items:watch?resource_version=0", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Accept", accept)
+
+			res, err := s.HTTP.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var events []*types.WatchItemsResponse
+			readStream(t, res, func(result []byte) bool {
+				event := &types.WatchItemsResponse{}
+				if err := protojson.Unmarshal(result, event); err != nil {
+					t.Fatal(err)
+				}
+				events = append(events, event)
+
+				if len(events) == 1 {
+					changeItem(t, s, id)
+				}
+
+				// the stream stays open until the client leaves
+				return len(events) < 3
+			})
+
+			assertEvent(t, events[0], types.EventType_EVENT_TYPE_ADDED, id)
+			assertEvent(t, events[1], types.EventType_EVENT_TYPE_MODIFIED, id)
+			assertEvent(t, events[2], types.EventType_EVENT_TYPE_DELETED, id)
+
+			// recreate the item for the next subtest
+			id = createItem(t, s, "Kettle")
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+
+	res, err := s.HTTP.Get(s.MetricsURL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), "go_goroutines") {
+		t.Errorf("got %s: %.200s", res.Status, body)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	s := testutil.Start(t, testutil.Config{})
+
+	res, err := s.HTTP.Get(s.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "SERVING" {
+		t.Errorf("got %s: %s", res.Status, body)
+	}
+}
+
+// readStream passes the result of every message of a streamed gateway
+// response, newline-delimited JSON or Server-Sent Events, to handle until it
+// returns false, and fails the test on a streamed error.
+func readStream(t *testing.T, res *http.Response, handle func(result []byte) bool) {
+	t.Helper()
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		t.Fatalf("got %s: %s", res.Status, body)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok {
+				continue
+			}
+			line = data
+		}
+
+		if line == "" {
+			continue
+		}
+
+		var message struct {
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			t.Fatalf("invalid stream message %q: %v", line, err)
+		}
+
+		if message.Error != nil {
+			t.Fatalf("stream failed: %s", message.Error)
+		}
+
+		if !handle(message.Result) {
+			return
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createItem(t *testing.T, s *testutil.Server, name string) uint64 {
+	t.Helper()
+
+	created, err := s.Client.CreateItem(context.Background(), &types.CreateItemRequest{Item: &types.Item{Name: name}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return created.ItemId
+}
+
+// changeItem renames the item, then deletes it.
+func changeItem(t *testing.T, s *testutil.Server, id uint64) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	if _, err := s.Client.UpdateItem(ctx, &types.UpdateItemRequest{Item: &types.Item{Id: id, Name: "Renamed"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Client.DeleteItem(ctx, &types.DeleteItemRequest{Id: id}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEvent(t *testing.T, event *types.WatchItemsResponse, eventType types.EventType, id uint64) {
+	t.Helper()
+
+	if event.Type != eventType || event.Item.GetId() != id || event.ResourceVersion == 0 {
+		t.Errorf("got event %v, want %v of item %d", event, eventType, id)
+	}
+}
+
+func assertExported(t *testing.T, exported []*types.Item) {
+	t.Helper()
+
+	if len(exported) != len(importedItems) {
+		t.Fatalf("exported %d items, want %d", len(exported), len(importedItems))
+	}
+
+	for i, item := range exported {
+		want := proto.Clone(importedItems[i]).(*types.Item)
+		want.Id = item.Id
+		want.Namespace = item.Namespace
+		want.ResourceName = item.ResourceName
+
+		if !proto.Equal(item, want) {
+			t.Errorf("exported %v, want %v", item, want)
+		}
+	}
+
+	if exported[0].Namespace != "default" || exported[0].ResourceName != fmt.Sprintf("namespaces/default/items/%d", exported[0].Id) {
+		t.Errorf("exported item %v lacks its namespace", exported[0])
+	}
+}
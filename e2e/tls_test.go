@@ -0,0 +1,236 @@
+package e2e
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/server"
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/client"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"github.com/skip-mev/platform-take-home/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// TestClientCertificates runs the principal path end to end: the namespace
+// of a request is the Organization of its client certificate, over gRPC and
+// through the gateway, and requests without one are rejected.
+func TestClientCertificates(t *testing.T) {
+	ca := newCA(t)
+	dir := t.TempDir()
+
+	serverConfig := server.TLSConfig{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+	}
+	writePEM(t, serverConfig.ClientCAFile, "CERTIFICATE", ca.cert.Raw)
+	ca.issue(t, serverConfig.CertFile, serverConfig.KeyFile, "")
+
+	teamA := ca.clientTLS(t, "team-a")
+	teamB := ca.clientTLS(t, "team-b")
+
+	s := testutil.Start(t, testutil.Config{TLS: serverConfig, ClientTLS: teamA})
+	ctx := context.Background()
+
+	t.Run("grpc", func(t *testing.T) {
+		created, err := s.Client.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.Client.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Item.Namespace != "team-a" {
+			t.Errorf("got namespace %q, want team-a", got.Item.Namespace)
+		}
+
+		other := metadata.AppendToOutgoingContext(ctx, tenancy.Header, "team-b")
+		_, err = s.Client.GetItem(other, &types.GetItemRequest{Id: created.ItemId})
+		assertCode(t, err, codes.PermissionDenied)
+
+		b, err := client.New(client.Config{Address: s.GRPCAddress, TLS: teamB})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer b.Close()
+
+		_, err = b.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		assertCode(t, err, codes.NotFound)
+
+		anonymous, err := client.New(client.Config{Address: s.GRPCAddress, TLS: &tls.Config{RootCAs: teamA.RootCAs}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer anonymous.Close()
+
+		_, err = anonymous.GetItems(ctx, &types.GetItemsRequest{})
+		assertCode(t, err, codes.Unavailable)
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		c := newRESTClient(s)
+
+		created, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Toaster"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Item.Namespace != "team-a" {
+			t.Errorf("got namespace %q, want team-a", got.Item.Namespace)
+		}
+
+		for path, want := range map[string]int{
+			"/healthz":                 http.StatusOK,
+			"/items":                   http.StatusOK,
+			"/namespaces/team-a/items": http.StatusOK,
+			"/namespaces/team-b/items": http.StatusForbidden,
+		} {
+			res, err := s.HTTP.Get(s.URL + path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+
+			if res.StatusCode != want {
+				t.Errorf("GET %s: got status %d, want %d", path, res.StatusCode, want)
+			}
+		}
+
+		b := restClient{http: &http.Client{Transport: &http.Transport{TLSClientConfig: teamB}}, url: s.URL}
+		_, err = b.GetItem(ctx, &types.GetItemRequest{Id: created.ItemId})
+		assertCode(t, err, codes.NotFound)
+
+		anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: teamA.RootCAs}}}
+		if _, err := anonymous.Get(s.URL + "/items"); err == nil {
+			t.Error("got a response without a client certificate, want a TLS error")
+		}
+	})
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue writes a certificate for 127.0.0.1, for both server and client
+// authentication, and its key. organization is the namespace it names, if
+// any.
+func (ca *testCA) issue(t *testing.T, certFile, keyFile, organization string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	if organization != "" {
+		template.Subject.Organization = []string{organization}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+}
+
+// clientTLS is the TLS config of a client acting in namespace, which trusts
+// ca.
+func (ca *testCA) clientTLS(t *testing.T, namespace string) *tls.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	ca.issue(t, certFile, keyFile, namespace)
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	return &tls.Config{Certificates: []tls.Certificate{cert}, RootCAs: roots}
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,175 @@
+package e2e
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/testutil"
+	"github.com/skip-mev/platform-take-home/webhook"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestWebhooks(t *testing.T) {
+	// the receiver listens on loopback
+	t.Setenv("WEBHOOK_ALLOW_LOCAL", "true")
+
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		received := make(chan *types.WebhookEvent, 10)
+		var secret string
+
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			if err := webhook.Verify(secret, r.Header.Get("X-Webhook-Signature"), body, time.Minute); err != nil {
+				t.Errorf("webhook signature: %v", err)
+			}
+
+			event := &types.WebhookEvent{}
+			if err := protojson.Unmarshal(body, event); err != nil {
+				t.Errorf("webhook body: %v", err)
+			}
+
+			received <- event
+		}))
+		defer receiver.Close()
+
+		created, err := c.CreateWebhook(ctx, &types.CreateWebhookRequest{Webhook: &types.Webhook{
+			Url:        receiver.URL,
+			EventTypes: []types.EventType{types.EventType_EVENT_TYPE_ADDED},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if secret = created.Webhook.Secret; secret == "" {
+			t.Fatal("CreateWebhook returned no secret")
+		}
+
+		webhooks, err := c.GetWebhooks(ctx, &types.EmptyRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(webhooks.Webhooks) != 1 || webhooks.Webhooks[0].Url != receiver.URL || webhooks.Webhooks[0].Secret != "" {
+			t.Errorf("GetWebhooks: got %v, want the webhook without its secret", webhooks.Webhooks)
+		}
+
+		item, err := c.CreateItem(ctx, &types.CreateItemRequest{Item: &types.Item{Name: "Kettle"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// not subscribed to
+		if _, err := c.DeleteItem(ctx, &types.DeleteItemRequest{Id: item.ItemId}); err != nil {
+			t.Fatal(err)
+		}
+
+		event := receive(t, received)
+		if event.Type != types.EventType_EVENT_TYPE_ADDED || event.Item.GetId() != item.ItemId {
+			t.Errorf("got event %v, want the item ADDED", event)
+		}
+
+		var deliveries []*types.WebhookDelivery
+		eventually(t, "delivery marked delivered", func() bool {
+			res, err := c.GetWebhookDeliveries(ctx, &types.GetWebhookDeliveriesRequest{WebhookId: created.Webhook.Id, Status: types.DeliveryStatus_DELIVERY_STATUS_DELIVERED})
+			if err != nil {
+				t.Fatal(err)
+			}
+			deliveries = res.Deliveries
+			return len(deliveries) == 1
+		})
+
+		if _, err := c.RedeliverWebhookDelivery(ctx, &types.RedeliverWebhookDeliveryRequest{Id: deliveries[0].Id}); err != nil {
+			t.Fatal(err)
+		}
+
+		if redelivered := receive(t, received); redelivered.Id != event.Id {
+			t.Errorf("redelivered event %d, want %d", redelivered.Id, event.Id)
+		}
+
+		_, err = c.RedeliverWebhookDelivery(ctx, &types.RedeliverWebhookDeliveryRequest{Id: deliveries[0].Id + 1})
+		assertCode(t, err, codes.NotFound)
+
+		if _, err := c.DeleteWebhook(ctx, &types.DeleteWebhookRequest{Id: created.Webhook.Id}); err != nil {
+			t.Fatal(err)
+		}
+
+		webhooks, err = c.GetWebhooks(ctx, &types.EmptyRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(webhooks.Webhooks) != 0 {
+			t.Errorf("GetWebhooks after DeleteWebhook: got %v", webhooks.Webhooks)
+		}
+
+		_, err = c.DeleteWebhook(ctx, &types.DeleteWebhookRequest{Id: created.Webhook.Id})
+		assertCode(t, err, codes.NotFound)
+	})
+}
+
+func TestWebhookURLs(t *testing.T) {
+	forEachTransport(t, func(t *testing.T, c types.TakeHomeServiceClient, _ *testutil.Server) {
+		ctx := context.Background()
+
+		for _, url := range []string{
+			"ftp://example.com/hook",
+			"/hook",
+			"http://127.0.0.1:8080/hook",
+			"http://localhost/hook",
+			"http://[::1]/hook",
+			"http://169.254.169.254/latest/meta-data",
+			"http://0.0.0.0/hook",
+			"http://10.0.0.1/hook",
+			"http://192.168.1.1:8080/hook",
+			"http://[fd00::1]/hook",
+		} {
+			_, err := c.CreateWebhook(ctx, &types.CreateWebhookRequest{Webhook: &types.Webhook{Url: url}})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s: got %v, want INVALID_ARGUMENT", url, err)
+			}
+		}
+
+		if _, err := c.CreateWebhook(ctx, &types.CreateWebhookRequest{Webhook: &types.Webhook{Url: "https://example.com/hook"}}); err != nil {
+			t.Errorf("https://example.com/hook: %v", err)
+		}
+	})
+}
+
+// receive waits for the next webhook event, which the dispatcher sends within
+// a poll interval or two.
+func receive(t *testing.T, events <-chan *types.WebhookEvent) *types.WebhookEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(10 * time.Second):
+		t.Fatal("no webhook delivered")
+		return nil
+	}
+}
+
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(10 * time.Second); !condition(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,291 @@
+// Package handoff restarts the server without refusing connections. On
+// Upgrade, the server starts a new process of its executable and passes it
+// its listening sockets; once the new process is serving, the old one stops
+// accepting and drains its in-flight requests. Listen also takes sockets
+// from systemd socket activation, so that they stay open across restarts.
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	// fdNamesEnv names the listeners Upgrade passes to the new process, from
+	// file descriptor 3 on, separated by colons, like LISTEN_FDNAMES.
+	fdNamesEnv = "HANDOFF_FDNAMES"
+	// readyFDEnv is the file descriptor Ready writes to once the new
+	// process serves.
+	readyFDEnv = "HANDOFF_READY_FD"
+
+	// the first file descriptor passed, after stdin, stdout and stderr
+	firstFD = 3
+)
+
+// Listen returns a listener for each name of addresses: the socket of that
+// name passed by the process that handed off to this one, or by systemd
+// socket activation (see FileDescriptorName= in systemd.socket(5)), and
+// otherwise a new TCP listener on the address.
+func Listen(addresses map[string]string) (map[string]net.Listener, error) {
+	listeners, err := inherit()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, listener := range listeners {
+		if _, ok := addresses[name]; !ok {
+			closeAll(listeners)
+			return nil, fmt.Errorf("passed a listener named %q, want one of %v", name, sortedNames(addresses))
+		}
+
+		if _, ok := listener.Addr().(*net.TCPAddr); !ok {
+			closeAll(listeners)
+			return nil, fmt.Errorf("passed listener %q on %s, want a TCP socket", name, listener.Addr())
+		}
+	}
+
+	for name, address := range addresses {
+		if _, ok := listeners[name]; ok {
+			continue
+		}
+
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("error creating listener: %w", err)
+		}
+
+		listeners[name] = listener
+	}
+
+	return listeners, nil
+}
+
+// inherit takes the listeners passed to this process, by Upgrade or by
+// systemd, and unsets the variables that describe them, so that processes
+// this one starts don't take them too.
+func inherit() (map[string]net.Listener, error) {
+	var names []string
+
+	if fdNames, ok := os.LookupEnv(fdNamesEnv); ok {
+		os.Unsetenv(fdNamesEnv)
+		names = strings.Split(fdNames, ":")
+	} else if pid := os.Getenv("LISTEN_PID"); pid == strconv.Itoa(os.Getpid()) {
+		count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid LISTEN_FDS %q", os.Getenv("LISTEN_FDS"))
+		}
+
+		names = strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+		if len(names) != count {
+			return nil, fmt.Errorf("LISTEN_FDNAMES %q doesn't name the %d LISTEN_FDS", os.Getenv("LISTEN_FDNAMES"), count)
+		}
+
+		for _, name := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+			os.Unsetenv(name)
+		}
+	}
+
+	listeners := map[string]net.Listener{}
+	for i, name := range names {
+		file := os.NewFile(uintptr(firstFD+i), name)
+
+		// FileListener dups the descriptor, marking the copy close-on-exec
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("error taking over listener %q: %w", name, err)
+		}
+
+		if _, ok := listeners[name]; ok {
+			closeAll(listeners)
+			listener.Close()
+			return nil, fmt.Errorf("passed two listeners named %q", name)
+		}
+
+		listeners[name] = listener
+	}
+
+	return listeners, nil
+}
+
+// Ready tells whoever waits for this process to serve that it does: the
+// process that handed off to this one, if any, which then stops, and
+// systemd, if the service is of Type=notify. Since the process that
+// handed off exits, systemd is told that this one is now the main process,
+// which requires NotifyAccess=all.
+func Ready() error {
+	if fd, ok := os.LookupEnv(readyFDEnv); ok {
+		os.Unsetenv(readyFDEnv)
+
+		n, err := strconv.Atoi(fd)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", readyFDEnv, fd)
+		}
+
+		file := os.NewFile(uintptr(n), "handoff-ready")
+		_, err = file.Write([]byte{1})
+		if err := errors.Join(err, file.Close()); err != nil {
+			return fmt.Errorf("error reporting readiness: %w", err)
+		}
+	}
+
+	if socket := os.Getenv("NOTIFY_SOCKET"); socket != "" {
+		conn, err := net.Dial("unixgram", socket)
+		if err != nil {
+			return fmt.Errorf("error notifying systemd: %w", err)
+		}
+		defer conn.Close()
+
+		if _, err := fmt.Fprintf(conn, "READY=1\nMAINPID=%d", os.Getpid()); err != nil {
+			return fmt.Errorf("error notifying systemd: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Upgrade starts a new process of the current executable, with the same
+// arguments and environment, passing it listeners by name, and waits until
+// it calls Ready. If the new process exits first, or ctx is done, in which
+// case Upgrade kills it, the upgrade fails and the listeners stay with this
+// process. The new process shares the listeners with this one until it
+// closes its own, and outlives it.
+//
+// Upgrade fails in a process running as PID 1, e.g. the entrypoint of a
+// container, whose exit would kill the new process along with every other
+// one in its PID namespace.
+func Upgrade(ctx context.Context, listeners map[string]net.Listener) error {
+	if os.Getpid() == 1 {
+		return errors.New("can't hand off as PID 1, whose exit would kill the new process; run under an init such as tini")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	names := sortedNames(listeners)
+
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	for _, name := range names {
+		filer, ok := listeners[name].(interface{ File() (*os.File, error) })
+		if !ok {
+			return fmt.Errorf("listener %q on %s can't be passed on", name, listeners[name].Addr())
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			return fmt.Errorf("error passing on listener %q: %w", name, err)
+		}
+		files = append(files, file)
+	}
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+	files = append(files, readyWriter)
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		fdNamesEnv+"="+strings.Join(names, ":"),
+		fmt.Sprintf("%s=%d", readyFDEnv, firstFD+len(names)),
+	)
+
+	err = cmd.Start()
+
+	// passing the sockets on put them in blocking mode, in this process
+	// too, where Accept, and so Close, would then block until the next
+	// connection
+	if err := setNonblock(listeners); err != nil {
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+		}
+		return err
+	}
+
+	if err != nil {
+		return fmt.Errorf("error starting new process: %w", err)
+	}
+
+	// close this process' end, so that reading fails once the new process
+	// exits
+	readyWriter.Close()
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1))
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			return nil
+		}
+	case <-ctx.Done():
+		cmd.Process.Kill()
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return fmt.Errorf("new process didn't get ready: %w", ctx.Err())
+	}
+
+	return fmt.Errorf("new process exited before it was ready: %v", err)
+}
+
+func setNonblock(listeners map[string]net.Listener) error {
+	for name, listener := range listeners {
+		conn, err := listener.(syscall.Conn).SyscallConn()
+		if err != nil {
+			return fmt.Errorf("error restoring listener %q: %w", name, err)
+		}
+
+		var nonblockErr error
+		if err := conn.Control(func(fd uintptr) { nonblockErr = syscall.SetNonblock(int(fd), true) }); err != nil {
+			return fmt.Errorf("error restoring listener %q: %w", name, err)
+		}
+		if nonblockErr != nil {
+			return fmt.Errorf("error restoring listener %q: %w", name, nonblockErr)
+		}
+	}
+
+	return nil
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func closeAll(listeners map[string]net.Listener) {
+	for _, listener := range listeners {
+		listener.Close()
+	}
+}
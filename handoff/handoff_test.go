@@ -0,0 +1,249 @@
+package handoff
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// helperEnv makes the test binary run helper instead of the tests,
+	// writing what it did to the file named by outputEnv.
+	helperEnv = "HANDOFF_TEST_HELPER"
+	outputEnv = "HANDOFF_TEST_OUTPUT"
+	// failEnv makes helper exit before reporting readiness.
+	failEnv = "HANDOFF_TEST_FAIL"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) != "" {
+		helper()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+// helper is the process listeners are passed to: it takes them with Listen,
+// writes their names and addresses, or the error, and reports readiness.
+func helper() {
+	output := os.Getenv(outputEnv)
+
+	if os.Getenv(failEnv) != "" {
+		os.Exit(1)
+	}
+
+	listeners, err := Listen(map[string]string{"grpc": "127.0.0.1:0", "gateway": "127.0.0.1:0"})
+	if err != nil {
+		os.WriteFile(output, []byte("error: "+err.Error()), 0o600)
+		os.Exit(1)
+	}
+
+	var lines []string
+	for name, listener := range listeners {
+		lines = append(lines, name+"="+listener.Addr().String())
+	}
+	sort.Strings(lines)
+
+	// the variables describing the listeners are gone
+	for _, name := range []string{fdNamesEnv, "LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+		if value, ok := os.LookupEnv(name); ok {
+			lines = append(lines, name+" still set to "+value)
+		}
+	}
+
+	if err := os.WriteFile(output, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		os.Exit(1)
+	}
+
+	if err := Ready(); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func TestListenHandedOff(t *testing.T) {
+	grpc := listen(t)
+	output := filepath.Join(t.TempDir(), "output")
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ready.Close()
+
+	cmd := exec.Command(os.Args[0])
+	cmd.ExtraFiles = []*os.File{file(t, grpc), readyWriter}
+	cmd.Env = append(os.Environ(), helperEnv+"=1", outputEnv+"="+output, fdNamesEnv+"=grpc", readyFDEnv+"=4")
+	run(t, cmd)
+	readyWriter.Close()
+
+	assertOutput(t, output, "gateway=127.0.0.1:", "grpc="+grpc.Addr().String())
+
+	if n, err := ready.Read(make([]byte, 1)); n != 1 {
+		t.Errorf("reading readiness: %v", err)
+	}
+}
+
+func TestListenSystemd(t *testing.T) {
+	grpc := listen(t)
+	output := filepath.Join(t.TempDir(), "output")
+
+	// LISTEN_PID must be the helper's PID, which the shell it execs has
+	cmd := exec.Command("/bin/sh", "-c", `LISTEN_PID=$$ exec "$0"`, os.Args[0])
+	cmd.ExtraFiles = []*os.File{file(t, grpc)}
+	cmd.Env = append(os.Environ(), helperEnv+"=1", outputEnv+"="+output, "LISTEN_FDS=1", "LISTEN_FDNAMES=grpc")
+	run(t, cmd)
+
+	assertOutput(t, output, "gateway=127.0.0.1:", "grpc="+grpc.Addr().String())
+}
+
+func TestListenSystemdOtherProcess(t *testing.T) {
+	grpc := listen(t)
+	output := filepath.Join(t.TempDir(), "output")
+
+	// passed on to another process, the sockets are ignored
+	cmd := exec.Command(os.Args[0])
+	cmd.ExtraFiles = []*os.File{file(t, grpc)}
+	cmd.Env = append(os.Environ(), helperEnv+"=1", outputEnv+"="+output, "LISTEN_PID=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=grpc")
+	run(t, cmd)
+
+	lines := readOutput(t, output)
+	if len(lines) != 5 || lines[1] == "grpc="+grpc.Addr().String() {
+		t.Errorf("got %q, want new listeners and the systemd variables left alone", lines)
+	}
+}
+
+func TestListenRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		env  []string
+		want string
+	}{
+		{name: "unknown name", env: []string{fdNamesEnv + "=admin"}, want: `passed a listener named "admin"`},
+		{name: "duplicate name", env: []string{fdNamesEnv + "=grpc:grpc"}, want: `passed two listeners named "grpc"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "output")
+
+			cmd := exec.Command(os.Args[0])
+			cmd.ExtraFiles = []*os.File{file(t, listen(t)), file(t, listen(t))}
+			cmd.Env = append(append(os.Environ(), helperEnv+"=1", outputEnv+"="+output), tc.env...)
+
+			if err := cmd.Run(); err == nil {
+				t.Error("helper succeeded, want it to fail")
+			}
+
+			if lines := readOutput(t, output); len(lines) != 1 || !strings.Contains(lines[0], tc.want) {
+				t.Errorf("got %q, want an error containing %q", lines, tc.want)
+			}
+		})
+	}
+}
+
+// TestUpgrade hands listeners off to a new process of the test binary,
+// which runs helper.
+func TestUpgrade(t *testing.T) {
+	grpc := listen(t)
+	output := filepath.Join(t.TempDir(), "output")
+
+	t.Setenv(helperEnv, "1")
+	t.Setenv(outputEnv, output)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := Upgrade(ctx, map[string]net.Listener{"grpc": grpc}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, output, "gateway=127.0.0.1:", "grpc="+grpc.Addr().String())
+
+	// the listener still accepts here, where Upgrade restored non-blocking mode
+	grpc.(*net.TCPListener).SetDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := grpc.Accept(); !os.IsTimeout(err) {
+		t.Errorf("accepting after the upgrade: got %v, want a timeout", err)
+	}
+}
+
+func TestUpgradeFails(t *testing.T) {
+	t.Setenv(helperEnv, "1")
+	t.Setenv(outputEnv, filepath.Join(t.TempDir(), "output"))
+	t.Setenv(failEnv, "1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := Upgrade(ctx, map[string]net.Listener{"grpc": listen(t)})
+	if err == nil || !strings.Contains(err.Error(), "exited before it was ready") {
+		t.Errorf("got %v, want the new process to exit before it was ready", err)
+	}
+}
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	return listener
+}
+
+// file dups the socket of listener, to pass it to a process.
+func file(t *testing.T, listener net.Listener) *os.File {
+	t.Helper()
+
+	f, err := listener.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func run(t *testing.T, cmd *exec.Cmd) {
+	t.Helper()
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("helper: %v: %s", err, out)
+	}
+}
+
+func readOutput(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(string(data), "\n")
+}
+
+// assertOutput checks that the helper wrote a line starting with each of
+// prefixes, in order, and nothing else.
+func assertOutput(t *testing.T, path string, prefixes ...string) {
+	t.Helper()
+
+	lines := readOutput(t, path)
+	if len(lines) != len(prefixes) {
+		t.Fatalf("got %q, want lines starting with %q", lines, prefixes)
+	}
+
+	for i, prefix := range prefixes {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d: got %q, want it to start with %q", i+1, lines[i], prefix)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package itemio reads and writes streams of items in the formats supported by
+// the bulk import and export commands: JSON lines, CSV and length-delimited
+// protobuf.
+package itemio
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+)
+
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+	FormatProto Format = "proto"
+)
+
+// ParseFormat validates a user supplied format name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatJSONL, FormatCSV, FormatProto:
+		return f, nil
+	case "json", "ndjson":
+		return FormatJSONL, nil
+	case "pb", "protobuf":
+		return FormatProto, nil
+	}
+
+	return "", fmt.Errorf("unknown format %q, expected one of jsonl, csv, proto", name)
+}
+
+// FormatFromPath guesses the format from a file extension, defaulting to JSON lines.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".pb", ".bin", ".proto":
+		return FormatProto
+	}
+
+	return FormatJSONL
+}
+
+// Reader yields items one at a time and returns io.EOF once the input is exhausted.
+type Reader interface {
+	Read() (*types.Item, error)
+}
+
+// Writer encodes items to an underlying stream. Flush must be called once all
+// items have been written.
+type Writer interface {
+	Write(item *types.Item) error
+	Flush() error
+}
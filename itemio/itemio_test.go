@@ -0,0 +1,179 @@
+package itemio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	attributes, err := structpb.NewStruct(map[string]any{
+		"color": "red",
+		"size":  map[string]any{"width": 1.5, "tags": []any{"a", "b"}},
+		"new":   true,
+		"none":  nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*types.Item{
+		{Id: 1, Name: "Kettle", Description: "Boils water", Labels: map[string]string{"env": "prod", "team": "a"}, Kind: "gadget", Attributes: attributes},
+		{Name: "Teapot"},
+		{Id: 3, Name: `Quotes "and", commas`, Description: "line one\nline two", Labels: map[string]string{"example.com/owner": ""}},
+		{Id: 1 << 62, Name: "Ünïcødé ☕"},
+	}
+
+	for _, format := range []Format{FormatJSONL, FormatCSV, FormatProto} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			writer, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, item := range items {
+				if err := writer.Write(item); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, want := range items {
+				got, err := reader.Read()
+				if err != nil {
+					t.Fatalf("item %d: %v", i, err)
+				}
+
+				if !proto.Equal(got, want) {
+					t.Errorf("item %d: got %v, want %v", i, got, want)
+				}
+			}
+
+			if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+				t.Errorf("got %v after the last item, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	for _, format := range []Format{FormatJSONL, FormatCSV, FormatProto} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			writer, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			reader, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := reader.Read(); !errors.Is(err, io.EOF) {
+				t.Errorf("got %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+		input  string
+		want   string
+	}{
+		{name: "jsonl syntax", format: FormatJSONL, input: "{\"name\":\"a\"}\n\n{\"name\":", want: "line 3"},
+		{name: "jsonl unknown field", format: FormatJSONL, input: `{"nom":"a"}`, want: "line 1"},
+		{name: "csv id", format: FormatCSV, input: "id,name\nx,Kettle\n", want: `line 2: invalid id "x"`},
+		{name: "csv labels", format: FormatCSV, input: "name,labels\nKettle,env\n", want: `invalid label "env"`},
+		{name: "csv attributes", format: FormatCSV, input: "name,attributes\nKettle,[1]\n", want: "line 2: invalid attributes"},
+		{name: "proto truncated", format: FormatProto, input: "\x10\x0aKet", want: "unexpected EOF"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader(tc.input), tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for {
+				_, err = reader.Read()
+				if err != nil {
+					break
+				}
+			}
+
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewReaderCSVHeader(t *testing.T) {
+	if _, err := NewReader(strings.NewReader("id,description\n1,x\n"), FormatCSV); err == nil {
+		t.Error("got no error for a header without a name column")
+	}
+
+	// columns may come in any order and case, and unknown ones are ignored
+	reader, err := NewReader(strings.NewReader(" Name ,extra,ID\nKettle,x,7\n"), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (&types.Item{Id: 7, Name: "Kettle"}); !proto.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{
+		"jsonl": FormatJSONL, "JSON": FormatJSONL, "ndjson": FormatJSONL,
+		"csv":   FormatCSV,
+		"proto": FormatProto, "pb": FormatProto, "protobuf": FormatProto,
+	} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml): got no error")
+	}
+
+	for path, want := range map[string]Format{
+		"items.csv": FormatCSV, "items.CSV": FormatCSV, "items.pb": FormatProto, "items.bin": FormatProto,
+		"items.jsonl": FormatJSONL, "items": FormatJSONL, "-": FormatJSONL,
+	} {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package itemio
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Progress periodically logs how many rows an import or export has processed.
+type Progress struct {
+	logger   *zap.Logger
+	interval time.Duration
+	start    time.Time
+	last     time.Time
+	rows     uint64
+}
+
+func NewProgress(logger *zap.Logger, interval time.Duration) *Progress {
+	now := time.Now()
+	return &Progress{logger: logger, interval: interval, start: now, last: now}
+}
+
+// Add records n more processed rows and logs if the reporting interval has elapsed.
+func (p *Progress) Add(n int) {
+	p.rows += uint64(n)
+
+	if time.Since(p.last) >= p.interval {
+		p.last = time.Now()
+		p.log("progress")
+	}
+}
+
+// Done logs the final row count and throughput.
+func (p *Progress) Done() {
+	p.log("done")
+}
+
+func (p *Progress) log(msg string) {
+	elapsed := time.Since(p.start)
+	p.logger.Info(msg,
+		zap.Uint64("rows", p.rows),
+		zap.Duration("elapsed", elapsed),
+		zap.Float64("rows_per_second", float64(p.rows)/elapsed.Seconds()),
+	)
+}
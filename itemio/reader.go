@@ -0,0 +1,172 @@
+package itemio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// maxLineSize bounds a single JSON line so a corrupt file can't exhaust memory.
+const maxLineSize = 4 << 20
+
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonlReader{scanner: scanner}, nil
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatProto:
+		return &protoReader{r: bufio.NewReader(r)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *jsonlReader) Read() (*types.Item, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var item types.Item
+		if err := protojson.Unmarshal([]byte(line), &item); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		return &item, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return &csvReader{r: cr}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("csv header is missing a name column")
+	}
+
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (r *csvReader) Read() (*types.Item, error) {
+	if r.columns == nil {
+		return nil, io.EOF
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	item := &types.Item{
+		Name:        field("name"),
+		Description: field("description"),
+		Kind:        field("kind"),
+	}
+
+	if id := field("id"); id != "" {
+		item.Id, err = strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			line, _ := r.r.FieldPos(r.columns["id"])
+			return nil, fmt.Errorf("line %d: invalid id %q", line, id)
+		}
+	}
+
+	if labels := field("labels"); labels != "" {
+		item.Labels, err = parseCSVLabels(labels)
+		if err != nil {
+			line, _ := r.r.FieldPos(r.columns["labels"])
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	if attributes := field("attributes"); attributes != "" {
+		item.Attributes = &structpb.Struct{}
+		if err := protojson.Unmarshal([]byte(attributes), item.Attributes); err != nil {
+			line, _ := r.r.FieldPos(r.columns["attributes"])
+			return nil, fmt.Errorf("line %d: invalid attributes: %w", line, err)
+		}
+	}
+
+	return item, nil
+}
+
+// parseCSVLabels parses the "k1=v1,k2=v2" form written by the CSV writer.
+func parseCSVLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", pair)
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+type protoReader struct {
+	r *bufio.Reader
+}
+
+func (r *protoReader) Read() (*types.Item, error) {
+	var item types.Item
+
+	if err := protodelim.UnmarshalFrom(r.r, &item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,107 @@
+package itemio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"github.com/skip-mev/platform-take-home/store"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var csvHeader = []string{"id", "name", "description", "labels", "kind", "attributes"}
+
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatJSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w)}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatProto:
+		return &protoWriter{w: bufio.NewWriter(w)}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+type jsonlWriter struct {
+	w *bufio.Writer
+}
+
+func (w *jsonlWriter) Write(item *types.Item) error {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+
+	return w.w.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.w.Flush()
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(item *types.Item) error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	var attributes string
+	if item.Attributes != nil {
+		b, err := protojson.Marshal(item.Attributes)
+		if err != nil {
+			return err
+		}
+		attributes = string(b)
+	}
+
+	return w.w.Write([]string{
+		strconv.FormatUint(item.Id, 10),
+		item.Name,
+		item.Description,
+		store.Labels(item.Labels).String(),
+		item.Kind,
+		attributes,
+	})
+}
+
+func (w *csvWriter) Flush() error {
+	if !w.headerWritten {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type protoWriter struct {
+	w *bufio.Writer
+}
+
+func (w *protoWriter) Write(item *types.Item) error {
+	_, err := protodelim.MarshalTo(w.w, item)
+	return err
+}
+
+func (w *protoWriter) Flush() error {
+	return w.w.Flush()
+}
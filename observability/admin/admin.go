@@ -0,0 +1,248 @@
+// Package admin serves endpoints for operators to look inside the running
+// server, on a listener of its own that isn't exposed like the gateway:
+// profiles, the log level, the build and the effective config.
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+)
+
+const (
+	defaultAddress = "127.0.0.1:8082"
+
+	// shutdownTimeout bounds how long a stopping admin server waits for
+	// requests in flight, which cuts profiles short.
+	shutdownTimeout = 5 * time.Second
+
+	redacted = "REDACTED"
+)
+
+// Config configures the admin server.
+type Config struct {
+	// Address is where the admin server listens. By default it only
+	// accepts local connections, since profiles and the config reveal more
+	// than the API does.
+	Address string
+}
+
+// ConfigFromEnv reads ADMIN_ADDRESS.
+func ConfigFromEnv() (Config, error) {
+	config := Config{Address: defaultAddress}
+
+	if address := os.Getenv("ADMIN_ADDRESS"); address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			return config, fmt.Errorf("invalid ADMIN_ADDRESS %q", address)
+		}
+		config.Address = address
+	}
+
+	return config, nil
+}
+
+// Serve serves the admin endpoints on listener until ctx is done:
+//
+//   - /debug/pprof/: the runtime profiles of net/http/pprof
+//   - /loglevel: the log level, see logging.Level
+//   - /buildinfo: the version, commit and Go version of the binary
+//   - /config: effective, the config the server runs with, as JSON, with
+//     secrets such as DSN passwords redacted
+func Serve(ctx context.Context, listener net.Listener, effective any) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/loglevel", logging.Level())
+	mux.Handle("GET /buildinfo", jsonHandler(readBuildInfo()))
+	mux.Handle("GET /config", jsonHandler(redact("", reflect.ValueOf(effective))))
+
+	server := http.Server{Handler: mux}
+
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(ctx)
+		if err != nil {
+			err = errors.Join(err, server.Close())
+		}
+		shutdown <- err
+	}()
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving admin endpoints: %v", err)
+	}
+
+	if err := <-shutdown; err != nil {
+		return fmt.Errorf("error shutting down admin server: %w", err)
+	}
+
+	return nil
+}
+
+func jsonHandler(value any) http.Handler {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(value)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body.Bytes())
+	})
+}
+
+type buildInfo struct {
+	Path       string `json:"path"`
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	GoVersion  string `json:"go_version"`
+}
+
+// readBuildInfo reads the build info Go embeds in the binary. The commit is
+// only known when it was built in a git checkout.
+func readBuildInfo() buildInfo {
+	info := buildInfo{GoVersion: runtime.Version()}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info.Path = build.Main.Path
+	info.Version = build.Main.Version
+
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+
+	return info
+}
+
+var (
+	// secretPattern matches the names of fields and keys whose values are
+	// redacted.
+	secretPattern = regexp.MustCompile(`(?i)dsn|password|secret|token|key`)
+	// keywordPassword matches the password in a key=value DSN.
+	keywordPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+)
+
+// redact converts value, found at name, into a form that marshals to
+// readable JSON: durations become strings like "30s", and struct fields keep
+// their Go names. Secrets are redacted, and DSNs lose their passwords.
+func redact(name string, value reflect.Value) any {
+	if !value.IsValid() {
+		return nil
+	}
+
+	if duration, ok := value.Interface().(time.Duration); ok {
+		return duration.String()
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return redact(name, value.Elem())
+
+	case reflect.Struct:
+		fields := map[string]any{}
+		for i := 0; i < value.NumField(); i++ {
+			if field := value.Type().Field(i); field.IsExported() {
+				fields[field.Name] = redact(field.Name, value.Field(i))
+			}
+		}
+		return fields
+
+	case reflect.Map:
+		entries := map[string]any{}
+		for _, key := range value.MapKeys() {
+			entries[fmt.Sprint(key.Interface())] = redact(fmt.Sprint(key.Interface()), value.MapIndex(key))
+		}
+		return entries
+
+	case reflect.Slice, reflect.Array:
+		elements := make([]any, value.Len())
+		for i := range elements {
+			elements[i] = redact(name, value.Index(i))
+		}
+		return elements
+
+	case reflect.String:
+		if !secretPattern.MatchString(name) || value.String() == "" {
+			return value.String()
+		}
+		if strings.Contains(strings.ToLower(name), "dsn") {
+			return redactDSN(value.String())
+		}
+		return redacted
+
+	default:
+		if secretPattern.MatchString(name) {
+			return redacted
+		}
+		return value.Interface()
+	}
+}
+
+// redactDSN removes the passwords, and other secrets in the query, from a
+// Postgres DSN, either a URL or key=value pairs.
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		query := u.Query()
+		for key := range query {
+			if secretPattern.MatchString(key) {
+				query.Set(key, redacted)
+				u.RawQuery = query.Encode()
+			}
+		}
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		return u.String()
+	}
+
+	if strings.Contains(dsn, "://") {
+		// a URL too malformed to find the password in
+		return redacted
+	}
+
+	return keywordPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
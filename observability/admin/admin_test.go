@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRedactDSN(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		dsn  string
+		want string
+	}{
+		{name: "url", dsn: "postgres://app:hunter2@db:5432/items?sslmode=require", want: "postgres://app:REDACTED@db:5432/items?sslmode=require"},
+		{name: "url without password", dsn: "postgres://app@db/items", want: "postgres://app@db/items"},
+		{name: "url without user", dsn: "postgresql://db/items", want: "postgresql://db/items"},
+		{name: "password in query", dsn: "postgres://db/items?user=app&password=hunter2", want: "postgres://db/items?password=REDACTED&user=app"},
+		{name: "other secrets in query", dsn: "postgres://db/items?sslpassword=pw&sslkey=/etc/key.pem&sslmode=verify-full", want: "postgres://db/items?sslkey=REDACTED&sslmode=verify-full&sslpassword=REDACTED"},
+		{name: "malformed url", dsn: "postgres://app:hunter2@db:port/items", want: "REDACTED"},
+		{name: "key=value", dsn: "host=db user=app password=hunter2 dbname=items", want: "host=db user=app password=REDACTED dbname=items"},
+		{name: "key=value, spaced", dsn: "host=db password = hunter2 dbname=items", want: "host=db password = REDACTED dbname=items"},
+		{name: "key=value, quoted", dsn: `host=db password='hunter 2\'s' dbname=items`, want: "host=db password=REDACTED dbname=items"},
+		{name: "key=value, case", dsn: "host=db PASSWORD=hunter2", want: "host=db PASSWORD=REDACTED"},
+		{name: "key=value, sslpassword", dsn: "host=db sslpassword=pw", want: "host=db sslpassword=REDACTED"},
+		{name: "key=value without password", dsn: "host=db user=app dbname=items", want: "host=db user=app dbname=items"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := redactDSN(tc.dsn); got != tc.want {
+				t.Errorf("redactDSN(%q) = %q, want %q", tc.dsn, got, tc.want)
+			}
+		})
+	}
+}
+
+type testConfig struct {
+	Address     string
+	Timeout     time.Duration
+	Token       string
+	APIKey      []byte
+	PostgresDSN string
+	Replicas    []string
+	ReplicaDSNs []string
+	Nested      *testNested
+	Empty       *testNested
+	Headers     map[string]string
+	unexported  string
+}
+
+type testNested struct {
+	Secret string
+	Port   int
+}
+
+func TestRedact(t *testing.T) {
+	config := map[string]any{
+		"Server": testConfig{
+			Address:     "0.0.0.0:9008",
+			Timeout:     30 * time.Second,
+			Token:       "t0ken",
+			APIKey:      []byte("k"),
+			PostgresDSN: "postgres://app:hunter2@db/items",
+			Replicas:    []string{"a", "b"},
+			ReplicaDSNs: []string{"host=r1 password=p1", "host=r2"},
+			Nested:      &testNested{Secret: "s", Port: 5432},
+			Headers:     map[string]string{"Authorization-Token": "bearer x", "Accept": "json"},
+			unexported:  "hidden",
+		},
+		"EmptySecret": "",
+		"Level":       "info",
+	}
+
+	got, err := json.Marshal(redact("", reflect.ValueOf(config)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"EmptySecret": "",
+		"Level":       "info",
+		"Server": map[string]any{
+			"Address":     "0.0.0.0:9008",
+			"Timeout":     "30s",
+			"Token":       "REDACTED",
+			"APIKey":      []any{"REDACTED"},
+			"PostgresDSN": "postgres://app:REDACTED@db/items",
+			"Replicas":    []any{"a", "b"},
+			"ReplicaDSNs": []any{"host=r1 password=REDACTED", "host=r2"},
+			"Nested":      map[string]any{"Secret": "REDACTED", "Port": float64(5432)},
+			"Empty":       nil,
+			"Headers":     map[string]any{"Authorization-Token": "REDACTED", "Accept": "json"},
+		},
+	}
+
+	var gotValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(gotValue, want) {
+		wantJSON, _ := json.Marshal(want)
+		t.Errorf("got  %s\nwant %s", got, wantJSON)
+	}
+}
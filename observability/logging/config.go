@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultSamplingInitial    = 100
+	defaultSamplingThereafter = 100
+	defaultFileMaxSize        = 100
+	defaultFileMaxBackups     = 5
+)
+
+// defaultRedactFields are the field names redacted unless LOG_REDACT_FIELDS
+// is set.
+var defaultRedactFields = []string{"password", "secret", "token", "authorization", "dsn"}
+
+// Config configures the loggers DefaultLogger builds.
+type Config struct {
+	// Level is the level loggers start at, see Level.
+	Level zapcore.Level
+	// Levels overrides Level for the packages logging through FromContext,
+	// by import path, or by its last elements, e.g. "store" or
+	// "api/service".
+	Levels map[string]zapcore.Level
+	// Encoding is "json", "console" or "logfmt".
+	Encoding string
+	// Development makes DPanic panic and adds stack traces to warnings.
+	Development bool
+
+	// Within each second, SamplingInitial entries with the same level and
+	// message are logged, then every SamplingThereafter-th. Sampling is
+	// disabled if SamplingInitial is 0.
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// File is where logs are written, instead of stderr. It is rotated once
+	// it reaches FileMaxSize megabytes, keeping FileMaxBackups rotated files
+	// for up to FileMaxAgeDays days. Zero keeps them all.
+	File           string
+	FileMaxSize    int
+	FileMaxBackups int
+	FileMaxAgeDays int
+
+	// RedactFields are the names of fields logged as [REDACTED]: any field
+	// whose name contains one of them, ignoring case, at any depth.
+	RedactFields []string
+
+	// OTLP also exports logs over OTLP, configured by the
+	// OTEL_EXPORTER_OTLP_* variables, with the trace and span of the
+	// context they were logged with.
+	OTLP bool
+}
+
+// ConfigFromEnv reads:
+//
+//   - DEV_LOGGING: "true" for debug logs in the console encoding, without
+//     sampling, as the defaults of the variables below
+//   - LOG_LEVEL: debug, info (default), warn or error
+//   - LOG_LEVELS: levels by package, e.g. "store=debug,webhook=warn"
+//   - LOG_ENCODING: json (default), console or logfmt
+//   - LOG_SAMPLING: "<initial>,<thereafter>" (default "100,100"), or "off"
+//   - LOG_FILE, LOG_FILE_MAX_SIZE_MB (default 100), LOG_FILE_MAX_BACKUPS
+//     (default 5) and LOG_FILE_MAX_AGE_DAYS (default 0, no limit)
+//   - LOG_REDACT_FIELDS: comma separated, default
+//     "password,secret,token,authorization,dsn"
+//   - LOG_OTLP: "true" to export logs over OTLP
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Level:              zapcore.InfoLevel,
+		Encoding:           "json",
+		SamplingInitial:    defaultSamplingInitial,
+		SamplingThereafter: defaultSamplingThereafter,
+		FileMaxSize:        defaultFileMaxSize,
+		FileMaxBackups:     defaultFileMaxBackups,
+		RedactFields:       defaultRedactFields,
+	}
+
+	if os.Getenv("DEV_LOGGING") == "true" {
+		config.Level = zapcore.DebugLevel
+		config.Encoding = "console"
+		config.Development = true
+		config.SamplingInitial, config.SamplingThereafter = 0, 0
+	}
+
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		level, err := zapcore.ParseLevel(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid LOG_LEVEL %q", value)
+		}
+		config.Level = level
+	}
+
+	if value := os.Getenv("LOG_LEVELS"); value != "" {
+		config.Levels = map[string]zapcore.Level{}
+		for _, entry := range strings.Split(value, ",") {
+			pkg, levelName, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			level, err := zapcore.ParseLevel(levelName)
+			if !ok || pkg == "" || err != nil {
+				return config, fmt.Errorf("invalid LOG_LEVELS %q: want package=level,...", value)
+			}
+			config.Levels[strings.Trim(pkg, "/")] = level
+		}
+	}
+
+	if value := os.Getenv("LOG_ENCODING"); value != "" {
+		switch value {
+		case "json", "console", "logfmt":
+			config.Encoding = value
+		default:
+			return config, fmt.Errorf("invalid LOG_ENCODING %q: must be json, console or logfmt", value)
+		}
+	}
+
+	if value := os.Getenv("LOG_SAMPLING"); value == "off" {
+		config.SamplingInitial, config.SamplingThereafter = 0, 0
+	} else if value != "" {
+		initialValue, thereafterValue, _ := strings.Cut(value, ",")
+		initial, err := strconv.Atoi(strings.TrimSpace(initialValue))
+		if err != nil || initial <= 0 {
+			return config, fmt.Errorf("invalid LOG_SAMPLING %q", value)
+		}
+		thereafter, err := strconv.Atoi(strings.TrimSpace(thereafterValue))
+		if err != nil || thereafter <= 0 {
+			return config, fmt.Errorf("invalid LOG_SAMPLING %q", value)
+		}
+		config.SamplingInitial, config.SamplingThereafter = initial, thereafter
+	}
+
+	config.File = os.Getenv("LOG_FILE")
+
+	for name, value := range map[string]*int{
+		"LOG_FILE_MAX_SIZE_MB":  &config.FileMaxSize,
+		"LOG_FILE_MAX_BACKUPS":  &config.FileMaxBackups,
+		"LOG_FILE_MAX_AGE_DAYS": &config.FileMaxAgeDays,
+	} {
+		if env := os.Getenv(name); env != "" {
+			n, err := strconv.Atoi(env)
+			if err != nil || n < 0 {
+				return config, fmt.Errorf("invalid %s %q", name, env)
+			}
+			*value = n
+		}
+	}
+
+	if value, ok := os.LookupEnv("LOG_REDACT_FIELDS"); ok {
+		config.RedactFields = nil
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				config.RedactFields = append(config.RedactFields, field)
+			}
+		}
+	}
+
+	if value := os.Getenv("LOG_OTLP"); value != "" {
+		otlp, err := strconv.ParseBool(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid LOG_OTLP %q", value)
+		}
+		config.OTLP = otlp
+	}
+
+	return config, nil
+}
@@ -0,0 +1,385 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelCore filters what its core logs by enabler. The core it wraps
+// logs at the lowest level of any package, so that FromContext can swap the
+// enabler for the calling package's.
+type levelCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level)
+}
+
+func (c *levelCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.enabler)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// withPackageLevel makes logger log at the level of pkg, if it has one.
+func withPackageLevel(logger *zap.Logger, pkg string) *zap.Logger {
+	level, ok := packageLevel(pkg)
+	if !ok {
+		return logger
+	}
+
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if levelCore, ok := core.(*levelCore); ok {
+			core = levelCore.Core
+		}
+		return &levelCore{Core: core, enabler: level}
+	}))
+}
+
+// packageLevel finds the level configured for pkg, an import path: the one
+// for the most elements at its end.
+func packageLevel(pkg string) (zapcore.Level, bool) {
+	for name := pkg; name != ""; {
+		if level, ok := packageLevels[name]; ok {
+			return level, true
+		}
+
+		_, rest, ok := strings.Cut(name, "/")
+		if !ok {
+			break
+		}
+		name = rest
+	}
+
+	return 0, false
+}
+
+// callerPackage is the import path of the package of the function skip
+// frames above the caller of callerPackage.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+
+	// e.g. github.com/skip-mev/platform-take-home/store.(*Store).GetItem
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// redactCore replaces the values of fields whose names contain one of
+// names with [REDACTED], before its core encodes them. That includes the
+// fields of objects, arrays and reflected values, at any depth, which it
+// redacts as they are encoded.
+type redactCore struct {
+	zapcore.Core
+	names redactNames
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.names.redactFields(fields)), names: c.names}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.names.redactFields(fields))
+}
+
+// redactNames are the names of the fields to redact, matched
+// case-insensitively anywhere in a field's name.
+type redactNames []string
+
+func (names redactNames) matches(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range names {
+		if strings.Contains(key, strings.ToLower(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (names redactNames) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, field := range fields {
+		field, ok := names.redactField(field)
+		if !ok {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = append([]zapcore.Field(nil), fields...)
+		}
+		redacted[i] = field
+	}
+
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// redactField redacts field, if its name matches, or wraps its value to
+// redact the fields within as it's encoded. It reports whether it changed
+// field.
+func (names redactNames) redactField(field zapcore.Field) (zapcore.Field, bool) {
+	if field.Type == zapcore.SkipType {
+		return field, false
+	}
+
+	if names.matches(field.Key) {
+		return zap.String(field.Key, redactedValue), true
+	}
+
+	switch field.Type {
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType:
+		field.Interface = redactObject{ObjectMarshaler: field.Interface.(zapcore.ObjectMarshaler), names: names}
+		return field, true
+	case zapcore.ArrayMarshalerType:
+		field.Interface = redactArray{ArrayMarshaler: field.Interface.(zapcore.ArrayMarshaler), names: names}
+		return field, true
+	case zapcore.ReflectType:
+		if value, ok := names.redactReflected(field.Interface); ok {
+			field.Interface = value
+			return field, true
+		}
+	}
+
+	return field, false
+}
+
+// redactReflected redacts the keys of the JSON objects value encodes to, at
+// any depth, which is how encoders encode reflected values. It reports
+// whether any key matched.
+func (names redactNames) redactReflected(value any) (any, bool) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return value, false
+	}
+
+	var redact func(v any) bool
+	redact = func(v any) bool {
+		redacted := false
+		switch v := v.(type) {
+		case map[string]any:
+			for key, nested := range v {
+				if names.matches(key) {
+					v[key] = redactedValue
+					redacted = true
+				} else if redact(nested) {
+					redacted = true
+				}
+			}
+		case []any:
+			for _, nested := range v {
+				if redact(nested) {
+					redacted = true
+				}
+			}
+		}
+		return redacted
+	}
+
+	if !redact(decoded) {
+		return value, false
+	}
+	return decoded, true
+}
+
+type redactObject struct {
+	zapcore.ObjectMarshaler
+	names redactNames
+}
+
+func (o redactObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.ObjectMarshaler.MarshalLogObject(&redactEncoder{ObjectEncoder: enc, names: o.names})
+}
+
+type redactArray struct {
+	zapcore.ArrayMarshaler
+	names redactNames
+}
+
+func (a redactArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.ArrayMarshaler.MarshalLogArray(&redactArrayEncoder{ArrayEncoder: enc, names: a.names})
+}
+
+// redactEncoder encodes the fields of an object, redacting those whose
+// names match.
+type redactEncoder struct {
+	zapcore.ObjectEncoder
+	names redactNames
+}
+
+// add encodes the field key with add, or redacts it.
+func (enc *redactEncoder) add(key string, add func()) {
+	if enc.names.matches(key) {
+		enc.ObjectEncoder.AddString(key, redactedValue)
+		return
+	}
+	add()
+}
+
+func (enc *redactEncoder) AddArray(key string, value zapcore.ArrayMarshaler) error {
+	if enc.names.matches(key) {
+		enc.ObjectEncoder.AddString(key, redactedValue)
+		return nil
+	}
+	return enc.ObjectEncoder.AddArray(key, redactArray{ArrayMarshaler: value, names: enc.names})
+}
+
+func (enc *redactEncoder) AddObject(key string, value zapcore.ObjectMarshaler) error {
+	if enc.names.matches(key) {
+		enc.ObjectEncoder.AddString(key, redactedValue)
+		return nil
+	}
+	return enc.ObjectEncoder.AddObject(key, redactObject{ObjectMarshaler: value, names: enc.names})
+}
+
+func (enc *redactEncoder) AddReflected(key string, value any) error {
+	if enc.names.matches(key) {
+		enc.ObjectEncoder.AddString(key, redactedValue)
+		return nil
+	}
+	value, _ = enc.names.redactReflected(value)
+	return enc.ObjectEncoder.AddReflected(key, value)
+}
+
+func (enc *redactEncoder) AddBinary(key string, value []byte) {
+	enc.add(key, func() { enc.ObjectEncoder.AddBinary(key, value) })
+}
+
+func (enc *redactEncoder) AddByteString(key string, value []byte) {
+	enc.add(key, func() { enc.ObjectEncoder.AddByteString(key, value) })
+}
+
+func (enc *redactEncoder) AddString(key, value string) {
+	enc.add(key, func() { enc.ObjectEncoder.AddString(key, value) })
+}
+
+func (enc *redactEncoder) AddBool(key string, value bool) {
+	enc.add(key, func() { enc.ObjectEncoder.AddBool(key, value) })
+}
+
+func (enc *redactEncoder) AddDuration(key string, value time.Duration) {
+	enc.add(key, func() { enc.ObjectEncoder.AddDuration(key, value) })
+}
+
+func (enc *redactEncoder) AddTime(key string, value time.Time) {
+	enc.add(key, func() { enc.ObjectEncoder.AddTime(key, value) })
+}
+
+func (enc *redactEncoder) AddComplex128(key string, value complex128) {
+	enc.add(key, func() { enc.ObjectEncoder.AddComplex128(key, value) })
+}
+
+func (enc *redactEncoder) AddComplex64(key string, value complex64) {
+	enc.add(key, func() { enc.ObjectEncoder.AddComplex64(key, value) })
+}
+
+func (enc *redactEncoder) AddFloat64(key string, value float64) {
+	enc.add(key, func() { enc.ObjectEncoder.AddFloat64(key, value) })
+}
+
+func (enc *redactEncoder) AddFloat32(key string, value float32) {
+	enc.add(key, func() { enc.ObjectEncoder.AddFloat32(key, value) })
+}
+
+func (enc *redactEncoder) AddInt(key string, value int) {
+	enc.add(key, func() { enc.ObjectEncoder.AddInt(key, value) })
+}
+
+func (enc *redactEncoder) AddInt64(key string, value int64) {
+	enc.add(key, func() { enc.ObjectEncoder.AddInt64(key, value) })
+}
+
+func (enc *redactEncoder) AddInt32(key string, value int32) {
+	enc.add(key, func() { enc.ObjectEncoder.AddInt32(key, value) })
+}
+
+func (enc *redactEncoder) AddInt16(key string, value int16) {
+	enc.add(key, func() { enc.ObjectEncoder.AddInt16(key, value) })
+}
+
+func (enc *redactEncoder) AddInt8(key string, value int8) {
+	enc.add(key, func() { enc.ObjectEncoder.AddInt8(key, value) })
+}
+
+func (enc *redactEncoder) AddUint(key string, value uint) {
+	enc.add(key, func() { enc.ObjectEncoder.AddUint(key, value) })
+}
+
+func (enc *redactEncoder) AddUint64(key string, value uint64) {
+	enc.add(key, func() { enc.ObjectEncoder.AddUint64(key, value) })
+}
+
+func (enc *redactEncoder) AddUint32(key string, value uint32) {
+	enc.add(key, func() { enc.ObjectEncoder.AddUint32(key, value) })
+}
+
+func (enc *redactEncoder) AddUint16(key string, value uint16) {
+	enc.add(key, func() { enc.ObjectEncoder.AddUint16(key, value) })
+}
+
+func (enc *redactEncoder) AddUint8(key string, value uint8) {
+	enc.add(key, func() { enc.ObjectEncoder.AddUint8(key, value) })
+}
+
+func (enc *redactEncoder) AddUintptr(key string, value uintptr) {
+	enc.add(key, func() { enc.ObjectEncoder.AddUintptr(key, value) })
+}
+
+// redactArrayEncoder encodes the elements of an array, redacting the
+// fields of those that are objects.
+type redactArrayEncoder struct {
+	zapcore.ArrayEncoder
+	names redactNames
+}
+
+func (enc *redactArrayEncoder) AppendArray(value zapcore.ArrayMarshaler) error {
+	return enc.ArrayEncoder.AppendArray(redactArray{ArrayMarshaler: value, names: enc.names})
+}
+
+func (enc *redactArrayEncoder) AppendObject(value zapcore.ObjectMarshaler) error {
+	return enc.ArrayEncoder.AppendObject(redactObject{ObjectMarshaler: value, names: enc.names})
+}
+
+func (enc *redactArrayEncoder) AppendReflected(value any) error {
+	value, _ = enc.names.redactReflected(value)
+	return enc.ArrayEncoder.AppendReflected(value)
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPackageLevel(t *testing.T) {
+	defer func(levels map[string]zapcore.Level) { packageLevels = levels }(packageLevels)
+
+	packageLevels = map[string]zapcore.Level{
+		"store":                 zapcore.DebugLevel,
+		"api/service":           zapcore.ErrorLevel,
+		"example.com/x/webhook": zapcore.WarnLevel,
+	}
+
+	for _, tc := range []struct {
+		pkg  string
+		want zapcore.Level
+		ok   bool
+	}{
+		{pkg: "github.com/skip-mev/platform-take-home/store", want: zapcore.DebugLevel, ok: true},
+		{pkg: "store", want: zapcore.DebugLevel, ok: true},
+		{pkg: "github.com/skip-mev/platform-take-home/api/service", want: zapcore.ErrorLevel, ok: true},
+		{pkg: "example.com/x/webhook", want: zapcore.WarnLevel, ok: true},
+		// only whole trailing elements match
+		{pkg: "github.com/skip-mev/platform-take-home/webhook"},
+		{pkg: "github.com/skip-mev/platform-take-home/store/cache"},
+		{pkg: "github.com/skip-mev/platform-take-home/mystore"},
+		{pkg: "github.com/skip-mev/platform-take-home/service"},
+		{pkg: ""},
+	} {
+		got, ok := packageLevel(tc.pkg)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("packageLevel(%q) = %v, %t, want %v, %t", tc.pkg, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+type caller struct{}
+
+func (*caller) pkg() string {
+	return callerPackage(0)
+}
+
+func TestCallerPackage(t *testing.T) {
+	const want = "github.com/skip-mev/platform-take-home/observability/logging"
+
+	if got := callerPackage(0); got != want {
+		t.Errorf("from a function: got %q, want %q", got, want)
+	}
+
+	if got := (&caller{}).pkg(); got != want {
+		t.Errorf("from a method: got %q, want %q", got, want)
+	}
+
+	if got := func() string { return callerPackage(0) }(); got != want {
+		t.Errorf("from a closure: got %q, want %q", got, want)
+	}
+
+	if got := func() string { return callerPackage(1) }(); got != want {
+		t.Errorf("skipping a frame: got %q, want %q", got, want)
+	}
+}
+
+type credentials struct {
+	User     string
+	Password string
+}
+
+func (c credentials) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("user", c.User)
+	enc.AddString("password", c.Password)
+	return enc.AddObject("nested", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddInt("api_token", 42)
+		enc.AddString("host", "db")
+		return nil
+	}))
+}
+
+func TestRedact(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		field zap.Field
+		want  string
+	}{
+		{name: "top level", field: zap.String("password", "hunter2"), want: `{"password":"[REDACTED]"}`},
+		{name: "case and substring", field: zap.Int("DB_Password_Hash", 7), want: `{"DB_Password_Hash":"[REDACTED]"}`},
+		{name: "object as a whole", field: zap.Object("secret", credentials{User: "u", Password: "p"}), want: `{"secret":"[REDACTED]"}`},
+		{name: "not secret", field: zap.String("user", "alice"), want: `{"user":"alice"}`},
+		{
+			name:  "nested in objects",
+			field: zap.Object("credentials", credentials{User: "u", Password: "p"}),
+			want:  `{"credentials":{"user":"u","password":"[REDACTED]","nested":{"api_token":"[REDACTED]","host":"db"}}}`,
+		},
+		{
+			name:  "inline",
+			field: zap.Inline(credentials{User: "u", Password: "p"}),
+			want:  `{"user":"u","password":"[REDACTED]","nested":{"api_token":"[REDACTED]","host":"db"}}`,
+		},
+		{
+			name: "objects in arrays",
+			field: zap.Array("all", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+				enc.AppendString("plain")
+				return enc.AppendObject(credentials{User: "u", Password: "p"})
+			})),
+			want: `{"all":["plain",{"user":"u","password":"[REDACTED]","nested":{"api_token":"[REDACTED]","host":"db"}}]}`,
+		},
+		{
+			name:  "reflected",
+			field: zap.Any("config", map[string]any{"database": map[string]any{"dsn": "postgres://u:p@db", "pool": 10}, "replicas": []any{map[string]any{"token": "t"}}}),
+			want:  `{"config":{"database":{"dsn":"[REDACTED]","pool":10},"replicas":[{"token":"[REDACTED]"}]}}`,
+		},
+		{
+			name:  "reflected, nothing to redact",
+			field: zap.Any("config", struct{ Pool int }{Pool: 10}),
+			want:  `{"config":{"Pool":10}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoderConfig := zapcore.EncoderConfig{MessageKey: "", LineEnding: "\n"}
+			core := &redactCore{
+				Core:  zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(&buf), zapcore.DebugLevel),
+				names: redactNames{"password", "secret", "token", "dsn"},
+			}
+
+			zap.New(core).Info("", tc.field)
+			assertJSON(t, buf.Bytes(), tc.want)
+
+			// fields added with With are redacted too
+			buf.Reset()
+			zap.New(core).With(tc.field).Info("")
+			assertJSON(t, buf.Bytes(), tc.want)
+		})
+	}
+}
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var gotValue, wantValue any
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("%s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatal(err)
+	}
+
+	gotJSON, _ := json.Marshal(gotValue)
+	wantJSON, _ := json.Marshal(wantValue)
+	if !bytes.Equal(gotJSON, wantJSON) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package logging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder encodes entries as logfmt, key=value pairs separated by
+// spaces, with values quoted if need be. Arrays, objects and reflected
+// values are JSON, quoted, and the keys in a namespace are prefixed with
+// its name and a dot. The entry's time, level, logger, caller, message and
+// stack trace come first under the keys of config, as ISO 8601, the
+// lowercase level and the trimmed caller path.
+type logfmtEncoder struct {
+	config *zapcore.EncoderConfig
+	// buf holds the fields added to the encoder, which every entry repeats
+	buf       *buffer.Buffer
+	namespace string
+}
+
+func newLogfmtEncoder(config zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{config: &config, buf: logfmtPool.Get()}
+}
+
+func (enc *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{config: enc.config, buf: logfmtPool.Get(), namespace: enc.namespace}
+	clone.buf.Write(enc.buf.Bytes())
+	return clone
+}
+
+func (enc *logfmtEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := &logfmtEncoder{config: enc.config, buf: logfmtPool.Get()}
+
+	if enc.config.TimeKey != "" {
+		line.AddString(enc.config.TimeKey, entry.Time.Format("2006-01-02T15:04:05.000Z0700"))
+	}
+	if enc.config.LevelKey != "" {
+		line.AddString(enc.config.LevelKey, entry.Level.String())
+	}
+	if enc.config.NameKey != "" && entry.LoggerName != "" {
+		line.AddString(enc.config.NameKey, entry.LoggerName)
+	}
+	if enc.config.CallerKey != "" && entry.Caller.Defined {
+		line.AddString(enc.config.CallerKey, entry.Caller.TrimmedPath())
+	}
+	if enc.config.MessageKey != "" {
+		line.AddString(enc.config.MessageKey, entry.Message)
+	}
+
+	if enc.buf.Len() > 0 {
+		if line.buf.Len() > 0 {
+			line.buf.AppendByte(' ')
+		}
+		line.buf.Write(enc.buf.Bytes())
+	}
+
+	line.namespace = enc.namespace
+	for _, field := range fields {
+		field.AddTo(line)
+	}
+
+	if enc.config.StacktraceKey != "" && entry.Stack != "" {
+		line.namespace = ""
+		line.AddString(enc.config.StacktraceKey, entry.Stack)
+	}
+
+	if enc.config.LineEnding != "" {
+		line.buf.AppendString(enc.config.LineEnding)
+	} else {
+		line.buf.AppendString(zapcore.DefaultLineEnding)
+	}
+
+	return line.buf, nil
+}
+
+func (enc *logfmtEncoder) add(key, value string) {
+	if enc.buf.Len() > 0 {
+		enc.buf.AppendByte(' ')
+	}
+
+	enc.buf.AppendString(strings.Map(func(r rune) rune {
+		if needsQuoting(r) {
+			return '_'
+		}
+		return r
+	}, enc.namespace+key))
+	enc.buf.AppendByte('=')
+
+	if value == "" || strings.IndexFunc(value, needsQuoting) >= 0 {
+		enc.buf.AppendString(strconv.Quote(value))
+	} else {
+		enc.buf.AppendString(value)
+	}
+}
+
+func needsQuoting(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar || !unicode.IsPrint(r)
+}
+
+func (enc *logfmtEncoder) addJSON(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	enc.add(key, string(data))
+	return nil
+}
+
+func (enc *logfmtEncoder) AddArray(key string, value zapcore.ArrayMarshaler) error {
+	fields := zapcore.NewMapObjectEncoder()
+	if err := fields.AddArray(key, value); err != nil {
+		return err
+	}
+	return enc.addJSON(key, fields.Fields[key])
+}
+
+func (enc *logfmtEncoder) AddObject(key string, value zapcore.ObjectMarshaler) error {
+	fields := zapcore.NewMapObjectEncoder()
+	if err := fields.AddObject(key, value); err != nil {
+		return err
+	}
+	return enc.addJSON(key, fields.Fields[key])
+}
+
+func (enc *logfmtEncoder) AddReflected(key string, value any) error {
+	return enc.addJSON(key, value)
+}
+
+func (enc *logfmtEncoder) OpenNamespace(key string) {
+	enc.namespace += key + "."
+}
+
+func (enc *logfmtEncoder) AddBinary(key string, value []byte) {
+	enc.add(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (enc *logfmtEncoder) AddByteString(key string, value []byte) {
+	enc.add(key, string(value))
+}
+
+func (enc *logfmtEncoder) AddString(key, value string) {
+	enc.add(key, value)
+}
+
+func (enc *logfmtEncoder) AddBool(key string, value bool) {
+	enc.add(key, strconv.FormatBool(value))
+}
+
+func (enc *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	enc.add(key, value.String())
+}
+
+func (enc *logfmtEncoder) AddTime(key string, value time.Time) {
+	enc.add(key, value.Format(time.RFC3339Nano))
+}
+
+func (enc *logfmtEncoder) AddComplex128(key string, value complex128) {
+	enc.add(key, strconv.FormatComplex(value, 'g', -1, 128))
+}
+
+func (enc *logfmtEncoder) AddComplex64(key string, value complex64) {
+	enc.add(key, strconv.FormatComplex(complex128(value), 'g', -1, 64))
+}
+
+func (enc *logfmtEncoder) AddFloat64(key string, value float64) {
+	enc.add(key, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (enc *logfmtEncoder) AddFloat32(key string, value float32) {
+	enc.add(key, strconv.FormatFloat(float64(value), 'g', -1, 32))
+}
+
+func (enc *logfmtEncoder) AddInt(key string, value int) {
+	enc.AddInt64(key, int64(value))
+}
+
+func (enc *logfmtEncoder) AddInt64(key string, value int64) {
+	enc.add(key, strconv.FormatInt(value, 10))
+}
+
+func (enc *logfmtEncoder) AddInt32(key string, value int32) {
+	enc.AddInt64(key, int64(value))
+}
+
+func (enc *logfmtEncoder) AddInt16(key string, value int16) {
+	enc.AddInt64(key, int64(value))
+}
+
+func (enc *logfmtEncoder) AddInt8(key string, value int8) {
+	enc.AddInt64(key, int64(value))
+}
+
+func (enc *logfmtEncoder) AddUint(key string, value uint) {
+	enc.AddUint64(key, uint64(value))
+}
+
+func (enc *logfmtEncoder) AddUint64(key string, value uint64) {
+	enc.add(key, strconv.FormatUint(value, 10))
+}
+
+func (enc *logfmtEncoder) AddUint32(key string, value uint32) {
+	enc.AddUint64(key, uint64(value))
+}
+
+func (enc *logfmtEncoder) AddUint16(key string, value uint16) {
+	enc.AddUint64(key, uint64(value))
+}
+
+func (enc *logfmtEncoder) AddUint8(key string, value uint8) {
+	enc.AddUint64(key, uint64(value))
+}
+
+func (enc *logfmtEncoder) AddUintptr(key string, value uintptr) {
+	enc.AddUint64(key, uint64(value))
+}
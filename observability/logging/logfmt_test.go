@@ -0,0 +1,104 @@
+package logging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		fields []zap.Field
+		want   string
+	}{
+		{name: "bare", fields: []zap.Field{zap.String("user", "alice"), zap.Int("n", -3), zap.Bool("ok", true)}, want: `user=alice n=-3 ok=true`},
+		{name: "empty", fields: []zap.Field{zap.String("user", "")}, want: `user=""`},
+		{name: "space", fields: []zap.Field{zap.String("q", "road bike")}, want: `q="road bike"`},
+		{name: "equals", fields: []zap.Field{zap.String("filter", "a=b")}, want: `filter="a=b"`},
+		{name: "quote", fields: []zap.Field{zap.String("q", `say "hi"`)}, want: `q="say \"hi\""`},
+		{name: "newline and tab", fields: []zap.Field{zap.String("q", "a\nb\tc")}, want: `q="a\nb\tc"`},
+		{name: "backslash alone", fields: []zap.Field{zap.String("path", `C:\x`)}, want: `path=C:\x`},
+		{name: "unicode", fields: []zap.Field{zap.String("name", "Café")}, want: `name=Café`},
+		{name: "invalid utf-8", fields: []zap.Field{zap.String("raw", "a\xffb")}, want: `raw="a\xffb"`},
+		{name: "key with space", fields: []zap.Field{zap.String("a key", "v")}, want: `a_key=v`},
+		{name: "duration and time", fields: []zap.Field{zap.Duration("took", 1500*time.Millisecond), zap.Time("at", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))}, want: `took=1.5s at=2024-01-02T03:04:05Z`},
+		{name: "float", fields: []zap.Field{zap.Float64("rate", 0.25)}, want: `rate=0.25`},
+		{name: "binary", fields: []zap.Field{zap.Binary("b", []byte{0, 1, 2})}, want: `b=AAEC`},
+		{name: "error", fields: []zap.Field{zap.Error(errors.New("no such item"))}, want: `error="no such item"`},
+		{name: "strings", fields: []zap.Field{zap.Strings("ids", []string{"a", "b c"})}, want: `ids="[\"a\",\"b c\"]"`},
+		{
+			name: "object",
+			fields: []zap.Field{zap.Object("item", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+				enc.AddUint64("id", 7)
+				enc.AddString("name", "Kettle")
+				return nil
+			}))},
+			want: `item="{\"id\":7,\"name\":\"Kettle\"}"`,
+		},
+		{name: "reflected", fields: []zap.Field{zap.Any("labels", map[string]string{"env": "prod"})}, want: `labels="{\"env\":\"prod\"}"`},
+		{name: "namespace", fields: []zap.Field{zap.String("a", "1"), zap.Namespace("req"), zap.String("id", "x"), zap.Namespace("peer"), zap.Int("port", 80)}, want: `a=1 req.id=x req.peer.port=80`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			enc := newLogfmtEncoder(zapcore.EncoderConfig{LineEnding: "\n"})
+
+			buf, err := enc.EncodeEntry(zapcore.Entry{}, tc.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer buf.Free()
+
+			if got := buf.String(); got != tc.want+"\n" {
+				t.Errorf("got  %s want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLogfmtEntry(t *testing.T) {
+	config := zap.NewProductionEncoderConfig()
+	config.CallerKey = "caller"
+	config.NameKey = "logger"
+
+	enc := newLogfmtEncoder(config)
+	enc.AddString("service", "items")
+	enc.OpenNamespace("ctx")
+
+	// the fields added to the encoder stay with its clones, namespace too
+	clone := enc.Clone()
+	clone.AddString("user", "alice")
+
+	entry := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.UTC),
+		LoggerName: "server",
+		Caller:     zapcore.NewEntryCaller(0, "/src/github.com/skip-mev/platform-take-home/store/store.go", 42, true),
+		Message:    "slow query",
+		Stack:      "goroutine 1",
+	}
+
+	buf, err := clone.EncodeEntry(entry, []zap.Field{zap.Int("ms", 250)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Free()
+
+	want := `ts=2024-01-02T03:04:05.006Z level=warn logger=server caller=store/store.go:42 msg="slow query" service=items ctx.user=alice ctx.ms=250 stacktrace="goroutine 1"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %s want %s", got, want)
+	}
+
+	// the original is left as it was
+	buf, err = enc.EncodeEntry(zapcore.Entry{Message: "m"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buf.Free()
+
+	if got := buf.String(); got != "ts=0001-01-01T00:00:00.000Z level=info msg=m service=items\n" {
+		t.Errorf("original encoder: got %s", got)
+	}
+}
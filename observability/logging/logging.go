@@ -5,18 +5,147 @@ import (
 	"encoding/binary"
 	"fmt"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/skip-mev/platform-take-home/requestid"
+	"go.opentelemetry.io/contrib/bridges/otelzap"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+const (
+	// instrumentationName names the logs exported over OTLP.
+	instrumentationName = "github.com/skip-mev/platform-take-home"
+
+	redactedValue = "[REDACTED]"
+)
+
+var (
+	// level is shared by every logger DefaultLogger builds, so that it can
+	// be changed at runtime.
+	level = zap.NewAtomicLevel()
+
+	// the core every logger DefaultLogger builds writes to, built once so
+	// that they share the log file and the OTLP exporter
+	buildOnce     sync.Once
+	defaultCore   zapcore.Core
+	defaultConfig Config
+	buildErr      error
+
+	packageLevels  map[string]zapcore.Level
+	loggerProvider *sdklog.LoggerProvider
+)
+
+// DefaultLogger builds a logger configured by ConfigFromEnv.
 func DefaultLogger(options ...zap.Option) (*zap.Logger, error) {
-	if os.Getenv("DEV_LOGGING") == "true" {
-		return zap.NewDevelopment(options...)
+	buildOnce.Do(func() {
+		defaultConfig, buildErr = ConfigFromEnv()
+		if buildErr == nil {
+			defaultCore, buildErr = buildCore(defaultConfig)
+		}
+	})
+	if buildErr != nil {
+		return nil, buildErr
+	}
+
+	stacktraceLevel := zapcore.ErrorLevel
+	if defaultConfig.Development {
+		stacktraceLevel = zapcore.WarnLevel
+		options = append([]zap.Option{zap.Development()}, options...)
+	}
+
+	options = append([]zap.Option{
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		zap.AddCaller(),
+		zap.AddStacktrace(stacktraceLevel),
+	}, options...)
+
+	return zap.New(defaultCore, options...), nil
+}
+
+// buildCore builds the core config describes, which logs at level, or at
+// the level of the calling package, see FromContext.
+func buildCore(config Config) (zapcore.Core, error) {
+	level.SetLevel(config.Level)
+	packageLevels = config.Levels
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	if config.Development {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	var encoder zapcore.Encoder
+	switch config.Encoding {
+	case "console":
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case "logfmt":
+		encoder = newLogfmtEncoder(encoderConfig)
+	default:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	writer := zapcore.Lock(os.Stderr)
+	if config.File != "" {
+		writer = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   config.File,
+			MaxSize:    config.FileMaxSize,
+			MaxBackups: config.FileMaxBackups,
+			MaxAge:     config.FileMaxAgeDays,
+		})
+	}
+
+	// let through what any package logs, and leave the rest to levelCore
+	lowest := zapcore.InvalidLevel
+	for _, packageLevel := range config.Levels {
+		lowest = min(lowest, packageLevel)
+	}
+	enabler := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return level.Enabled(l) || l >= lowest
+	})
+
+	core := zapcore.NewCore(encoder, writer, enabler)
+
+	if config.OTLP {
+		exporter, err := otlploggrpc.New(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error creating OTLP log exporter: %w", err)
+		}
+		loggerProvider = sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+
+		core = zapcore.NewTee(core, otelzap.NewCore(instrumentationName, otelzap.WithLoggerProvider(loggerProvider)))
+	}
+
+	if len(config.RedactFields) > 0 {
+		core = &redactCore{Core: core, names: redactNames(config.RedactFields)}
 	}
 
-	return zap.NewProduction(options...)
+	if config.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, config.SamplingInitial, config.SamplingThereafter)
+	}
+
+	return &levelCore{Core: core, enabler: level}, nil
+}
+
+// Shutdown flushes the logs not yet exported over OTLP and stops exporting
+// them.
+func Shutdown(ctx context.Context) error {
+	if loggerProvider == nil {
+		return nil
+	}
+	return loggerProvider.Shutdown(ctx)
+}
+
+// Level is the level of the loggers DefaultLogger builds, except in the
+// packages Config.Levels sets levels for. As an http.Handler, it reports the
+// level on GET and changes it on PUT, e.g. with {"level": "debug"}.
+func Level() zap.AtomicLevel {
+	return level
 }
 
 func WithDefaultLogger(ctx context.Context, options ...zap.Option) (context.Context, error) {
@@ -62,9 +191,23 @@ func FromContext(ctx context.Context) *zap.Logger {
 		logger.Error("missing logger on ctx", zap.Any("ctx", ctx))
 	}
 
-	traceID, ok := TraceIDFromContext(ctx)
-	if ok {
-		logger = logger.With(zap.String("traceId", traceID.String()))
+	if len(packageLevels) > 0 {
+		logger = withPackageLevel(logger, callerPackage(1))
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		logger = logger.With(
+			zap.String("traceId", spanContext.TraceID().String()),
+			zap.String("spanId", spanContext.SpanID().String()),
+			// only read by the OTLP exporter, which correlates the records
+			// with the span
+			zap.Field{Key: "context", Type: zapcore.SkipType, Interface: trace.ContextWithSpanContext(context.Background(), spanContext)},
+		)
+	}
+
+	if id := requestid.FromContext(ctx); id != "" {
+		logger = logger.With(zap.String("requestId", id))
 	}
 
 	service, ok := ctx.Value(serviceLabelKey).(string)
@@ -77,19 +220,39 @@ func FromContext(ctx context.Context) *zap.Logger {
 
 func UnaryServerInterceptor(logger *zap.Logger, sampleRate float64) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		loggerForContext := logger
-		traceID, ok := TraceIDFromContext(ctx)
-		if ok {
-			traceInt := binary.BigEndian.Uint16(traceID[:])
-			if traceInt%100 > uint16(sampleRate*100) {
-				loggerForContext = zap.NewNop()
-			}
-		}
-		newCtx := WithLogger(ctx, loggerForContext)
+		newCtx := WithLogger(ctx, sampledLogger(ctx, logger, sampleRate))
 		return handler(newCtx, req)
 	}
 }
 
+func StreamServerInterceptor(logger *zap.Logger, sampleRate float64) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		newCtx := WithLogger(stream.Context(), sampledLogger(stream.Context(), logger, sampleRate))
+		return handler(srv, &loggingStream{ServerStream: stream, ctx: newCtx})
+	}
+}
+
+// sampledLogger returns logger, or a no-op logger for traces sampled out.
+func sampledLogger(ctx context.Context, logger *zap.Logger, sampleRate float64) *zap.Logger {
+	traceID, ok := TraceIDFromContext(ctx)
+	if ok {
+		traceInt := binary.BigEndian.Uint16(traceID[:])
+		if traceInt%100 > uint16(sampleRate*100) {
+			return zap.NewNop()
+		}
+	}
+	return logger
+}
+
+type loggingStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggingStream) Context() context.Context {
+	return s.ctx
+}
+
 func DefaultLoggingContext() context.Context {
 	ctx, err := WithDefaultLogger(context.Background())
 	if err != nil {
@@ -4,41 +4,70 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
-	"log"
 	"net"
 	"net/http"
+	"time"
 )
 
-func ServeMetrics(ctx context.Context, host string, port uint) error {
-	exporter, err := prometheus.New()
-	if err != nil {
-		log.Fatal(err)
-	}
-	provider := metric.NewMeterProvider(metric.WithReader(exporter))
-	otel.SetMeterProvider(provider)
+// shutdownTimeout bounds how long a stopping metrics server waits for
+// scrapes in flight.
+const shutdownTimeout = 5 * time.Second
 
-	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+// Provider is a meter provider whose metrics Serve exports, along with
+// those of the Go runtime and the process.
+type Provider struct {
+	*metric.MeterProvider
+	registry *prom.Registry
+}
 
+// NewProvider creates a Provider. Each exports from a registry of its own,
+// so that servers started in the same process, e.g. by tests, don't clash;
+// the command makes its provider the global one, which instruments such as
+// those of the store use.
+func NewProvider() (*Provider, error) {
+	registry := prom.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	exporter, err := prometheus.New(prometheus.WithRegisterer(registry))
 	if err != nil {
-		return fmt.Errorf("error creating listener: %v", err)
+		return nil, fmt.Errorf("error creating exporter: %v", err)
 	}
 
-	server := http.Server{Handler: promhttp.Handler()}
+	return &Provider{MeterProvider: metric.NewMeterProvider(metric.WithReader(exporter)), registry: registry}, nil
+}
+
+// Serve exports the metrics of provider on listener until ctx is done, then
+// shuts down the provider, flushing it.
+func Serve(ctx context.Context, listener net.Listener, provider *Provider) error {
+	handler := promhttp.InstrumentMetricHandler(provider.registry, promhttp.HandlerFor(provider.registry, promhttp.HandlerOpts{}))
+	server := http.Server{Handler: handler}
 
+	shutdown := make(chan error, 1)
 	go func() {
 		<-ctx.Done()
-		if err := server.Shutdown(context.Background()); err != nil {
-			panic(err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(ctx)
+		if err != nil {
+			err = errors.Join(err, server.Close())
 		}
+		shutdown <- errors.Join(err, provider.Shutdown(ctx))
 	}()
 
 	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("error serving metrics: %v", err)
 	}
 
+	if err := <-shutdown; err != nil {
+		return fmt.Errorf("error shutting down metrics server: %w", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// serve serves provider until the test ends and returns its metrics URL.
+func serve(t *testing.T, provider *Provider) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, listener, provider) }()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("error serving: %v", err)
+		}
+	})
+
+	return "http://" + listener.Addr().String() + "/metrics"
+}
+
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func TestServe(t *testing.T) {
+	first, err := NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counter, err := first.Meter("test").Int64Counter("test.requests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(context.Background(), 3)
+
+	firstURL, secondURL := serve(t, first), serve(t, second)
+
+	body := scrape(t, firstURL)
+	if !regexp.MustCompile(`(?m)^test_requests_total\{.*\} 3$`).MatchString(body) || !strings.Contains(body, "go_goroutines") {
+		t.Errorf("first provider's metrics lack the counter or the runtime's:\n%s", body)
+	}
+
+	// providers don't share their instruments
+	if body := scrape(t, secondURL); strings.Contains(body, "test_requests") || !strings.Contains(body, "go_goroutines") {
+		t.Errorf("second provider's metrics:\n%.500s", body)
+	}
+}
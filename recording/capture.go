@@ -0,0 +1,288 @@
+package recording
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// gatewayKey carries the ID of a gateway request to the gRPC server, or the
+// recorder's skip ID if it isn't recorded. Clients can send it too, so the
+// interceptor only trusts IDs the recorder generated.
+const gatewayKey = "x-recording-id"
+
+const redacted = "[REDACTED]"
+
+var marshalOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// UnaryServerInterceptor records sampled unary calls. Calls through the
+// gateway are sampled by Middleware instead, which writes their records.
+// Streaming calls are not recorded.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if r == nil {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		gatewayID := r.gatewayID(md)
+		switch {
+		case gatewayID == r.skip:
+			return handler(ctx, req)
+		case gatewayID == "" && !r.sample():
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		record := &Record{
+			Time:      start,
+			Method:    info.FullMethod,
+			Namespace: tenancy.FromContext(ctx),
+			Transport: "grpc",
+			Metadata:  redactMetadata(md),
+			Request:   marshal(req),
+			Code:      status.Code(err).String(),
+			Duration:  milliseconds(time.Since(start)),
+		}
+
+		if err != nil {
+			record.Error = status.Convert(err).Message()
+		} else {
+			record.Response = marshal(resp)
+		}
+
+		if gatewayID != "" {
+			r.attach(gatewayID, record)
+		} else if err := r.write(record); err != nil {
+			logging.FromContext(ctx).Error("error recording request", zap.Error(err))
+		}
+
+		return resp, err
+	}
+}
+
+// Middleware records sampled requests to the gateway. The gateway turns a
+// request into a gRPC call, which UnaryServerInterceptor captures and hands
+// back, tagged by GatewayMetadata; Middleware then adds the HTTP side and
+// writes the combined record. Requests that never reach a unary RPC, such as
+// unknown routes and watches, are not recorded.
+func (r *Recorder) Middleware(next http.Handler) http.Handler {
+	if r == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !r.sample() {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), gatewayIDKey, r.skip)))
+			return
+		}
+
+		id := newID()
+		r.pendingMu.Lock()
+		r.pending[id] = nil
+		r.pendingMu.Unlock()
+
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		url := req.URL.RequestURI()
+		headers := redactHeaders(req.Header)
+
+		next.ServeHTTP(sw, req.WithContext(context.WithValue(req.Context(), gatewayIDKey, id)))
+
+		r.pendingMu.Lock()
+		record := r.pending[id]
+		delete(r.pending, id)
+		r.pendingMu.Unlock()
+
+		if record == nil {
+			return
+		}
+
+		record.Time = start
+		record.Transport = "rest"
+		record.Metadata = headers
+		record.Duration = milliseconds(time.Since(start))
+		record.HTTP = &HTTPExchange{Method: req.Method, URL: url, Status: sw.status}
+
+		if err := r.write(record); err != nil {
+			logging.FromContext(req.Context()).Error("error recording request", zap.Error(err))
+		}
+	})
+}
+
+// GatewayMetadata passes the ID Middleware gave a request on to the gRPC
+// server. Register it with runtime.WithMetadata.
+func (r *Recorder) GatewayMetadata(_ context.Context, req *http.Request) metadata.MD {
+	if r == nil {
+		return nil
+	}
+
+	id, _ := req.Context().Value(gatewayIDKey).(string)
+	if id == "" {
+		return nil
+	}
+
+	return metadata.Pairs(gatewayKey, id)
+}
+
+// prevent collisions with other packages
+type key int
+
+var gatewayIDKey key = 0
+
+// gatewayID returns the ID GatewayMetadata passed with a call, ignoring any
+// the client made up: only the skip ID and those of pending requests count.
+func (r *Recorder) gatewayID(md metadata.MD) string {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	for _, id := range md.Get(gatewayKey) {
+		if _, ok := r.pending[id]; ok || id == r.skip {
+			return id
+		}
+	}
+
+	return ""
+}
+
+func (r *Recorder) attach(id string, record *Record) {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+
+	if _, ok := r.pending[id]; ok {
+		r.pending[id] = record
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// marshal encodes a request or response with its secrets redacted.
+func marshal(message interface{}) []byte {
+	m, ok := message.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	m = proto.Clone(m)
+	redactMessage(m.ProtoReflect())
+
+	data, err := marshalOptions.Marshal(m)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// secret reports whether a header by this name holds a credential.
+func secret(name string) bool {
+	name = strings.ToLower(name)
+
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+
+	for _, word := range []string{"secret", "token", "password", "api-key", "apikey", "api_key"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// secretField is narrower than secret, so that e.g. page tokens are kept
+// and the request can be replayed.
+func secretField(name protoreflect.Name) bool {
+	return name == "secret" || name == "password" || strings.HasSuffix(string(name), "_secret")
+}
+
+func redactMetadata(md metadata.MD) map[string][]string {
+	out := make(map[string][]string, len(md))
+
+	for key, values := range md {
+		switch {
+		case key == gatewayKey || strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-"):
+		case secret(key):
+			out[key] = []string{redacted}
+		default:
+			out[key] = values
+		}
+	}
+
+	return out
+}
+
+func redactHeaders(header http.Header) map[string][]string {
+	out := make(map[string][]string, len(header))
+
+	for key, values := range header {
+		if secret(key) {
+			out[key] = []string{redacted}
+		} else {
+			out[key] = values
+		}
+	}
+
+	return out
+}
+
+// redactMessage replaces string fields named like secrets, e.g. the signing
+// secret of a webhook, in m and the messages it contains.
+func redactMessage(m protoreflect.Message) {
+	m.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		switch {
+		case field.Kind() == protoreflect.StringKind && !field.IsList() && !field.IsMap() && secretField(field.Name()):
+			m.Set(field, protoreflect.ValueOfString(redacted))
+		case field.Kind() == protoreflect.MessageKind && field.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message())
+			}
+		case field.Kind() == protoreflect.MessageKind && !field.IsMap():
+			redactMessage(value.Message())
+		}
+		return true
+	})
+}
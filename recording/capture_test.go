@@ -0,0 +1,193 @@
+package recording
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const createWebhook = "/skip.platform.api.TakeHomeService/CreateWebhook"
+
+// call sends req through the interceptor, like the gRPC server would.
+func call(t *testing.T, r *Recorder, md metadata.MD, req interface{}) {
+	t.Helper()
+
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	info := &grpc.UnaryServerInfo{FullMethod: createWebhook}
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return &types.CreateWebhookResponse{Webhook: &types.Webhook{Id: 1, Secret: "hunter2"}}, nil
+	}
+
+	if _, err := r.UnaryServerInterceptor()(ctx, req, info, handler); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	r := newTestRecorder(t, Config{})
+
+	md := metadata.Pairs(
+		"authorization", "Bearer s3cret",
+		"x-api-key", "k3y",
+		"x-namespace", "tenant",
+		"grpc-timeout", "1S",
+	)
+	call(t, r, md, &types.CreateWebhookRequest{Webhook: &types.Webhook{Url: "https://example.com", Secret: "hunter2"}})
+
+	records := readRecords(t, r.config.Path)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	record := records[0]
+
+	wantMetadata := map[string][]string{
+		"authorization": {redacted},
+		"x-api-key":     {redacted},
+		"x-namespace":   {"tenant"},
+	}
+	if !reflect.DeepEqual(record.Metadata, wantMetadata) {
+		t.Errorf("metadata = %v, want %v", record.Metadata, wantMetadata)
+	}
+
+	var req types.CreateWebhookRequest
+	if err := protojson.Unmarshal(record.Request, &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Webhook.Secret != redacted || req.Webhook.Url != "https://example.com" {
+		t.Errorf("request = %s, want the secret redacted", record.Request)
+	}
+
+	var resp types.CreateWebhookResponse
+	if err := protojson.Unmarshal(record.Response, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Webhook.Secret != redacted || resp.Webhook.Id != 1 {
+		t.Errorf("response = %s, want the secret redacted", record.Response)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{
+		"Cookie":                 {"session=abc"},
+		"X-Auth-Token":           {"t0ken"},
+		"Grpc-Metadata-Password": {"pw"},
+		"Accept":                 {"application/json"},
+	}
+
+	want := map[string][]string{
+		"Cookie":                 {redacted},
+		"X-Auth-Token":           {redacted},
+		"Grpc-Metadata-Password": {redacted},
+		"Accept":                 {"application/json"},
+	}
+	if got := redactHeaders(header); !reflect.DeepEqual(got, want) {
+		t.Errorf("redactHeaders() = %v, want %v", got, want)
+	}
+}
+
+func TestRedactMessageLists(t *testing.T) {
+	resp := &types.GetWebhooksResponse{Webhooks: []*types.Webhook{{Id: 1, Secret: "a"}, {Id: 2, Secret: "b"}}}
+
+	var got types.GetWebhooksResponse
+	if err := protojson.Unmarshal(marshal(resp), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, webhook := range got.Webhooks {
+		if webhook.Secret != redacted {
+			t.Errorf("webhook %d secret = %q, want redacted", webhook.Id, webhook.Secret)
+		}
+	}
+
+	// the message itself is left alone
+	if resp.Webhooks[0].Secret != "a" {
+		t.Errorf("marshal modified the message")
+	}
+}
+
+func TestClientRecordingID(t *testing.T) {
+	t.Run("unknown ID is ignored", func(t *testing.T) {
+		r := newTestRecorder(t, Config{})
+
+		call(t, r, metadata.Pairs(gatewayKey, "made-up"), &types.CreateWebhookRequest{})
+
+		records := readRecords(t, r.config.Path)
+		if len(records) != 1 {
+			t.Fatalf("got %d records, want 1", len(records))
+		}
+		if _, ok := records[0].Metadata[gatewayKey]; ok {
+			t.Errorf("recorded %s metadata", gatewayKey)
+		}
+	})
+
+	t.Run("old skip ID is ignored", func(t *testing.T) {
+		r := newTestRecorder(t, Config{})
+
+		// "-" used to opt a call out of recording
+		call(t, r, metadata.Pairs(gatewayKey, "-"), &types.CreateWebhookRequest{})
+
+		if got := len(readRecords(t, r.config.Path)); got != 1 {
+			t.Fatalf("got %d records, want 1", got)
+		}
+	})
+}
+
+func TestGateway(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		sampleRate float64
+		// client is the ID the client sends along with the gateway's
+		client  string
+		records int
+	}{
+		{name: "sampled", sampleRate: 1, records: 1},
+		{name: "sampled with client ID", sampleRate: 1, client: "made-up", records: 1},
+		// a rate of 0 would default to 1 in newTestRecorder
+		{name: "not sampled", sampleRate: 0.000001, records: 0},
+		{name: "not sampled with client ID", sampleRate: 0.000001, client: "made-up", records: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestRecorder(t, Config{SampleRate: tc.sampleRate})
+
+			handler := r.Middleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				md := r.GatewayMetadata(req.Context(), req)
+				if tc.client != "" {
+					md = metadata.Join(metadata.Pairs(gatewayKey, tc.client), md)
+				}
+				call(t, r, md, &types.CreateWebhookRequest{})
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/webhooks", strings.NewReader("{}"))
+			req.Header.Set("Authorization", "Bearer s3cret")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			records := readRecords(t, r.config.Path)
+			if len(records) != tc.records {
+				t.Fatalf("got %d records, want %d", len(records), tc.records)
+			}
+			if tc.records == 0 {
+				return
+			}
+
+			record := records[0]
+			if record.Transport != "rest" || record.HTTP == nil || record.HTTP.Status != http.StatusCreated || record.Method != createWebhook {
+				t.Errorf("record = %+v, want the REST request to %s", record, createWebhook)
+			}
+			if got := record.Metadata["Authorization"]; !reflect.DeepEqual(got, []string{redacted}) {
+				t.Errorf("authorization header = %v, want redacted", got)
+			}
+			if len(r.pending) != 0 {
+				t.Errorf("%d requests still pending", len(r.pending))
+			}
+		})
+	}
+}
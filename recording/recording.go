@@ -0,0 +1,230 @@
+// Package recording captures sampled requests and responses of the server
+// into a rotating JSONL file, to reproduce production traffic locally. The
+// records are request files for loadgen, which replays them and, with
+// -compare, diffs the new responses against the recorded ones.
+package recording
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxSize  = 100 << 20
+	defaultMaxFiles = 5
+)
+
+// Config configures a Recorder.
+type Config struct {
+	// Path is the file records are appended to. Once it reaches MaxSize it
+	// is rotated to Path.1, Path.1 to Path.2 and so on, keeping MaxFiles
+	// rotated files.
+	Path     string
+	MaxSize  int64
+	MaxFiles int
+	// SampleRate is the fraction of requests recorded, from 0 to 1.
+	SampleRate float64
+}
+
+// ConfigFromEnv reads RECORD_FILE, RECORD_SAMPLE_RATE (default 1),
+// RECORD_MAX_SIZE_MB (default 100) and RECORD_MAX_FILES (default 5).
+// Recording is disabled if RECORD_FILE is unset.
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		Path:       os.Getenv("RECORD_FILE"),
+		MaxSize:    defaultMaxSize,
+		MaxFiles:   defaultMaxFiles,
+		SampleRate: 1,
+	}
+
+	if value := os.Getenv("RECORD_SAMPLE_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return config, fmt.Errorf("invalid RECORD_SAMPLE_RATE %q: must be between 0 and 1", value)
+		}
+		config.SampleRate = rate
+	}
+
+	if value := os.Getenv("RECORD_MAX_SIZE_MB"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || size <= 0 {
+			return config, fmt.Errorf("invalid RECORD_MAX_SIZE_MB %q", value)
+		}
+		config.MaxSize = size << 20
+	}
+
+	if value := os.Getenv("RECORD_MAX_FILES"); value != "" {
+		files, err := strconv.Atoi(value)
+		if err != nil || files < 0 {
+			return config, fmt.Errorf("invalid RECORD_MAX_FILES %q", value)
+		}
+		config.MaxFiles = files
+	}
+
+	return config, nil
+}
+
+// Record is a line of the recording. Method, Namespace and Request make it a
+// loadgen request; the rest describes what happened.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Method is the full gRPC method, e.g. /skip.platform.api.TakeHomeService/GetItem.
+	Method    string `json:"method"`
+	Namespace string `json:"namespace,omitempty"`
+	// Transport is "grpc" or "rest".
+	Transport string `json:"transport"`
+	// Metadata holds the gRPC metadata or HTTP headers, without secrets.
+	Metadata map[string][]string `json:"metadata,omitempty"`
+	// Request and Response are in the JSON form of the REST API.
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	// Code is the gRPC status code and Error its message.
+	Code     string        `json:"code"`
+	Error    string        `json:"error,omitempty"`
+	Duration float64       `json:"duration_ms"`
+	HTTP     *HTTPExchange `json:"http,omitempty"`
+}
+
+// HTTPExchange is the REST side of a request that came through the gateway.
+type HTTPExchange struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+}
+
+// Recorder appends records to a rotating file. A nil *Recorder records
+// nothing, so callers don't need to check whether recording is enabled.
+type Recorder struct {
+	config Config
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// pending holds the gRPC half of gateway requests, see Middleware.
+	pendingMu sync.Mutex
+	pending   map[string]*Record
+	// skip tags gateway requests that weren't sampled.
+	skip string
+}
+
+// New opens the recording at config.Path, or returns nil if it is empty.
+func New(config Config) (*Recorder, error) {
+	if config.Path == "" {
+		return nil, nil
+	}
+
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultMaxSize
+	}
+
+	r := &Recorder{config: config, pending: map[string]*Record{}, skip: newID()}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) open() error {
+	file, err := os.OpenFile(r.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file, r.size = file, info.Size()
+
+	return nil
+}
+
+// sample reports whether to record a request.
+func (r *Recorder) sample() bool {
+	return r.config.SampleRate >= 1 || rand.Float64() < r.config.SampleRate
+}
+
+func (r *Recorder) write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return errors.New("recording is closed")
+	}
+
+	if r.size > 0 && r.size+int64(len(line)) > r.config.MaxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+
+	return err
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, and starts
+// a new file.
+func (r *Recorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	path := r.config.Path
+	rotated := func(i int) string { return path + "." + strconv.Itoa(i) }
+
+	if r.config.MaxFiles == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	} else {
+		for i := r.config.MaxFiles - 1; i >= 1; i-- {
+			if err := os.Rename(rotated(i), rotated(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+
+		if err := os.Rename(path, rotated(1)); err != nil {
+			return err
+		}
+	}
+
+	return r.open()
+}
+
+// Close closes the recording.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+
+	return err
+}
@@ -0,0 +1,186 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestRecorder(t *testing.T, config Config) *Recorder {
+	t.Helper()
+
+	if config.Path == "" {
+		config.Path = filepath.Join(t.TempDir(), "recording.jsonl")
+	}
+	if config.SampleRate == 0 {
+		config.SampleRate = 1
+	}
+
+	r, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+
+	return r
+}
+
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+func TestNewDisabled(t *testing.T) {
+	r, err := New(Config{})
+	if err != nil || r != nil {
+		t.Fatalf("New(Config{}) = %v, %v, want nil, nil", r, err)
+	}
+
+	// a nil recorder records nothing
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	record := &Record{Method: "/skip.platform.api.TakeHomeService/GetItem", Code: "OK"}
+	line, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(len(line) + 1)
+
+	for _, tc := range []struct {
+		name     string
+		maxFiles int
+		// files are the number of records in Path, Path.1, Path.2...
+		files []int
+	}{
+		{name: "keeps max files", maxFiles: 2, files: []int{1, 2, 2}},
+		{name: "no rotated files", maxFiles: 0, files: []int{1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "recording.jsonl")
+			r := newTestRecorder(t, Config{Path: path, MaxSize: 2 * size, MaxFiles: tc.maxFiles})
+
+			// 7 records in files of 2: the oldest rotated file is dropped
+			for i := 0; i < 7; i++ {
+				if err := r.write(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, want := range tc.files {
+				name := path
+				if i > 0 {
+					name += "." + strconv.Itoa(i)
+				}
+				if got := len(readRecords(t, name)); got != want {
+					t.Errorf("%s has %d records, want %d", filepath.Base(name), got, want)
+				}
+			}
+
+			if _, err := os.Stat(path + "." + strconv.Itoa(len(tc.files))); !os.IsNotExist(err) {
+				t.Errorf("rotated file beyond MaxFiles exists: %v", err)
+			}
+		})
+	}
+}
+
+func TestRotateReopened(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	record := &Record{Method: "/skip.platform.api.TakeHomeService/GetItem", Code: "OK"}
+
+	r := newTestRecorder(t, Config{Path: path, MaxSize: 1 << 20, MaxFiles: 1})
+	if err := r.write(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the size of the existing file counts towards MaxSize
+	r = newTestRecorder(t, Config{Path: path, MaxSize: info.Size() + 1, MaxFiles: 1})
+	if err := r.write(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(readRecords(t, path+".1")); got != 1 {
+		t.Errorf("rotated file has %d records, want 1", got)
+	}
+	if got := len(readRecords(t, path)); got != 1 {
+		t.Errorf("recording has %d records, want 1", got)
+	}
+}
+
+func TestWriteClosed(t *testing.T) {
+	r := newTestRecorder(t, Config{})
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.write(&Record{}); err == nil {
+		t.Fatal("write to a closed recording succeeded")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("RECORD_FILE", "recording.jsonl")
+	t.Setenv("RECORD_SAMPLE_RATE", "0.5")
+	t.Setenv("RECORD_MAX_SIZE_MB", "2")
+	t.Setenv("RECORD_MAX_FILES", "0")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{Path: "recording.jsonl", MaxSize: 2 << 20, MaxFiles: 0, SampleRate: 0.5}
+	if config != want {
+		t.Errorf("ConfigFromEnv() = %+v, want %+v", config, want)
+	}
+
+	for name, value := range map[string]string{
+		"RECORD_SAMPLE_RATE": "2",
+		"RECORD_MAX_SIZE_MB": "0",
+		"RECORD_MAX_FILES":   "-1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			if _, err := ConfigFromEnv(); err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("ConfigFromEnv() with %s=%s: error %v, want invalid %s", name, value, err, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Package requestid carries the ID of a request, which follows it from the
+// gateway through the gRPC server, the service and the store into every log
+// line, and back to the client in the response headers and error details.
+package requestid
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Header carries the request ID. The gateway forwards it as gRPC
+	// metadata, and both return it as a response header.
+	Header = "X-Request-Id"
+
+	// SpanAttribute is the attribute of the server span holding the ID.
+	SpanAttribute = "request.id"
+
+	maxLength = 128
+)
+
+var metadataKey = strings.ToLower(Header)
+
+// prevent collisions with other packages
+type key int
+
+var idKey key = 0
+
+// New generates a request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether id can be taken from a client: at most 128
+// printable ASCII characters, without spaces.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey, id)
+}
+
+// FromContext returns the request ID on ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey).(string)
+	return id
+}
+
+// resolve takes the ID of an incoming call from its metadata, or generates
+// one if the client sent none, or an invalid one, and records it on the
+// call's span.
+func resolve(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(Header); len(values) > 0 {
+			id = values[0]
+		}
+	}
+
+	if !Valid(id) {
+		id = New()
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(SpanAttribute, id))
+
+	return WithID(ctx, id), id
+}
+
+// withDetails adds the request ID to the details of err, as a RequestInfo.
+func withDetails(err error, id string) error {
+	if err == nil {
+		return nil
+	}
+
+	st := status.Convert(err)
+	if st.Code() == codes.OK {
+		return err
+	}
+
+	withID, detailsErr := st.WithDetails(&errdetails.RequestInfo{RequestId: id})
+	if detailsErr != nil {
+		return err
+	}
+
+	return withID.Err()
+}
+
+// UnaryServerInterceptor puts the request ID on the context, returns it in
+// the response headers and adds it to the details of errors. It comes first
+// in the chain, so that the errors of the other interceptors carry it too.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := resolve(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(metadataKey, id))
+
+		resp, err := handler(ctx, req)
+		return resp, withDetails(err, id)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streams.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := resolve(stream.Context())
+		_ = stream.SetHeader(metadata.Pairs(metadataKey, id))
+
+		err := handler(srv, &identifiedStream{ServerStream: stream, ctx: ctx})
+		return withDetails(err, id)
+	}
+}
+
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
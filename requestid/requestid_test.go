@@ -0,0 +1,195 @@
+package requestid
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestValid(t *testing.T) {
+	for _, tc := range []struct {
+		id    string
+		valid bool
+	}{
+		{id: "req-1", valid: true},
+		{id: "0f8fad5b-d9cb-469f-a165-70867728950e", valid: true},
+		{id: "a/b:c=d~!", valid: true},
+		{id: strings.Repeat("x", maxLength), valid: true},
+		{id: "", valid: false},
+		{id: strings.Repeat("x", maxLength+1), valid: false},
+		{id: "req 1", valid: false},
+		{id: "req\t1", valid: false},
+		{id: "req-1\r\nX-Injected: 1", valid: false},
+		{id: "req-\x7f", valid: false},
+		{id: "réq-1", valid: false},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			if got := Valid(tc.id); got != tc.valid {
+				t.Errorf("Valid(%q) = %v, want %v", tc.id, got, tc.valid)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	if id := New(); !Valid(id) || id == New() {
+		t.Errorf("New() = %q, want a valid ID unique to each request", id)
+	}
+}
+
+var interceptorCases = []struct {
+	name string
+	md   metadata.MD
+	// want is the ID the request is handled under, or "" for a generated
+	// one
+	want string
+	err  error
+}{
+	{name: "incoming", md: metadata.Pairs(Header, "req-1"), want: "req-1"},
+	{name: "lower case key", md: metadata.Pairs(metadataKey, "req-1"), want: "req-1"},
+	{name: "first of several", md: metadata.Pairs(Header, "req-1", Header, "req-2"), want: "req-1"},
+	{name: "none", md: metadata.MD{}},
+	{name: "invalid", md: metadata.Pairs(Header, "req 1")},
+	{name: "too long", md: metadata.Pairs(Header, strings.Repeat("x", maxLength+1))},
+	{name: "status error", md: metadata.Pairs(Header, "req-1"), want: "req-1", err: status.Error(codes.NotFound, "item not found")},
+	{name: "plain error", md: metadata.Pairs(Header, "req-1"), want: "req-1", err: errors.New("boom")},
+	{name: "generated for an error", md: metadata.MD{}, err: status.Error(codes.InvalidArgument, "name is required")},
+}
+
+// headerStream records the headers a unary handler sets.
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	for _, tc := range interceptorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &headerStream{}
+			ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), tc.md), stream)
+
+			var handled string
+			_, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				handled = FromContext(ctx)
+				return nil, tc.err
+			})
+
+			assertID(t, handled, tc.want, tc.md)
+			assertHeader(t, stream.header, handled)
+			assertDetails(t, err, tc.err, handled)
+		})
+	}
+}
+
+// serverStream records the headers a stream handler sets.
+type serverStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	for _, tc := range interceptorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &serverStream{ctx: metadata.NewIncomingContext(context.Background(), tc.md)}
+
+			var handled string
+			err := StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(_ interface{}, stream grpc.ServerStream) error {
+				handled = FromContext(stream.Context())
+				return tc.err
+			})
+
+			assertID(t, handled, tc.want, tc.md)
+			assertHeader(t, stream.header, handled)
+			assertDetails(t, err, tc.err, handled)
+		})
+	}
+}
+
+func TestWithDetails(t *testing.T) {
+	if err := withDetails(nil, "req-1"); err != nil {
+		t.Errorf("withDetails(nil) = %v", err)
+	}
+
+	// details already on the error are kept
+	st, err := status.New(codes.InvalidArgument, "invalid item").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name", Description: "required"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	details := status.Convert(withDetails(st.Err(), "req-1")).Details()
+	if len(details) != 2 {
+		t.Fatalf("got details %v, want the bad request and the request info", details)
+	}
+	if _, ok := details[0].(*errdetails.BadRequest); !ok {
+		t.Errorf("got %T first, want the original *errdetails.BadRequest", details[0])
+	}
+}
+
+func assertID(t *testing.T, got, want string, md metadata.MD) {
+	t.Helper()
+
+	switch {
+	case want != "" && got != want:
+		t.Errorf("handled under %q, want the incoming %q", got, want)
+	case want == "" && (!Valid(got) || slices.Contains(md.Get(Header), got)):
+		t.Errorf("handled under %q, want a generated ID", got)
+	}
+}
+
+func assertHeader(t *testing.T, header metadata.MD, id string) {
+	t.Helper()
+
+	if got := header.Get(Header); len(got) != 1 || got[0] != id {
+		t.Errorf("returned header %v, want %q", got, id)
+	}
+}
+
+// assertDetails checks that err is want with id added to its details.
+func assertDetails(t *testing.T, err, want error, id string) {
+	t.Helper()
+
+	if want == nil {
+		if err != nil {
+			t.Errorf("got error %v, want none", err)
+		}
+		return
+	}
+
+	st := status.Convert(err)
+	if wantSt := status.Convert(want); st.Code() != wantSt.Code() || st.Message() != wantSt.Message() {
+		t.Errorf("got %v, want %v", err, want)
+	}
+
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("got details %v, want the request info", details)
+	}
+	if info, ok := details[0].(*errdetails.RequestInfo); !ok || info.RequestId != id {
+		t.Errorf("got details %v, want request ID %q", details[0], id)
+	}
+}
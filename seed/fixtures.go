@@ -0,0 +1,108 @@
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/store"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures is the content of a fixture file:
+//
+//	namespace: default
+//	kinds:
+//	  - name: gadget
+//	    schema: {type: object, required: [color]}
+//	items:
+//	  - id: kettle
+//	    name: Kettle
+//	    kind: gadget
+//	    labels: {env: dev}
+//	    attributes: {color: red}
+type Fixtures struct {
+	// Namespace the fixtures go into, by default the default namespace.
+	Namespace string    `json:"namespace"`
+	Kinds     []Kind    `json:"kinds"`
+	Items     []Fixture `json:"items"`
+}
+
+type Kind struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// Fixture is an item with a stable ID of its own, which must be a valid
+// label value.
+type Fixture struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Kind        string                 `json:"kind"`
+	Labels      map[string]string      `json:"labels"`
+	Attributes  map[string]interface{} `json:"attributes"`
+}
+
+// LoadFile reads a fixture file, in YAML if it ends in .yaml or .yml and in
+// JSON otherwise. Unknown fields are rejected, to catch typos.
+func LoadFile(path string) (Fixtures, error) {
+	var fixtures Fixtures
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixtures, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// go through JSON, so both formats decode to the same types
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fixtures, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if data, err = json.Marshal(doc); err != nil {
+			return fixtures, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&fixtures); err != nil {
+		return fixtures, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return fixtures, nil
+}
+
+// item converts the fixture to the item it seeds, labelled with its ID.
+func (f Fixture) item() (store.Item, error) {
+	if err := store.ValidateLabelValue(f.ID); err != nil || f.ID == "" {
+		return store.Item{}, fmt.Errorf("invalid fixture id %q: must be a non-empty label value", f.ID)
+	}
+
+	labels := store.Labels{FixtureLabel: f.ID}
+	for key, value := range f.Labels {
+		if key == FixtureLabel {
+			return store.Item{}, fmt.Errorf("fixture %q: label %s is reserved", f.ID, FixtureLabel)
+		}
+		labels[key] = value
+	}
+
+	if err := labels.Validate(); err != nil {
+		return store.Item{}, fmt.Errorf("fixture %q: %w", f.ID, err)
+	}
+
+	return store.Item{
+		Name:        f.Name,
+		Description: f.Description,
+		Kind:        f.Kind,
+		Labels:      labels,
+		Attributes:  f.Attributes,
+	}, nil
+}
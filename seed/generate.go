@@ -0,0 +1,66 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+)
+
+var (
+	adjectives = []string{
+		"Compact", "Wireless", "Ergonomic", "Stainless", "Vintage", "Smart", "Portable", "Heavy-Duty",
+		"Foldable", "Insulated", "Rechargeable", "Handcrafted", "Waterproof", "Modular", "Lightweight", "Classic",
+	}
+	materials = []string{"steel", "bamboo", "aluminium", "oak", "recycled plastic", "ceramic", "leather", "carbon fiber"}
+	colors    = []string{"black", "white", "red", "blue", "green", "silver", "orange", "gray"}
+	uses      = []string{"everyday use", "travel", "the office", "the outdoors", "small kitchens", "gifts", "workshops"}
+
+	// products maps categories to the products in them.
+	products = map[string][]string{
+		"kitchen":     {"Kettle", "Mug", "Chef's Knife", "Cutting Board", "Coffee Grinder", "Water Bottle"},
+		"electronics": {"Headphones", "Keyboard", "Desk Lamp", "Power Bank", "Speaker", "Webcam"},
+		"outdoors":    {"Backpack", "Tent", "Camping Stove", "Hammock", "Headlamp", "Sleeping Bag"},
+		"tools":       {"Drill", "Tape Measure", "Screwdriver Set", "Workbench", "Utility Knife", "Level"},
+	}
+	categories = []string{"kitchen", "electronics", "outdoors", "tools"}
+)
+
+// Generate returns n synthetic items that look like a product catalog. The
+// same seed always yields the same items with the same fixture IDs, so
+// regenerating upserts them instead of adding more.
+func Generate(n int, seed uint64) Fixtures {
+	random := rand.New(rand.NewPCG(seed, seed))
+	fixtures := Fixtures{Items: make([]Fixture, 0, n)}
+
+	pick := func(values []string) string {
+		return values[random.IntN(len(values))]
+	}
+
+	for i := 0; i < n; i++ {
+		category := pick(categories)
+		adjective := pick(adjectives)
+		product := pick(products[category])
+		material := pick(materials)
+		color := pick(colors)
+
+		fixtures.Items = append(fixtures.Items, Fixture{
+			ID:   fmt.Sprintf("synthetic-%d-%d", seed, i),
+			Name: adjective + " " + product,
+			Description: fmt.Sprintf("A %s %s %s made of %s, ideal for %s.",
+				color, strings.ToLower(adjective), strings.ToLower(product), material, pick(uses)),
+			Labels: map[string]string{
+				"category": category,
+				"color":    color,
+				"source":   "synthetic",
+			},
+			Attributes: map[string]interface{}{
+				"price":    math.Round((5+random.Float64()*495)*100) / 100,
+				"stock":    float64(random.IntN(500)),
+				"material": material,
+			},
+		})
+	}
+
+	return fixtures
+}
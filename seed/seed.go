@@ -0,0 +1,272 @@
+// Package seed loads fixture items into the store for local and preview
+// environments, either from YAML or JSON files or generated from a random
+// seed. Seeding is an upsert keyed by fixture ID, so it can run on every boot.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FixtureLabel marks seeded items with their fixture ID. Seeding updates the
+// item carrying a fixture's ID instead of creating another one.
+const FixtureLabel = "seed/fixture"
+
+const upsertBatchSize = 500
+
+// Config selects what to seed.
+type Config struct {
+	// Files are fixture files to load, see LoadFile.
+	Files []string
+	// Generate is the number of synthetic items to generate into Namespace.
+	Generate int
+	// RandomSeed makes the synthetic items reproducible.
+	RandomSeed uint64
+	Namespace  string
+}
+
+// ConfigFromEnv reads SEED_FILES, a comma separated list of fixture files,
+// SEED_GENERATE, SEED_RANDOM_SEED and SEED_NAMESPACE. It returns the zero
+// Config, which seeds nothing, if none of them are set.
+func ConfigFromEnv() (Config, error) {
+	config := Config{Namespace: tenancy.DefaultNamespace}
+
+	for _, file := range strings.Split(os.Getenv("SEED_FILES"), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			config.Files = append(config.Files, file)
+		}
+	}
+
+	if generate := os.Getenv("SEED_GENERATE"); generate != "" {
+		var err error
+		if config.Generate, err = strconv.Atoi(generate); err != nil || config.Generate < 0 {
+			return config, fmt.Errorf("invalid SEED_GENERATE %q", generate)
+		}
+	}
+
+	if seed := os.Getenv("SEED_RANDOM_SEED"); seed != "" {
+		var err error
+		if config.RandomSeed, err = strconv.ParseUint(seed, 10, 64); err != nil {
+			return config, fmt.Errorf("invalid SEED_RANDOM_SEED %q", seed)
+		}
+	}
+
+	if namespace := os.Getenv("SEED_NAMESPACE"); namespace != "" {
+		config.Namespace = namespace
+	}
+
+	return config, nil
+}
+
+// Empty reports whether config seeds nothing.
+func (c Config) Empty() bool {
+	return len(c.Files) == 0 && c.Generate == 0
+}
+
+// Result counts what seeding did to the items.
+type Result struct {
+	Created   int
+	Updated   int
+	Unchanged int
+}
+
+// Validator checks an item's attributes against its kind, as the API does.
+type Validator func(ctx context.Context, item *store.Item) error
+
+// Run loads the fixture files and synthetic items config asks for.
+func Run(ctx context.Context, dbStore *store.DBStore, validate Validator, config Config) (Result, error) {
+	var all []Fixtures
+
+	for _, file := range config.Files {
+		fixtures, err := LoadFile(file)
+		if err != nil {
+			return Result{}, err
+		}
+		all = append(all, fixtures)
+	}
+
+	if config.Generate > 0 {
+		fixtures := Generate(config.Generate, config.RandomSeed)
+		fixtures.Namespace = config.Namespace
+		all = append(all, fixtures)
+	}
+
+	var result Result
+
+	for _, fixtures := range all {
+		r, err := Apply(ctx, dbStore, validate, fixtures)
+		result.Created += r.Created
+		result.Updated += r.Updated
+		result.Unchanged += r.Unchanged
+
+		if err != nil {
+			return result, err
+		}
+	}
+
+	logging.FromContext(ctx).Info("seeded items",
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("unchanged", result.Unchanged),
+	)
+
+	return result, nil
+}
+
+// Apply upserts fixtures: kinds first, so the items can be validated against
+// them, then items, skipping those that already match their fixture so
+// reseeding doesn't flood the change log. Applies to the same namespace run
+// one at a time, also in replicas booting together on Postgres, since two
+// could otherwise both find a fixture missing and create it twice.
+func Apply(ctx context.Context, dbStore *store.DBStore, validate Validator, fixtures Fixtures) (Result, error) {
+	namespace := fixtures.Namespace
+	if namespace == "" {
+		namespace = tenancy.DefaultNamespace
+	}
+
+	if err := tenancy.Validate(namespace); err != nil {
+		return Result{}, err
+	}
+
+	// a lagging replica could hide an item seeded a moment ago, which would
+	// then be created twice
+	ctx = store.WithReadFromPrimary(tenancy.WithNamespace(ctx, namespace))
+
+	var result Result
+	err := dbStore.WithLock(ctx, "seed/"+namespace, func() error {
+		var err error
+		result, err = apply(ctx, dbStore, validate, fixtures)
+		return err
+	})
+
+	return result, err
+}
+
+// apply is Apply in the namespace on ctx, holding its lock.
+func apply(ctx context.Context, dbStore *store.DBStore, validate Validator, fixtures Fixtures) (Result, error) {
+	var result Result
+
+	for _, kind := range fixtures.Kinds {
+		if err := applyKind(ctx, dbStore, kind); err != nil {
+			return result, err
+		}
+	}
+
+	existing, err := dbStore.GetItems(ctx, store.ItemFilter{
+		Labels: store.Selector{{Key: FixtureLabel, Operator: store.OpExists}},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	seeded := make(map[string]store.Item, len(existing))
+	for _, item := range existing {
+		seeded[item.Labels[FixtureLabel]] = item
+	}
+
+	seen := make(map[string]bool, len(fixtures.Items))
+	batch := make([]store.Item, 0, upsertBatchSize)
+
+	for _, fixture := range fixtures.Items {
+		if seen[fixture.ID] {
+			return result, fmt.Errorf("duplicate fixture %q", fixture.ID)
+		}
+		seen[fixture.ID] = true
+
+		item, err := fixture.item()
+		if err != nil {
+			return result, err
+		}
+
+		if err := validate(ctx, &item); err != nil {
+			return result, fmt.Errorf("fixture %q: %w", fixture.ID, err)
+		}
+
+		if current, ok := seeded[fixture.ID]; ok {
+			if sameItem(&current, &item) {
+				result.Unchanged++
+				continue
+			}
+
+			item.ID = current.ID
+			result.Updated++
+		} else {
+			result.Created++
+		}
+
+		if batch = append(batch, item); len(batch) == upsertBatchSize {
+			if err := dbStore.UpsertItems(ctx, batch); err != nil {
+				return result, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		if err := dbStore.UpsertItems(ctx, batch); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func applyKind(ctx context.Context, dbStore *store.DBStore, kind Kind) error {
+	schema, err := json.Marshal(kind.Schema)
+	if err != nil {
+		return fmt.Errorf("kind %q: %w", kind.Name, err)
+	}
+
+	current, err := dbStore.GetItemKind(ctx, kind.Name)
+	if err == nil && sameJSON(current.Schema, string(schema)) {
+		return nil
+	}
+
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return dbStore.PutItemKind(ctx, &store.ItemKind{Name: kind.Name, Schema: string(schema)})
+}
+
+// sameItem compares the fields a fixture sets.
+func sameItem(a, b *store.Item) bool {
+	return a.Name == b.Name &&
+		a.Description == b.Description &&
+		a.Kind == b.Kind &&
+		maps.Equal(a.Labels, b.Labels) &&
+		sameJSON(a.Attributes, b.Attributes)
+}
+
+// sameJSON compares values by their JSON encoding, since numbers decode
+// differently from YAML, JSON and the database.
+func sameJSON(a, b interface{}) bool {
+	normalize := func(v interface{}) interface{} {
+		var data []byte
+		if s, ok := v.(string); ok {
+			data = []byte(s)
+		} else {
+			data, _ = json.Marshal(v)
+		}
+
+		var out interface{}
+		_ = json.Unmarshal(data, &out)
+
+		return out
+	}
+
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
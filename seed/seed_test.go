@@ -0,0 +1,191 @@
+package seed
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/service"
+	"github.com/skip-mev/platform-take-home/store"
+	"github.com/skip-mev/platform-take-home/tenancy"
+)
+
+var testFixtures = Fixtures{
+	Namespace: "team-a",
+	Kinds: []Kind{
+		{Name: "gadget", Schema: map[string]interface{}{"type": "object", "required": []interface{}{"color"}}},
+	},
+	Items: []Fixture{
+		{ID: "kettle", Name: "Kettle", Kind: "gadget", Labels: map[string]string{"env": "dev"}, Attributes: map[string]interface{}{"color": "red"}},
+		{ID: "mug", Name: "Mug", Description: "Holds tea"},
+	},
+}
+
+func TestApplyIdempotent(t *testing.T) {
+	ctx := context.Background()
+	dbStore, validate := newStore(t)
+
+	result, err := Apply(ctx, dbStore, validate, testFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (Result{Created: 2}) {
+		t.Errorf("first Apply: got %+v, want 2 created", result)
+	}
+
+	result, err = Apply(ctx, dbStore, validate, testFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (Result{Unchanged: 2}) {
+		t.Errorf("second Apply: got %+v, want 2 unchanged", result)
+	}
+
+	changed := testFixtures
+	changed.Items = append([]Fixture{{ID: "kettle", Name: "Electric kettle", Kind: "gadget", Attributes: map[string]interface{}{"color": "red"}}}, testFixtures.Items[1:]...)
+
+	result, err = Apply(ctx, dbStore, validate, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (Result{Updated: 1, Unchanged: 1}) {
+		t.Errorf("Apply after a change: got %+v, want 1 updated and 1 unchanged", result)
+	}
+
+	assertSeeded(t, dbStore, "kettle", "mug")
+}
+
+// TestApplyConcurrently checks that Applies racing each other, like replicas
+// seeding as they boot, create every fixture once.
+func TestApplyConcurrently(t *testing.T) {
+	ctx := context.Background()
+	dbStore, validate := newStore(t)
+
+	// widen the window between looking up the seeded items and writing them
+	slowValidate := func(ctx context.Context, item *store.Item) error {
+		time.Sleep(10 * time.Millisecond)
+		return validate(ctx, item)
+	}
+
+	var wg sync.WaitGroup
+	created := make([]int, 8)
+	for i := range created {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result, err := Apply(ctx, dbStore, slowValidate, testFixtures)
+			if err != nil {
+				t.Error(err)
+			}
+			created[i] = result.Created
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range created {
+		total += n
+	}
+	if total != 2 {
+		t.Errorf("created %d items in all, want 2", total)
+	}
+
+	assertSeeded(t, dbStore, "kettle", "mug")
+}
+
+func TestApplyInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		fixtures Fixtures
+		want     string
+	}{
+		{
+			name:     "invalid namespace",
+			fixtures: Fixtures{Namespace: "Team A"},
+			want:     "namespace",
+		},
+		{
+			name:     "duplicate fixture",
+			fixtures: Fixtures{Items: []Fixture{{ID: "kettle", Name: "Kettle"}, {ID: "kettle", Name: "Kettle"}}},
+			want:     `duplicate fixture "kettle"`,
+		},
+		{
+			name:     "empty id",
+			fixtures: Fixtures{Items: []Fixture{{Name: "Kettle"}}},
+			want:     "invalid fixture id",
+		},
+		{
+			name:     "id not a label value",
+			fixtures: Fixtures{Items: []Fixture{{ID: "a kettle", Name: "Kettle"}}},
+			want:     "invalid fixture id",
+		},
+		{
+			name:     "reserved label",
+			fixtures: Fixtures{Items: []Fixture{{ID: "kettle", Labels: map[string]string{FixtureLabel: "mug"}}}},
+			want:     "reserved",
+		},
+		{
+			name:     "invalid label",
+			fixtures: Fixtures{Items: []Fixture{{ID: "kettle", Labels: map[string]string{"-env": "dev"}}}},
+			want:     `fixture "kettle"`,
+		},
+		{
+			name:     "attributes violate the kind",
+			fixtures: Fixtures{Kinds: testFixtures.Kinds, Items: []Fixture{{ID: "kettle", Kind: "gadget", Attributes: map[string]interface{}{"size": 2}}}},
+			want:     `fixture "kettle"`,
+		},
+		{
+			name:     "unknown kind",
+			fixtures: Fixtures{Items: []Fixture{{ID: "kettle", Kind: "gizmo"}}},
+			want:     `fixture "kettle"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dbStore, validate := newStore(t)
+
+			_, err := Apply(context.Background(), dbStore, validate, tc.fixtures)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func newStore(t *testing.T) (*store.DBStore, Validator) {
+	t.Helper()
+
+	dbStore, err := store.NewSQLiteBackedStore(store.SQLiteConfig{Path: store.InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbStore.Close() })
+
+	if err := dbStore.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return dbStore, service.NewTakeHomeService(dbStore).ValidateAttributes
+}
+
+// assertSeeded checks that team-a holds exactly one item per fixture ID.
+func assertSeeded(t *testing.T, dbStore *store.DBStore, ids ...string) {
+	t.Helper()
+
+	ctx := tenancy.WithNamespace(context.Background(), "team-a")
+	items, err := dbStore.GetItems(ctx, store.ItemFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Labels[FixtureLabel])
+	}
+
+	if strings.Join(got, ",") != strings.Join(ids, ",") {
+		t.Errorf("got items for fixtures %v, want %v", got, ids)
+	}
+}
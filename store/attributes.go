@@ -0,0 +1,247 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Attributes are structured, team-defined fields of an item. They are stored
+// as JSONB on Postgres and as JSON text on SQLite.
+type Attributes map[string]interface{}
+
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+func (a *Attributes) Scan(value interface{}) error {
+	var b []byte
+
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Attributes", value)
+	}
+
+	return json.Unmarshal(b, a)
+}
+
+func (Attributes) GormDataType() string {
+	return "json"
+}
+
+func (Attributes) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "JSONB"
+	}
+
+	return "JSON"
+}
+
+// ItemKind registers the JSON Schema that the attributes of items of a kind
+// must satisfy. Each namespace registers its own kinds.
+type ItemKind struct {
+	Namespace string `gorm:"primaryKey;size:63;default:'default'"`
+	Name      string `gorm:"primaryKey;size:63"`
+	Schema    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PutItemKind creates or replaces the schema registered for a kind in the
+// namespace on ctx.
+func (s *DBStore) PutItemKind(ctx context.Context, kind *ItemKind) error {
+	return s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		kind.Namespace = namespace
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "namespace"}, {Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"schema", "updated_at"}),
+		}).Create(kind).Error
+	})
+}
+
+// GetItemKind returns gorm.ErrRecordNotFound if no schema is registered for
+// name in the namespace on ctx.
+func (s *DBStore) GetItemKind(ctx context.Context, name string) (*ItemKind, error) {
+	var kind ItemKind
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		return tx.Where("namespace = ? AND name = ?", namespace, name).First(&kind).Error
+	})
+
+	return &kind, err
+}
+
+// itemKind returns the kind named name in namespace inside tx, or nil if
+// name is empty or no such kind exists.
+func itemKind(tx *gorm.DB, namespace, name string) (*ItemKind, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var kind ItemKind
+	err := tx.Where("namespace = ? AND name = ?", namespace, name).Take(&kind).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &kind, err
+}
+
+func (s *DBStore) GetItemKinds(ctx context.Context) ([]ItemKind, error) {
+	var kinds []ItemKind
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		return tx.Where("namespace = ?", namespace).Order("name").Find(&kinds).Error
+	})
+
+	return kinds, err
+}
+
+// AttributeFilter is a single comparison against an attribute path, such as
+// "size.width>=10" or "color=red".
+type AttributeFilter struct {
+	Path     []string
+	Operator string
+	// Value is a JSON scalar: a string, number, boolean or null.
+	Value interface{}
+}
+
+var (
+	attributeFilterPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)\s*(==|!=|>=|<=|=|>|<)\s*(.*?)\s*$`)
+	attributeOperators     = map[string]string{"=": "==", "==": "==", "!=": "!=", ">": ">", ">=": ">=", "<": "<", "<=": "<="}
+)
+
+// ParseAttributeFilters parses a comma separated list of attribute
+// comparisons. Values are read as JSON scalars, so 10 is a number and "10" a
+// string; anything that isn't valid JSON is taken as a bare string.
+func ParseAttributeFilters(input string) ([]AttributeFilter, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	var filters []AttributeFilter
+
+	for _, clause := range splitOutsideQuotes(input, ',') {
+		match := attributeFilterPattern.FindStringSubmatch(clause)
+		if match == nil {
+			return nil, fmt.Errorf("invalid attribute filter %q, expected <path><op><value>", clause)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(match[3]), &value); err != nil {
+			value = match[3]
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("invalid attribute filter %q: value must be a scalar", clause)
+		case string, nil, bool:
+			if op := match[2]; op != "=" && op != "==" && op != "!=" {
+				return nil, fmt.Errorf("invalid attribute filter %q: %s needs a number", clause, op)
+			}
+		}
+
+		filters = append(filters, AttributeFilter{
+			Path:     strings.Split(match[1], "."),
+			Operator: attributeOperators[match[2]],
+			Value:    value,
+		})
+	}
+
+	return filters, nil
+}
+
+// applyAttributeFilters restricts a query on items to those whose attributes
+// satisfy every filter. A missing path never matches, not even for !=.
+func applyAttributeFilters(db *gorm.DB, filters []AttributeFilter) *gorm.DB {
+	for _, filter := range filters {
+		if db.Dialector.Name() == "postgres" {
+			// a jsonpath comparison is type-aware, so 10 never equals "10"
+			path := fmt.Sprintf(`$."%s" ? (@ %s $value)`, strings.Join(filter.Path, `"."`), filter.Operator)
+			vars, _ := json.Marshal(map[string]interface{}{"value": filter.Value})
+
+			db = db.Where("jsonb_path_exists(items.attributes, ?::jsonpath, ?::jsonb)", path, string(vars))
+			continue
+		}
+
+		path := "$." + strings.Join(filter.Path, ".")
+		operator := filter.Operator
+		if operator == "==" {
+			operator = "="
+		}
+
+		switch value := filter.Value.(type) {
+		case nil:
+			if operator == "=" {
+				db = db.Where("json_type(items.attributes, ?) = 'null'", path)
+			} else {
+				db = db.Where("json_type(items.attributes, ?) NOT IN ('null')", path)
+			}
+		case bool:
+			// json_extract returns booleans as 0/1, so compare JSON types as well
+			jsonType := "false"
+			if value {
+				jsonType = "true"
+			}
+
+			if operator == "=" {
+				db = db.Where("json_type(items.attributes, ?) = ?", path, jsonType)
+			} else {
+				db = db.Where("json_type(items.attributes, ?) <> ?", path, jsonType)
+			}
+		case string:
+			db = db.Where(fmt.Sprintf("json_type(items.attributes, ?) = 'text' AND json_extract(items.attributes, ?) %s ?", operator), path, path, value)
+		case float64:
+			db = db.Where(fmt.Sprintf("json_type(items.attributes, ?) IN ('integer', 'real') AND json_extract(items.attributes, ?) %s ?", operator), path, path, value)
+		}
+	}
+
+	return db
+}
+
+// splitOutsideQuotes splits s on sep, ignoring separators inside double quotes.
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes, escaped := false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == sep && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+
+	return append(parts, current.String())
+}
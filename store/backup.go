@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SchemaVersion identifies the layout of the store tables as seen by backups.
+// Bump it whenever a migration changes them in a way older restores can't
+// read, e.g. renamed or repurposed columns.
+const SchemaVersion = 1
+
+const restoreBatchSize = 500
+
+// ErrRestoreNotEmpty is returned by Restore when the store already holds
+// data, which the restored rows could collide with.
+var ErrRestoreNotEmpty = errors.New("restore requires an empty store")
+
+// backupTable dumps and restores one table through its model, so rows are
+// encoded the same way whatever the backend.
+type backupTable struct {
+	name    string
+	dump    func(tx *gorm.DB, write func(row []byte) error) error
+	restore func(tx *gorm.DB, rows [][]byte) error
+	// serial is set for tables with an auto-incrementing id column.
+	serial bool
+}
+
+func tableOf[T any](name string, serial bool) backupTable {
+	return backupTable{
+		name:   name,
+		serial: serial,
+		dump: func(tx *gorm.DB, write func(row []byte) error) error {
+			rows, err := tx.Model(new(T)).Unscoped().Rows()
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				var row T
+				if err := tx.ScanRows(rows, &row); err != nil {
+					return err
+				}
+
+				data, err := json.Marshal(&row)
+				if err != nil {
+					return err
+				}
+
+				if err := write(data); err != nil {
+					return err
+				}
+			}
+
+			return rows.Err()
+		},
+		restore: func(tx *gorm.DB, data [][]byte) error {
+			rows := make([]T, len(data))
+			for i := range data {
+				if err := json.Unmarshal(data[i], &rows[i]); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+
+			return tx.Omit(clause.Associations).CreateInBatches(rows, restoreBatchSize).Error
+		},
+	}
+}
+
+// backupTables lists every store table, parents before the tables referencing
+// them, so restoring in this order satisfies foreign keys.
+var backupTables = []backupTable{
+	tableOf[Item]("items", true),
+	tableOf[ItemLabel]("item_labels", false),
+	tableOf[ItemKind]("item_kinds", false),
+	tableOf[ItemEvent]("item_events", true),
+	tableOf[OutboxMessage]("outbox_messages", true),
+	tableOf[Webhook]("webhooks", true),
+	tableOf[WebhookDelivery]("webhook_deliveries", true),
+	tableOf[ImportCheckpoint]("import_checkpoints", false),
+}
+
+// BackupTables returns the names of the tables a backup contains, in the order
+// Backup writes them and Restore expects them.
+func BackupTables() []string {
+	names := make([]string, 0, len(backupTables))
+	for _, table := range backupTables {
+		names = append(names, table.name)
+	}
+
+	return names
+}
+
+// Backup passes every row of every table, soft-deleted ones included, to
+// write as JSON, table by table in BackupTables order. All tables are read
+// from a single snapshot, so a backup of a live store is consistent.
+func (s *DBStore) Backup(ctx context.Context, write func(table string, row []byte) error) error {
+	db := s.DB
+	if s.readDB != nil {
+		// a WAL read transaction doesn't hold up the writer
+		db = s.readDB
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT set_config('app.namespace', ?, true)", allNamespaces).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range backupTables {
+			err := table.dump(tx, func(row []byte) error { return write(table.name, row) })
+			if err != nil {
+				return fmt.Errorf("error backing up %s: %w", table.name, err)
+			}
+		}
+
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+// Restore loads rows written by Backup into an empty store in a single
+// transaction. read is called with a function that takes each row, table by
+// table in BackupTables order; if read fails, nothing is restored. The store
+// must have been migrated to SchemaVersion.
+func (s *DBStore) Restore(ctx context.Context, read func(restore func(table string, row []byte) error) error) error {
+	// the rows can only be read once, so unlike other writes this one isn't
+	// retried
+	err := inTransaction(s.DB.WithContext(ctx), allNamespaces, func(tx *gorm.DB) error {
+		for _, table := range backupTables {
+			var count int64
+			if err := tx.Table(table.name).Count(&count).Error; err != nil {
+				return err
+			}
+
+			if count > 0 {
+				return fmt.Errorf("%w: %s has %d rows", ErrRestoreNotEmpty, table.name, count)
+			}
+		}
+
+		next := 0
+		var batch [][]byte
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+
+			err := backupTables[next].restore(tx, batch)
+			batch = batch[:0]
+
+			return err
+		}
+
+		err := read(func(table string, row []byte) error {
+			for backupTables[next].name != table {
+				if err := flush(); err != nil {
+					return err
+				}
+
+				if next++; next == len(backupTables) {
+					return fmt.Errorf("unexpected table %q", table)
+				}
+			}
+
+			batch = append(batch, row)
+			if len(batch) < restoreBatchSize {
+				return nil
+			}
+
+			return flush()
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+
+		return resetSequences(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.cache != nil {
+		s.cache.purge(ctx)
+	}
+
+	return nil
+}
+
+// resetSequences moves the Postgres id sequences past the restored rows.
+// SQLite derives the next id from the table itself.
+func resetSequences(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	for _, table := range backupTables {
+		if !table.serial {
+			continue
+		}
+
+		err := tx.Exec(fmt.Sprintf(
+			"SELECT setval(seq::regclass, (SELECT COALESCE(MAX(id), 0) + 1 FROM %s), false) FROM pg_get_serial_sequence(?, 'id') AS seq WHERE seq IS NOT NULL",
+			table.name,
+		), table.name).Error
+		if err != nil {
+			return fmt.Errorf("error resetting %s id sequence: %w", table.name, err)
+		}
+	}
+
+	return nil
+}
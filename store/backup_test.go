@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type backupRow struct {
+	table string
+	row   string
+}
+
+func dump(t *testing.T, dbStore *DBStore) []backupRow {
+	t.Helper()
+
+	var rows []backupRow
+	err := dbStore.Backup(context.Background(), func(table string, row []byte) error {
+		rows = append(rows, backupRow{table, string(row)})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rows
+}
+
+func restore(dbStore *DBStore, rows []backupRow) error {
+	return dbStore.Restore(context.Background(), func(restore func(table string, row []byte) error) error {
+		for _, row := range rows {
+			if err := restore(row.table, []byte(row.row)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeBackupFixtures puts a row in every backed up table.
+func writeBackupFixtures(t *testing.T, dbStore *DBStore) {
+	t.Helper()
+
+	ctx := tenancy.WithNamespace(logging.WithLogger(context.Background(), zap.NewNop()), "team-a")
+
+	if err := dbStore.PutItemKind(ctx, &ItemKind{Name: "gadget", Schema: `{"type":"object"}`}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dbStore.CreateWebhook(ctx, &Webhook{URL: "https://example.com/hook", Secret: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range []*Item{
+		{Name: "Kettle", Labels: Labels{"env": "prod"}, Kind: "gadget", Attributes: Attributes{"size": 1.0}},
+		{Name: "Teapot", Description: "Brews tea"},
+	} {
+		if _, err := dbStore.CreateItem(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := dbStore.ImportItems(ctx, "items.jsonl", "v1", 0, []Item{{Model: gorm.Model{ID: 100}, Name: "Imported", Labels: Labels{"team": "a"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// soft-deleted rows are backed up too
+	if err := dbStore.DeleteItem(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dbStore.DispatchOutbox(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	targets := testStores(t)
+
+	for name, source := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			writeBackupFixtures(t, source)
+			rows := dump(t, source)
+
+			var tables []string
+			for _, row := range rows {
+				if !slices.Contains(tables, row.table) {
+					tables = append(tables, row.table)
+				}
+			}
+			if !slices.Equal(tables, BackupTables()) {
+				t.Errorf("backed up tables %v, want rows in each of %v", tables, BackupTables())
+			}
+
+			target := targets[name]
+			if err := restore(target, rows); err != nil {
+				t.Fatal(err)
+			}
+
+			if restored := dump(t, target); fmt.Sprint(restored) != fmt.Sprint(rows) {
+				t.Errorf("restored store backs up as\n%v\nwant\n%v", restored, rows)
+			}
+
+			if err := restore(target, rows); !errors.Is(err, ErrRestoreNotEmpty) {
+				t.Errorf("restoring into a store with data: got %v, want ErrRestoreNotEmpty", err)
+			}
+
+			// new rows come after the restored ones, which on Postgres takes
+			// moving the sequences
+			ctx := tenancy.WithNamespace(logging.WithLogger(context.Background(), zap.NewNop()), "team-a")
+
+			id, err := target.CreateItem(ctx, &Item{Name: "New"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id <= 100 {
+				t.Errorf("got item id %d after restoring item 100", id)
+			}
+
+			webhook := &Webhook{URL: "https://example.com/other"}
+			if err := target.CreateWebhook(ctx, webhook); err != nil {
+				t.Fatal(err)
+			}
+			if webhook.ID <= 1 {
+				t.Errorf("got webhook id %d after restoring webhook 1", webhook.ID)
+			}
+		})
+	}
+}
+
+func TestRestoreFailure(t *testing.T) {
+	sources := testStores(t)
+
+	for name, target := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			writeBackupFixtures(t, sources[name])
+			rows := dump(t, sources[name])
+
+			failure := errors.New("checksum mismatch")
+			err := target.Restore(context.Background(), func(restore func(table string, row []byte) error) error {
+				for _, row := range rows {
+					if err := restore(row.table, []byte(row.row)); err != nil {
+						return err
+					}
+				}
+				return failure
+			})
+			if !errors.Is(err, failure) {
+				t.Fatalf("got %v, want the read error", err)
+			}
+
+			if restored := dump(t, target); len(restored) != 0 {
+				t.Errorf("a failed restore left %d rows behind", len(restored))
+			}
+
+			// tables an archive lacks, such as ones added since, stay empty
+			var older []backupRow
+			for _, row := range rows {
+				if row.table != "import_checkpoints" {
+					older = append(older, row)
+				}
+			}
+
+			if err := restore(target, older); err != nil {
+				t.Fatal(err)
+			}
+
+			if restored := dump(t, target); fmt.Sprint(restored) != fmt.Sprint(older) {
+				t.Errorf("restored store backs up as\n%v\nwant\n%v", restored, older)
+			}
+
+			if err := restore(testStores(t)[name], []backupRow{{"items", "{}"}, {"unknown", "{}"}}); err == nil {
+				t.Error("restoring a row of an unknown table: got no error")
+			}
+		})
+	}
+}
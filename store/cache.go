@@ -0,0 +1,472 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCacheSize = 10000
+	defaultCacheTTL  = time.Minute
+)
+
+// CacheConfig sizes the read-through cache in front of GetItem and GetItems.
+type CacheConfig struct {
+	// Size bounds the number of cached results. Zero disables the cache.
+	Size int
+	// TTL bounds how stale a result can be when an invalidation is missed,
+	// e.g. for writes by another process to the same SQLite file.
+	TTL time.Duration
+}
+
+// CacheConfigFromEnv reads ITEM_CACHE_SIZE and ITEM_CACHE_TTL, a Go
+// duration.
+func CacheConfigFromEnv() (CacheConfig, error) {
+	config := CacheConfig{Size: defaultCacheSize, TTL: defaultCacheTTL}
+
+	if size := os.Getenv("ITEM_CACHE_SIZE"); size != "" {
+		var err error
+		if config.Size, err = strconv.Atoi(size); err != nil || config.Size < 0 {
+			return config, fmt.Errorf("invalid ITEM_CACHE_SIZE %q", size)
+		}
+	}
+
+	if ttl := os.Getenv("ITEM_CACHE_TTL"); ttl != "" {
+		var err error
+		if config.TTL, err = time.ParseDuration(ttl); err != nil || config.TTL <= 0 {
+			return config, fmt.Errorf("invalid ITEM_CACHE_TTL %q", ttl)
+		}
+	}
+
+	return config, nil
+}
+
+// cacheGroup is what a cached result depends on: a single item, or, with a
+// zero ID, every item of a namespace, as lists do.
+type cacheGroup struct {
+	namespace string
+	id        uint
+}
+
+type cacheKey struct {
+	cacheGroup
+	query string
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	value   interface{}
+	expires time.Time
+}
+
+// itemCache is a size-bounded LRU of GetItem and GetItems results with a
+// TTL. Concurrent misses for the same key share a single query.
+type itemCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[cacheKey]*list.Element
+	groups  map[cacheGroup]map[cacheKey]struct{}
+	// epoch counts invalidations. A result loaded while one happened may
+	// predate it, so it isn't cached.
+	epoch uint64
+	// position is the primary's WAL position as of the latest invalidation.
+	// Loads only read from replicas that have replayed it, or a lagging
+	// replica could put back what was just invalidated.
+	position uint64
+
+	loads singleflight.Group
+
+	hits          metric.Int64Counter
+	misses        metric.Int64Counter
+	evictions     metric.Int64Counter
+	invalidations metric.Int64Counter
+}
+
+func newItemCache(config CacheConfig) (*itemCache, error) {
+	meter := otel.Meter("github.com/skip-mev/platform-take-home/store")
+
+	c := &itemCache{
+		size:    config.Size,
+		ttl:     config.TTL,
+		lru:     list.New(),
+		entries: make(map[cacheKey]*list.Element),
+		groups:  make(map[cacheGroup]map[cacheKey]struct{}),
+	}
+
+	var err error
+
+	if c.hits, err = meter.Int64Counter("item_cache.hits", metric.WithDescription("Item cache lookups served from the cache")); err != nil {
+		return nil, err
+	}
+
+	if c.misses, err = meter.Int64Counter("item_cache.misses", metric.WithDescription("Item cache lookups that queried the database")); err != nil {
+		return nil, err
+	}
+
+	if c.evictions, err = meter.Int64Counter("item_cache.evictions", metric.WithDescription("Item cache entries dropped for space or age")); err != nil {
+		return nil, err
+	}
+
+	if c.invalidations, err = meter.Int64Counter("item_cache.invalidations", metric.WithDescription("Item cache entries dropped because an item changed")); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// get returns the cached result for key, or calls load once for all
+// concurrent callers and caches its result.
+func (c *itemCache) get(ctx context.Context, key cacheKey, load func() (interface{}, error)) (interface{}, error) {
+	lookup := attribute.String("lookup", "item")
+	if key.id == 0 {
+		lookup = attribute.String("lookup", "list")
+	}
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+
+		if time.Now().Before(entry.expires) {
+			c.lru.MoveToFront(element)
+			c.mu.Unlock()
+			c.hits.Add(ctx, 1, metric.WithAttributes(lookup))
+
+			return entry.value, nil
+		}
+
+		c.remove(element)
+		c.evictions.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "expired")))
+	}
+	c.mu.Unlock()
+
+	c.misses.Add(ctx, 1, metric.WithAttributes(lookup))
+
+	value, err, _ := c.loads.Do(fmt.Sprintf("%s/%d/%s", key.namespace, key.id, key.query), func() (interface{}, error) {
+		c.mu.Lock()
+		epoch := c.epoch
+		c.mu.Unlock()
+
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		c.put(ctx, key, value, epoch)
+
+		return value, nil
+	})
+
+	return value, err
+}
+
+func (c *itemCache) put(ctx context.Context, key cacheKey, value interface{}, epoch uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch != epoch {
+		return
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, value: value, expires: time.Now().Add(c.ttl)})
+
+	if c.groups[key.cacheGroup] == nil {
+		c.groups[key.cacheGroup] = make(map[cacheKey]struct{})
+	}
+	c.groups[key.cacheGroup][key] = struct{}{}
+
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+		c.evictions.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "size")))
+	}
+}
+
+// remove drops an entry. c.mu must be held.
+func (c *itemCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+
+	group := c.groups[entry.key.cacheGroup]
+	delete(group, entry.key)
+	if len(group) == 0 {
+		delete(c.groups, entry.key.cacheGroup)
+	}
+}
+
+// invalidate drops the cached results for the given items, and every list of
+// their namespaces. position is the WAL position of the changes, or 0 without
+// read replicas.
+func (c *itemCache) invalidate(ctx context.Context, position uint64, changed ...cacheGroup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.position = max(c.position, position)
+
+	var dropped int64
+	for _, group := range changed {
+		for _, g := range []cacheGroup{group, {namespace: group.namespace}} {
+			for key := range c.groups[g] {
+				c.remove(c.entries[key])
+				dropped++
+			}
+		}
+	}
+
+	c.invalidations.Add(ctx, dropped)
+}
+
+// readContext makes a load on ctx observe every invalidated change.
+func (c *itemCache) readContext(ctx context.Context) context.Context {
+	c.mu.Lock()
+	position := c.position
+	c.mu.Unlock()
+
+	if position == 0 {
+		return ctx
+	}
+
+	return withReadPosition(ctx, position)
+}
+
+// purge drops every cached result.
+func (c *itemCache) purge(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.invalidations.Add(ctx, int64(c.lru.Len()))
+
+	c.lru.Init()
+	c.entries = make(map[cacheKey]*list.Element)
+	c.groups = make(map[cacheGroup]map[cacheKey]struct{})
+}
+
+// EnableCache puts a read-through cache in front of GetItem and GetItems.
+// Writes through this store invalidate it immediately. On Postgres, every
+// change notification also makes it read the change log and drop whatever
+// other replicas changed, until ctx is done. It must be called before the
+// store is used.
+func (s *DBStore) EnableCache(ctx context.Context, config CacheConfig) error {
+	if config.Size <= 0 {
+		return nil
+	}
+
+	cache, err := newItemCache(config)
+	if err != nil {
+		return err
+	}
+
+	latest, err := s.latestItemEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.cache = cache
+
+	if s.dsn != "" {
+		s.startListening(ctx)
+		go s.followChanges(ctx, latest)
+	}
+
+	return nil
+}
+
+func (s *DBStore) latestItemEvent(ctx context.Context) (uint64, error) {
+	var latest uint64
+
+	err := s.readTransaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		return tx.Model(&ItemEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&latest).Error
+	})
+
+	return latest, err
+}
+
+// followChanges invalidates the cache for every change log entry after
+// version, whoever wrote it.
+func (s *DBStore) followChanges(ctx context.Context, version uint64) {
+	wake, unsubscribe := s.changes.subscribe()
+	defer unsubscribe()
+
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-poll.C:
+		}
+
+		next, err := s.invalidateChanges(ctx, version)
+		if err != nil {
+			// without the log we can't tell what changed
+			logging.FromContext(ctx).Warn("error reading item changes, purging item cache", zap.Error(err))
+			s.cache.purge(ctx)
+
+			if next, err = s.latestItemEvent(ctx); err != nil {
+				continue
+			}
+		}
+
+		version = next
+	}
+}
+
+// invalidateChanges drops the cached results for items changed after version
+// and returns the latest version it saw.
+func (s *DBStore) invalidateChanges(ctx context.Context, version uint64) (uint64, error) {
+	for {
+		var events []ItemEvent
+
+		err := s.readTransaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+			var oldest uint64
+			if err := tx.Model(&ItemEvent{}).Select("COALESCE(MIN(id), 0)").Scan(&oldest).Error; err != nil {
+				return err
+			}
+
+			if oldest > version+1 {
+				return ErrResourceVersionTooOld
+			}
+
+			return tx.Select("id", "namespace", "item_id").Where("id > ?", version).Order("id").Limit(watchBatchSize).Find(&events).Error
+		})
+		if err != nil {
+			return version, err
+		}
+
+		if len(events) == 0 {
+			return version, nil
+		}
+
+		// the primary has committed at least up to here, so once a replica
+		// replays it, it has every event just read
+		var position uint64
+		if len(s.replicas) > 0 {
+			if position, err = s.primaryPosition(ctx); err != nil {
+				return version, err
+			}
+		}
+
+		changed := make([]cacheGroup, 0, len(events))
+		for _, event := range events {
+			changed = append(changed, cacheGroup{namespace: event.Namespace, id: event.ItemID})
+		}
+
+		s.cache.invalidate(ctx, position, changed...)
+		version = events[len(events)-1].ID
+
+		if len(events) < watchBatchSize {
+			return version, nil
+		}
+	}
+}
+
+// invalidateItems drops the cached results for items written through this
+// store, so its own readers see their writes right away.
+func (s *DBStore) invalidateItems(ctx context.Context, position uint64, items ...Item) {
+	if s.cache == nil || len(items) == 0 {
+		return
+	}
+
+	changed := make([]cacheGroup, 0, len(items))
+	for _, item := range items {
+		changed = append(changed, cacheGroup{namespace: item.Namespace, id: item.ID})
+	}
+
+	s.cache.invalidate(ctx, position, changed...)
+}
+
+func (s *DBStore) cachedItem(ctx context.Context, id uint, fields []string) (*Item, error) {
+	key := cacheKey{
+		cacheGroup: cacheGroup{namespace: tenancy.FromContext(ctx), id: id},
+		query:      strings.Join(fields, ","),
+	}
+
+	value, err := s.cache.get(ctx, key, func() (interface{}, error) { return s.getItem(s.cache.readContext(ctx), id, fields) })
+	if err != nil {
+		return &Item{}, err
+	}
+
+	item := copyItem(*value.(*Item))
+
+	return &item, nil
+}
+
+func (s *DBStore) cachedItems(ctx context.Context, filter ItemFilter) ([]Item, error) {
+	query, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	key := cacheKey{cacheGroup: cacheGroup{namespace: tenancy.FromContext(ctx)}, query: string(query)}
+
+	value, err := s.cache.get(ctx, key, func() (interface{}, error) { return s.getItems(s.cache.readContext(ctx), filter) })
+	if err != nil {
+		return nil, err
+	}
+
+	cached := value.([]Item)
+	items := make([]Item, len(cached))
+	for i := range cached {
+		items[i] = copyItem(cached[i])
+	}
+
+	return items, nil
+}
+
+// copyItem lets callers modify the items they get back without corrupting the
+// cache.
+func copyItem(item Item) Item {
+	item.Labels = maps.Clone(item.Labels)
+	if item.Attributes != nil {
+		item.Attributes = copyValue(map[string]interface{}(item.Attributes)).(map[string]interface{})
+	}
+
+	return item
+}
+
+// copyValue deep-copies a decoded JSON value, whose objects and arrays would
+// otherwise be shared.
+func copyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			copied[key] = copyValue(v)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, v := range value {
+			copied[i] = copyValue(v)
+		}
+		return copied
+	}
+
+	return value
+}
@@ -0,0 +1,437 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gorm.io/gorm"
+)
+
+// cachedStore returns a migrated SQLite store with an item cache.
+func cachedStore(t *testing.T, config CacheConfig) *DBStore {
+	t.Helper()
+
+	dbStore, err := NewSQLiteBackedStore(SQLiteConfig{Path: InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbStore.Close() })
+
+	if err := dbStore.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.TTL == 0 {
+		config.TTL = time.Minute
+	}
+	if err := dbStore.EnableCache(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	return dbStore
+}
+
+func TestCachedItemsAreCopies(t *testing.T) {
+	dbStore := cachedStore(t, CacheConfig{Size: 10})
+	ctx := context.Background()
+
+	attributes := Attributes{
+		"size":  map[string]interface{}{"width": 10.0, "ports": []interface{}{"usb", "hdmi"}},
+		"specs": []interface{}{map[string]interface{}{"ram_gb": 16.0}},
+	}
+	id, err := dbStore.CreateItem(ctx, &Item{Name: "Laptop", Labels: Labels{"env": "prod"}, Attributes: attributes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mutate := func(item *Item) {
+		item.Labels["env"] = "dev"
+		item.Attributes["size"].(map[string]interface{})["width"] = 20.0
+		item.Attributes["size"].(map[string]interface{})["ports"].([]interface{})[0] = "vga"
+		item.Attributes["specs"].([]interface{})[0].(map[string]interface{})["ram_gb"] = 8.0
+	}
+
+	item, err := dbStore.GetItem(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutate(item)
+
+	items, err := dbStore.GetItems(ctx, ItemFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutate(&items[0])
+
+	for i := 0; i < 2; i++ {
+		item, err := dbStore.GetItem(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(item.Attributes, attributes) || item.Labels["env"] != "prod" {
+			t.Fatalf("cached item = %v %v, want the stored one", item.Labels, item.Attributes)
+		}
+
+		items, err := dbStore.GetItems(ctx, ItemFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(items[0].Attributes, attributes) || items[0].Labels["env"] != "prod" {
+			t.Fatalf("cached list = %v %v, want the stored items", items[0].Labels, items[0].Attributes)
+		}
+	}
+}
+
+// loader counts the loads of a cache key and returns value from each.
+type loader struct {
+	calls atomic.Int32
+	value interface{}
+}
+
+func (l *loader) load() (interface{}, error) {
+	l.calls.Add(1)
+	return l.value, nil
+}
+
+func newTestCache(t *testing.T, config CacheConfig) *itemCache {
+	t.Helper()
+
+	c, err := newItemCache(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func itemKey(namespace string, id uint) cacheKey {
+	return cacheKey{cacheGroup: cacheGroup{namespace: namespace, id: id}}
+}
+
+func listKey(namespace, query string) cacheKey {
+	return cacheKey{cacheGroup: cacheGroup{namespace: namespace}, query: query}
+}
+
+// get gets key from c, loading it with l, and reports whether it was loaded.
+func get(t *testing.T, c *itemCache, key cacheKey, l *loader) bool {
+	t.Helper()
+
+	before := l.calls.Load()
+	value, err := c.get(context.Background(), key, l.load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != l.value {
+		t.Fatalf("get(%v) = %v, want %v", key, value, l.value)
+	}
+
+	return l.calls.Load() > before
+}
+
+func TestCacheLRU(t *testing.T) {
+	c := newTestCache(t, CacheConfig{Size: 2, TTL: time.Minute})
+	a, b, d := &loader{value: "a"}, &loader{value: "b"}, &loader{value: "d"}
+
+	get(t, c, itemKey("default", 1), a)
+	get(t, c, itemKey("default", 2), b)
+
+	// a is used more recently than b, so d evicts b
+	if get(t, c, itemKey("default", 1), a) {
+		t.Error("a was loaded again")
+	}
+	get(t, c, itemKey("default", 3), d)
+
+	if !get(t, c, itemKey("default", 2), b) {
+		t.Error("b was not evicted")
+	}
+	// which evicted a in turn, as d was used since
+	if get(t, c, itemKey("default", 3), d) {
+		t.Error("d was evicted")
+	}
+	if !get(t, c, itemKey("default", 1), a) {
+		t.Error("a was not evicted")
+	}
+
+	if c.lru.Len() != 2 || len(c.entries) != 2 {
+		t.Errorf("cache holds %d entries, %d by key, want 2", c.lru.Len(), len(c.entries))
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	c := newTestCache(t, CacheConfig{Size: 10, TTL: 20 * time.Millisecond})
+	l := &loader{value: "a"}
+
+	get(t, c, itemKey("default", 1), l)
+	if get(t, c, itemKey("default", 1), l) {
+		t.Error("loaded again before the TTL")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if !get(t, c, itemKey("default", 1), l) {
+		t.Error("served after the TTL")
+	}
+	if c.lru.Len() != 1 {
+		t.Errorf("cache holds %d entries, want the reloaded one", c.lru.Len())
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	c := newTestCache(t, CacheConfig{Size: 10, TTL: time.Minute})
+	loaders := map[cacheKey]*loader{
+		itemKey("team-a", 1):          {value: "a1"},
+		itemKey("team-a", 2):          {value: "a2"},
+		listKey("team-a", "env=prod"): {value: "a-list"},
+		itemKey("team-b", 3):          {value: "b3"},
+		listKey("team-b", "env=prod"): {value: "b-list"},
+	}
+	for key, l := range loaders {
+		get(t, c, key, l)
+	}
+
+	// a change to item 1 drops it and the lists of its namespace only
+	c.invalidate(context.Background(), 0, cacheGroup{namespace: "team-a", id: 1})
+
+	for key, l := range loaders {
+		dropped := key == itemKey("team-a", 1) || key == listKey("team-a", "env=prod")
+		if loaded := get(t, c, key, l); loaded != dropped {
+			t.Errorf("%v reloaded: %v, want %v", key, loaded, dropped)
+		}
+	}
+}
+
+// TestCacheStaleFill invalidates an item while it is being loaded: the load
+// may have read the old row, so it must not be cached.
+func TestCacheStaleFill(t *testing.T) {
+	c := newTestCache(t, CacheConfig{Size: 10, TTL: time.Minute})
+	key := itemKey("default", 1)
+
+	loading, invalidated := make(chan struct{}), make(chan struct{})
+	go func() {
+		<-loading
+		c.invalidate(context.Background(), 0, key.cacheGroup)
+		close(invalidated)
+	}()
+
+	value, err := c.get(context.Background(), key, func() (interface{}, error) {
+		close(loading)
+		<-invalidated
+		return "old", nil
+	})
+	if err != nil || value != "old" {
+		t.Fatalf("get() = %v, %v", value, err)
+	}
+
+	if !get(t, c, key, &loader{value: "new"}) {
+		t.Error("the load that raced the invalidation was cached")
+	}
+}
+
+func TestCacheSharesLoads(t *testing.T) {
+	c := newTestCache(t, CacheConfig{Size: 10, TTL: time.Minute})
+	key := itemKey("default", 1)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func() (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "a", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := c.get(context.Background(), key, load); err != nil || value != "a" {
+				t.Errorf("get() = %v, %v", value, err)
+			}
+		}()
+	}
+
+	// let the goroutines pile up on the load
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loaded %d times, want once", n)
+	}
+}
+
+func TestCacheErrorsAreNotCached(t *testing.T) {
+	c := newTestCache(t, CacheConfig{Size: 10, TTL: time.Minute})
+	key := itemKey("default", 1)
+
+	failed := errors.New("failed")
+	if _, err := c.get(context.Background(), key, func() (interface{}, error) { return nil, failed }); !errors.Is(err, failed) {
+		t.Fatalf("get() error = %v, want %v", err, failed)
+	}
+
+	if !get(t, c, key, &loader{value: "a"}) {
+		t.Error("the failed load was cached")
+	}
+}
+
+func TestCacheMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	c := newTestCache(t, CacheConfig{Size: 1, TTL: time.Minute})
+	a, b := &loader{value: "a"}, &loader{value: "b"}
+
+	get(t, c, itemKey("default", 1), a)                                            // miss
+	get(t, c, itemKey("default", 1), a)                                            // hit
+	get(t, c, listKey("default", ""), b)                                           // miss, evicts a
+	c.invalidate(context.Background(), 0, cacheGroup{namespace: "default", id: 2}) // drops the list
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	sums := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					sums[m.Name] += point.Value
+				}
+			}
+		}
+	}
+
+	want := map[string]int64{"item_cache.hits": 1, "item_cache.misses": 2, "item_cache.evictions": 1, "item_cache.invalidations": 1}
+	if !reflect.DeepEqual(sums, want) {
+		t.Errorf("metrics = %v, want %v", sums, want)
+	}
+}
+
+func TestCacheNamespaces(t *testing.T) {
+	dbStore := cachedStore(t, CacheConfig{Size: 10})
+	ctxA := tenancy.WithNamespace(context.Background(), "team-a")
+	ctxB := tenancy.WithNamespace(context.Background(), "team-b")
+
+	id, err := dbStore.CreateItem(ctxA, &Item{Name: "Kettle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := dbStore.GetItem(ctxA, id); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dbStore.GetItem(ctxB, id); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetItem() in another namespace: error %v, want not found", err)
+		}
+
+		items, err := dbStore.GetItems(ctxA, ItemFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertItemIDs(t, "team-a", items, id)
+
+		if items, err = dbStore.GetItems(ctxB, ItemFilter{}); err != nil {
+			t.Fatal(err)
+		}
+		assertItemIDs(t, "team-b", items)
+	}
+
+	// a write in team-b leaves the lists of team-a cached, and shows up in
+	// its own
+	other, err := dbStore.CreateItem(ctxB, &Item{Name: "Toaster"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := dbStore.GetItems(ctxB, ItemFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertItemIDs(t, "team-b", items, other)
+
+	if len(dbStore.cache.groups[cacheGroup{namespace: "team-a"}]) != 1 {
+		t.Error("a write in team-b dropped the lists of team-a")
+	}
+}
+
+// TestInvalidateChanges writes through another store, as another replica
+// would, and has the change log invalidate the cache.
+func TestInvalidateChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.db")
+	ctx := context.Background()
+
+	open := func() *DBStore {
+		dbStore, err := NewSQLiteBackedStore(SQLiteConfig{Path: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { dbStore.Close() })
+
+		if err := dbStore.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		return dbStore
+	}
+
+	cached, other := open(), open()
+	if err := cached.EnableCache(ctx, CacheConfig{Size: 10, TTL: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := cached.CreateItem(ctx, &Item{Name: "Kettle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := cached.latestItemEvent(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cached.GetItem(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := other.UpdateItem(ctx, &Item{Model: gorm.Model{ID: id}, Name: "Toaster"}, FieldName); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := cached.GetItem(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Name != "Kettle" {
+		t.Fatalf("name = %q, want the cached Kettle before invalidating", item.Name)
+	}
+
+	next, err := cached.invalidateChanges(ctx, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != version+1 {
+		t.Errorf("invalidateChanges() = %d, want %d", next, version+1)
+	}
+
+	if item, err = cached.GetItem(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if item.Name != "Toaster" {
+		t.Errorf("name = %q after invalidating, want Toaster", item.Name)
+	}
+}
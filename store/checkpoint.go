@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCheckpointMoved is returned by ImportItems when the checkpoint of the
+// source is no longer where the import resumed from, because another run of
+// the same import committed rows in the meantime.
+var ErrCheckpointMoved = errors.New("import checkpoint moved")
+
+// ImportCheckpoint records how many rows of a source an import has committed
+// to a namespace. It is written in the same transaction as the rows, so an
+// import resumed from it neither skips nor repeats one, even for items
+// without an ID. Fingerprint identifies the content of the source, so that a
+// different file imported under the same name starts over rather than
+// skipping rows it never committed.
+type ImportCheckpoint struct {
+	Namespace   string `gorm:"primaryKey;size:63;default:'default'"`
+	Source      string `gorm:"primaryKey"`
+	Fingerprint string
+	Committed   uint64
+	UpdatedAt   time.Time
+}
+
+// ImportCheckpoint returns how many rows of source have been imported into
+// the namespace on ctx, zero if there is no checkpoint or it was written for
+// a source with another fingerprint. Checkpoints written before fingerprints
+// were recorded match any.
+func (s *DBStore) ImportCheckpoint(ctx context.Context, source, fingerprint string) (uint64, error) {
+	var checkpoint ImportCheckpoint
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		return tx.Where("namespace = ? AND source = ?", namespace, source).Limit(1).Find(&checkpoint).Error
+	})
+
+	if checkpoint.Fingerprint != "" && checkpoint.Fingerprint != fingerprint {
+		return 0, err
+	}
+
+	return checkpoint.Committed, err
+}
+
+// ImportItems upserts items like UpsertItems, as the rows of source that
+// follow the first after, and moves the checkpoint of source past them in the
+// same transaction. It returns ErrCheckpointMoved if the checkpoint isn't at
+// after. Starting at zero replaces a checkpoint of another fingerprint.
+func (s *DBStore) ImportItems(ctx context.Context, source, fingerprint string, after uint64, items []Item) error {
+	return s.upsertItems(ctx, items, func(tx *gorm.DB, namespace string) error {
+		committed := after + uint64(len(items))
+		now := time.Now()
+
+		var result *gorm.DB
+		if after == 0 {
+			result = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "namespace"}, {Name: "source"}},
+				DoUpdates: clause.AssignmentColumns([]string{"fingerprint", "committed", "updated_at"}),
+				Where: clause.Where{Exprs: []clause.Expression{
+					clause.Expr{SQL: "import_checkpoints.fingerprint <> ''"},
+					clause.Expr{SQL: "import_checkpoints.fingerprint <> ?", Vars: []any{fingerprint}},
+				}},
+			}).Create(&ImportCheckpoint{Namespace: namespace, Source: source, Fingerprint: fingerprint, Committed: committed, UpdatedAt: now})
+		} else {
+			result = tx.Model(&ImportCheckpoint{}).
+				Where("namespace = ? AND source = ? AND committed = ?", namespace, source, after).
+				Where("fingerprint IN ?", []string{"", fingerprint}).
+				Updates(map[string]any{"fingerprint": fingerprint, "committed": committed, "updated_at": now})
+		}
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: rows of %q after %d were imported concurrently", ErrCheckpointMoved, source, after)
+		}
+
+		return nil
+	})
+}
+
+// DeleteImportCheckpoint forgets the checkpoint of source in the namespace on
+// ctx, once its import is complete.
+func (s *DBStore) DeleteImportCheckpoint(ctx context.Context, source string) error {
+	return s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		return tx.Where("namespace = ? AND source = ?", namespace, source).Delete(&ImportCheckpoint{}).Error
+	})
+}
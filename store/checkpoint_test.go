@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/tenancy"
+)
+
+// TestImportCheckpoint resumes an import of items without IDs and checks that
+// no row is imported twice, even when a run repeats a batch that an earlier
+// one committed.
+func TestImportCheckpoint(t *testing.T) {
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := tenancy.WithNamespace(context.Background(), "team-a")
+			rows := []Item{{Name: "one"}, {Name: "two"}, {Name: "three"}, {Name: "four"}}
+
+			assertCheckpoint := func(want uint64) {
+				t.Helper()
+
+				got, err := dbStore.ImportCheckpoint(ctx, "items.jsonl", "v1")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("got checkpoint %d, want %d", got, want)
+				}
+			}
+
+			assertCheckpoint(0)
+
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v1", 0, rows[:2]); err != nil {
+				t.Fatal(err)
+			}
+			assertCheckpoint(2)
+
+			// a run that didn't see the checkpoint move repeats the batch
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v1", 0, rows[:2]); !errors.Is(err, ErrCheckpointMoved) {
+				t.Errorf("repeated first batch: got %v, want ErrCheckpointMoved", err)
+			}
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v1", 1, rows[1:3]); !errors.Is(err, ErrCheckpointMoved) {
+				t.Errorf("overlapping batch: got %v, want ErrCheckpointMoved", err)
+			}
+
+			// the checkpoints of other sources and namespaces are separate
+			other := tenancy.WithNamespace(context.Background(), "team-b")
+			if got, err := dbStore.ImportCheckpoint(other, "items.jsonl", "v1"); err != nil || got != 0 {
+				t.Errorf("checkpoint in another namespace: got %d, %v", got, err)
+			}
+			if got, err := dbStore.ImportCheckpoint(ctx, "other.jsonl", "v1"); err != nil || got != 0 {
+				t.Errorf("checkpoint of another source: got %d, %v", got, err)
+			}
+
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v1", 2, rows[2:]); err != nil {
+				t.Fatal(err)
+			}
+			assertCheckpoint(4)
+
+			items, err := dbStore.GetItems(ctx, ItemFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, item := range items {
+				names = append(names, item.Name)
+			}
+			if len(names) != len(rows) {
+				t.Errorf("got items %v, want one of each row", names)
+			}
+
+			if err := dbStore.DeleteImportCheckpoint(ctx, "items.jsonl"); err != nil {
+				t.Fatal(err)
+			}
+			assertCheckpoint(0)
+		})
+	}
+}
+
+// TestImportCheckpointFingerprint imports a different file under the name of
+// an interrupted one, which starts over instead of skipping its first rows.
+func TestImportCheckpointFingerprint(t *testing.T) {
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := tenancy.WithNamespace(context.Background(), "team-a")
+			rows := []Item{{Name: "one"}, {Name: "two"}}
+
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v1", 0, rows[:1]); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, err := dbStore.ImportCheckpoint(ctx, "items.jsonl", "v1"); err != nil || got != 1 {
+				t.Errorf("checkpoint of the same file: got %d, %v, want 1", got, err)
+			}
+			if got, err := dbStore.ImportCheckpoint(ctx, "items.jsonl", "v2"); err != nil || got != 0 {
+				t.Errorf("checkpoint of another file: got %d, %v, want 0", got, err)
+			}
+
+			// the other file can't continue the checkpoint, but replaces it
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v2", 1, rows[1:]); !errors.Is(err, ErrCheckpointMoved) {
+				t.Errorf("continuing another file's checkpoint: got %v, want ErrCheckpointMoved", err)
+			}
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v2", 0, rows); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := dbStore.ImportCheckpoint(ctx, "items.jsonl", "v2"); err != nil || got != 2 {
+				t.Errorf("replaced checkpoint: got %d, %v, want 2", got, err)
+			}
+
+			// a concurrent run of the same file still can't start over
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v2", 0, rows); !errors.Is(err, ErrCheckpointMoved) {
+				t.Errorf("repeated first batch: got %v, want ErrCheckpointMoved", err)
+			}
+
+			// checkpoints recorded before fingerprints resume any file, and
+			// take on the fingerprint of the first batch that continues them
+			err := dbStore.DB.Model(&ImportCheckpoint{}).Where("source = ?", "items.jsonl").Update("fingerprint", "").Error
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := dbStore.ImportCheckpoint(ctx, "items.jsonl", "v3"); err != nil || got != 2 {
+				t.Errorf("checkpoint without a fingerprint: got %d, %v, want 2", got, err)
+			}
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v3", 0, rows); !errors.Is(err, ErrCheckpointMoved) {
+				t.Errorf("restarting a checkpoint without a fingerprint: got %v, want ErrCheckpointMoved", err)
+			}
+			if err := dbStore.ImportItems(ctx, "items.jsonl", "v3", 2, []Item{{Name: "three"}}); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := dbStore.ImportCheckpoint(ctx, "items.jsonl", "v1"); err != nil || got != 0 {
+				t.Errorf("checkpoint continued by another file: got %d, %v, want 0", got, err)
+			}
+
+			items, err := dbStore.GetItems(ctx, ItemFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != 4 {
+				t.Errorf("got %d items, want the first file's row and the second's three", len(items))
+			}
+		})
+	}
+}
@@ -1,32 +1,153 @@
 package store
 
 import (
-	"gorm.io/driver/postgres"
-	"gorm.io/driver/sqlite"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
 	"gorm.io/gorm"
 )
 
 type DBStore struct {
 	*gorm.DB
+
+	// dsn is the Postgres connection string used to LISTEN for item changes
+	// committed by other processes. It is empty for SQLite.
+	dsn     string
+	changes *broker
+	// listening starts the listener once; stopListening stops it, and
+	// listener is done when it has.
+	listening     sync.Once
+	stopListening context.CancelFunc
+	listener      sync.WaitGroup
+
+	// searchEnabled is set by Migrate once the SQLite FTS5 index exists;
+	// until then SearchItems scans the items instead.
+	searchEnabled bool
+
+	// cache is set by EnableCache.
+	cache *itemCache
+
+	// replicas serve GetItem and GetItems while they are healthy; see
+	// MonitorReplicas.
+	replicas    []*replica
+	nextReplica atomic.Uint64
+
+	breaker breaker
+
+	// readDB serves read-only transactions alongside the single SQLite
+	// writer. It is nil when reads go to DB.
+	readDB *gorm.DB
+
+	// locks are the WithLock locks on SQLite, by name.
+	locksMu sync.Mutex
+	locks   map[string]*sync.Mutex
 }
 
-func NewSQLiteBackedStore() (*DBStore, error) {
-	db, err := gorm.Open(sqlite.Open("tables.db"))
+// NewSQLiteBackedStore opens the SQLite database described by config; see
+// openSQLite for how it is accessed.
+func NewSQLiteBackedStore(config SQLiteConfig) (*DBStore, error) {
+	db, reader, err := openSQLite(config)
 
 	if err != nil {
 		return nil, err
 	}
-	return &DBStore{db}, nil
+	return &DBStore{DB: db, readDB: reader, changes: newBroker()}, nil
 }
 
-func NewPostgresBackedStore(dsn string) (*DBStore, error) {
-	db, err := gorm.Open(postgres.Open(dsn))
+// NewPostgresBackedStore writes to the primary at dsn and reads items from
+// the replicas at replicaDSNs, if any, once MonitorReplicas finds them healthy.
+// It waits up to config.StartupTimeout for the primary to come up.
+func NewPostgresBackedStore(ctx context.Context, config DBConfig, dsn string, replicaDSNs ...string) (*DBStore, error) {
+	db, err := openPostgresWithRetry(ctx, config, dsn)
 	if err != nil {
 		return nil, err
 	}
-	return &DBStore{db}, nil
+
+	replicas := make([]*replica, 0, len(replicaDSNs))
+	for _, replicaDSN := range replicaDSNs {
+		r, err := openReplica(config, replicaDSN)
+		if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, r)
+	}
+
+	return &DBStore{DB: db, dsn: dsn, changes: newBroker(), replicas: replicas}, nil
+}
+
+// NewStoreFromEnv opens the Postgres store when POSTGRES_DSN is set and falls
+// back to the SQLite database at SQLITE_PATH otherwise. POSTGRES_REPLICA_DSNS is a
+// comma separated list of read replicas of POSTGRES_DSN. See DBConfigFromEnv
+// for tuning the connections.
+func NewStoreFromEnv(ctx context.Context) (*DBStore, error) {
+	if dsn := os.Getenv("POSTGRES_DSN"); dsn != "" {
+		config, err := DBConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+
+		var replicaDSNs []string
+		for _, replicaDSN := range strings.Split(os.Getenv("POSTGRES_REPLICA_DSNS"), ",") {
+			if replicaDSN = strings.TrimSpace(replicaDSN); replicaDSN != "" {
+				replicaDSNs = append(replicaDSNs, replicaDSN)
+			}
+		}
+
+		return NewPostgresBackedStore(ctx, config, dsn, replicaDSNs...)
+	}
+
+	config, err := SQLiteConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSQLiteBackedStore(config)
+}
+
+// Close closes the connections to the primary, the replicas and the SQLite
+// readers.
+func (s *DBStore) Close() error {
+	// no listener starts after this, and one that has stops before its
+	// connection's database closes
+	s.listening.Do(func() {})
+	if s.stopListening != nil {
+		s.stopListening()
+		s.listener.Wait()
+	}
+
+	dbs := []*gorm.DB{s.DB}
+	if s.readDB != nil {
+		dbs = append(dbs, s.readDB)
+	}
+	for _, r := range s.replicas {
+		dbs = append(dbs, r.db)
+	}
+
+	var errs []error
+	for _, db := range dbs {
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
 }
 
 func (s *DBStore) Migrate() error {
-	return s.DB.AutoMigrate(&Item{})
+	err := s.DB.AutoMigrate(&Item{}, &ItemLabel{}, &ItemKind{}, &ItemEvent{}, &OutboxMessage{}, &Webhook{}, &WebhookDelivery{}, &ImportCheckpoint{})
+	if err != nil {
+		return err
+	}
+
+	if err := s.migrateNamespaces(); err != nil {
+		return err
+	}
+
+	return s.migrateSearch()
 }
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventType string
+
+const (
+	EventAdded    EventType = "ADDED"
+	EventModified EventType = "MODIFIED"
+	EventDeleted  EventType = "DELETED"
+)
+
+// ItemEvent is an entry in the item change log. Its ID is the resource version
+// watchers resume from, and Object holds the item as it was after the change
+// (or just before it, for deletions).
+type ItemEvent struct {
+	ID        uint64    `gorm:"primaryKey"`
+	Namespace string    `gorm:"size:63;not null;default:'default';index"`
+	Type      EventType `gorm:"size:16"`
+	ItemID    uint      `gorm:"index"`
+	Object    Item      `gorm:"serializer:json"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+const (
+	// changesChannel is the Postgres NOTIFY channel used to wake up watchers.
+	changesChannel = "item_changes"
+
+	// changeLogLockID is the Postgres advisory lock that serializes change log
+	// writers, so events commit in resource version order and a watcher never
+	// skips an event whose ID was allocated before one it has already seen.
+	changeLogLockID = 0x6974656d
+)
+
+// recordEvents appends change log and webhook outbox entries inside tx. On
+// Postgres it also queues a NOTIFY, which is only delivered if tx commits.
+func recordEvents(tx *gorm.DB, eventType EventType, items ...Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", changeLogLockID).Error; err != nil {
+			return err
+		}
+	}
+
+	events := make([]ItemEvent, 0, len(items))
+	for _, item := range items {
+		events = append(events, ItemEvent{Namespace: item.Namespace, Type: eventType, ItemID: item.ID, Object: item})
+	}
+
+	if err := tx.Create(&events).Error; err != nil {
+		return err
+	}
+
+	outbox := make([]OutboxMessage, 0, len(events))
+	for _, event := range events {
+		outbox = append(outbox, OutboxMessage{EventID: event.ID, Namespace: event.Namespace, Type: event.Type, ItemID: event.ItemID, Object: event.Object})
+	}
+
+	if err := tx.Create(&outbox).Error; err != nil {
+		return err
+	}
+
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Exec("SELECT pg_notify(?, '')", changesChannel).Error
+	}
+
+	return nil
+}
+
+// afterCommit issues a consistency token for the write, drops the changed
+// items from the cache and wakes up local watchers. Postgres watchers are
+// woken by the NOTIFY queued in recordEvents instead, which also reaches other
+// replicas.
+func (s *DBStore) afterCommit(ctx context.Context, items ...Item) {
+	position := s.issueConsistencyToken(ctx)
+	s.invalidateItems(ctx, position, items...)
+
+	if s.dsn == "" {
+		s.changes.notify()
+	}
+}
+
+// CompactItemEvents deletes change log entries of every namespace created
+// before the given time. The newest entry is always kept so watchers can tell
+// a compacted resource version from one that is merely up to date.
+func (s *DBStore) CompactItemEvents(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+
+	err := s.transaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		latest := tx.Model(&ItemEvent{}).Select("MAX(id)")
+
+		res := tx.Where("created_at < ? AND id < (?)", before, latest).Delete(&ItemEvent{})
+		deleted = res.RowsAffected
+
+		return res.Error
+	})
+
+	return deleted, err
+}
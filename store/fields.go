@@ -0,0 +1,67 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Item fields that reads can be restricted to and updates limited to, named
+// like the API fields they back. The ID is always read.
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldLabels      = "labels"
+	FieldKind        = "kind"
+	FieldAttributes  = "attributes"
+	FieldNamespace   = "namespace"
+)
+
+// UpdatableFields are the fields UpdateItem overwrites when it isn't given
+// any.
+var UpdatableFields = []string{FieldName, FieldDescription, FieldLabels, FieldKind, FieldAttributes}
+
+var readableFields = append([]string{FieldNamespace}, UpdatableFields...)
+
+// itemColumns returns the qualified items columns that hold fields, and
+// whether labels, which live in item_labels, were asked for. No fields means
+// every column.
+func itemColumns(fields []string) (columns []string, labels bool, err error) {
+	if len(fields) == 0 {
+		return []string{"items.*"}, true, nil
+	}
+
+	columns = []string{"items.id"}
+
+	for _, field := range fields {
+		switch {
+		case field == FieldLabels:
+			labels = true
+		case slices.Contains(readableFields, field):
+			columns = append(columns, "items."+field)
+		default:
+			return nil, false, fmt.Errorf("unknown item field %q", field)
+		}
+	}
+
+	return columns, labels, nil
+}
+
+// CopyFields overwrites the given fields of i with those of from.
+func (i *Item) CopyFields(from *Item, fields ...string) {
+	for _, field := range fields {
+		switch field {
+		case FieldName:
+			i.Name = from.Name
+		case FieldDescription:
+			i.Description = from.Description
+		case FieldLabels:
+			i.Labels = from.Labels
+		case FieldKind:
+			i.Kind = from.Kind
+		case FieldAttributes:
+			i.Attributes = from.Attributes
+		case FieldNamespace:
+			i.Namespace = from.Namespace
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// labelBatchSize bounds the item IDs bound in one statement, well below
+// the variable limits of SQLite (32766) and Postgres (65535).
+const labelBatchSize = 500
+
+// Labels are arbitrary key/value pairs used to group and select items.
+type Labels map[string]string
+
+// ItemLabel is a row of the normalized item_labels table. The (key, value)
+// index serves selector lookups; the primary key serves loading an item's labels.
+type ItemLabel struct {
+	ItemID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Key    string `gorm:"primaryKey;size:317;index:idx_item_labels_key_value,priority:1"`
+	Value  string `gorm:"size:63;index:idx_item_labels_key_value,priority:2"`
+}
+
+var (
+	labelNamePattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.-]{0,61}[A-Za-z0-9])?$`)
+	labelPrefixPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// ValidateLabelKey checks a key against the Kubernetes label key syntax: an
+// optional DNS subdomain prefix and a slash, followed by a name of at most 63
+// alphanumerics, '-', '_' or '.', starting and ending with an alphanumeric.
+func ValidateLabelKey(key string) error {
+	name := key
+
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		if len(prefix) > 253 || !labelPrefixPattern.MatchString(prefix) {
+			return fmt.Errorf("invalid label key %q: prefix must be a DNS subdomain", key)
+		}
+		name = rest
+	}
+
+	if !labelNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid label key %q", key)
+	}
+
+	return nil
+}
+
+// ValidateLabelValue checks a value against the Kubernetes label value syntax,
+// which is the key name syntax but may also be empty.
+func ValidateLabelValue(value string) error {
+	if value != "" && !labelNamePattern.MatchString(value) {
+		return fmt.Errorf("invalid label value %q", value)
+	}
+
+	return nil
+}
+
+func (l Labels) Validate() error {
+	for key, value := range l {
+		if err := ValidateLabelKey(key); err != nil {
+			return err
+		}
+
+		if err := ValidateLabelValue(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String formats labels as a sorted, comma separated list of key=value pairs.
+func (l Labels) String() string {
+	pairs := make([]string, 0, len(l))
+	for key, value := range l {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// replaceLabels overwrites the labels of items inside tx.
+func replaceLabels(tx *gorm.DB, items ...Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(items))
+	var rows []ItemLabel
+
+	for _, item := range items {
+		ids = append(ids, item.ID)
+		for key, value := range item.Labels {
+			rows = append(rows, ItemLabel{ItemID: item.ID, Key: key, Value: value})
+		}
+	}
+
+	for batch := range slices.Chunk(ids, labelBatchSize) {
+		if err := tx.Where("item_id IN ?", batch).Delete(&ItemLabel{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return tx.CreateInBatches(&rows, labelBatchSize).Error
+}
+
+// loadLabels fills in the labels of items, querying labelBatchSize items at
+// a time.
+func loadLabels(db *gorm.DB, items ...*Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint]*Item, len(items))
+	ids := make([]uint, 0, len(items))
+
+	for _, item := range items {
+		item.Labels = nil
+		byID[item.ID] = item
+		ids = append(ids, item.ID)
+	}
+
+	for batch := range slices.Chunk(ids, labelBatchSize) {
+		var rows []ItemLabel
+		if err := db.Where("item_id IN ?", batch).Find(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			item := byID[row.ItemID]
+			if item.Labels == nil {
+				item.Labels = make(Labels)
+			}
+			item.Labels[row.Key] = row.Value
+		}
+	}
+
+	return nil
+}
+
+func itemPointers(items []Item) []*Item {
+	pointers := make([]*Item, len(items))
+	for i := range items {
+		pointers[i] = &items[i]
+	}
+
+	return pointers
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// TestGetItemsUnpaged lists more items than SQLite and Postgres can bind in
+// one statement.
+func TestGetItemsUnpaged(t *testing.T) {
+	if testing.Short() {
+		t.Skip("creates 70000 items")
+	}
+
+	const n = 70000
+
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			items := make([]Item, n)
+			for i := range items {
+				items[i] = Item{Name: fmt.Sprintf("item-%d", i), Labels: Labels{"n": fmt.Sprint(i)}}
+			}
+
+			// in batches, as imports write them
+			for batch := range slices.Chunk(items, 1000) {
+				if err := dbStore.UpsertItems(ctx, batch); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := dbStore.GetItems(ctx, ItemFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != n {
+				t.Fatalf("got %d items, want %d", len(got), n)
+			}
+
+			for _, item := range got {
+				if want := item.Name[len("item-"):]; item.Labels["n"] != want {
+					t.Fatalf("item %d has labels %v, want n=%s", item.ID, item.Labels, want)
+				}
+			}
+		})
+	}
+}
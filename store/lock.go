@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync"
+)
+
+// WithLock runs fn holding the lock called name, so that no other WithLock
+// of the same name runs at the same time. On Postgres the lock is a session
+// advisory lock, held by a connection of its own while fn runs, which
+// serializes every process sharing the database. On SQLite it only
+// serializes the callers in this process.
+func (s *DBStore) WithLock(ctx context.Context, name string, fn func() error) error {
+	if s.DB.Dialector.Name() != "postgres" {
+		lock := s.localLock(name)
+		lock.Lock()
+		defer lock.Unlock()
+
+		return fn()
+	}
+
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil {
+			// the lock lives as long as the session, so end it rather than
+			// return it to the pool still locked
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	return fn()
+}
+
+func (s *DBStore) localLock(name string) *sync.Mutex {
+	s.locksMu.Lock()
+	defer s.locksMu.Unlock()
+
+	if s.locks == nil {
+		s.locks = make(map[string]*sync.Mutex)
+	}
+
+	lock, ok := s.locks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[name] = lock
+	}
+
+	return lock
+}
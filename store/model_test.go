@@ -0,0 +1,326 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"gorm.io/gorm"
+)
+
+// TestStoreModel applies random sequences of item writes to the store and
+// to a map of the items it should hold, and checks after every step that
+// the two agree: created items read back as written, every list holds
+// exactly the live items, pages add up to the whole list, and IDs are never
+// handed out twice. The cached store must agree too, so its invalidation is
+// checked along the way.
+func TestStoreModel(t *testing.T) {
+	stores := testStores(t)
+
+	cached, err := NewSQLiteBackedStore(SQLiteConfig{Path: InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cached.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cached.EnableCache(context.Background(), CacheConfig{Size: 1000, TTL: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	stores["sqlite-cached"] = cached
+
+	for name, dbStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			// IDs are unique across namespaces, so they are tracked across
+			// runs, which each act in a namespace of their own
+			seen := map[uint]bool{}
+			var runs atomic.Int64
+
+			property := func(ops []operation) bool {
+				ctx := tenancy.WithNamespace(context.Background(), fmt.Sprintf("model-%d", runs.Add(1)))
+
+				if err := checkModel(ctx, dbStore, ops, seen); err != nil {
+					t.Log(err)
+					return false
+				}
+				return true
+			}
+
+			if err := quick.Check(property, &quick.Config{MaxCount: 25}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+type opKind int
+
+const (
+	opCreate opKind = iota
+	opUpdate
+	opDelete
+)
+
+// operation is a random write. Update and delete pick their item by
+// Target, modulo the items written so far, deleted ones included.
+type operation struct {
+	Kind   opKind
+	Item   Item
+	Target int
+	// Fields to update; nil updates all of them.
+	Fields []string
+}
+
+var (
+	modelRunes  = []rune("abcxyz ÄéΩ漢字🙂-_'\"%\\")
+	modelLabels = map[string][]string{"env": {"prod", "dev"}, "team": {"a", "b"}, "tier": {"1"}}
+)
+
+func (operation) Generate(r *rand.Rand, size int) reflect.Value {
+	op := operation{Kind: opKind(r.Intn(3)), Target: r.Intn(1 << 16)}
+
+	// create more than delete, so that lists grow
+	if r.Intn(2) == 0 {
+		op.Kind = opCreate
+	}
+
+	text := func() string {
+		var b strings.Builder
+		for i := r.Intn(size + 1); i > 0; i-- {
+			b.WriteRune(modelRunes[r.Intn(len(modelRunes))])
+		}
+		return b.String()
+	}
+
+	op.Item = Item{Name: text(), Description: text(), Labels: Labels{}}
+	for key, values := range modelLabels {
+		if r.Intn(2) == 0 {
+			op.Item.Labels[key] = values[r.Intn(len(values))]
+		}
+	}
+
+	if op.Kind == opUpdate && r.Intn(2) == 0 {
+		for _, field := range []string{FieldName, FieldDescription, FieldLabels} {
+			if r.Intn(2) == 0 {
+				op.Fields = append(op.Fields, field)
+			}
+		}
+	}
+
+	return reflect.ValueOf(op)
+}
+
+func (op operation) String() string {
+	switch op.Kind {
+	case opCreate:
+		return fmt.Sprintf("create(%q, %q, %v)", op.Item.Name, op.Item.Description, op.Item.Labels)
+	case opUpdate:
+		return fmt.Sprintf("update(#%d, %q, %q, %v, fields %v)", op.Target, op.Item.Name, op.Item.Description, op.Item.Labels, op.Fields)
+	default:
+		return fmt.Sprintf("delete(#%d)", op.Target)
+	}
+}
+
+// checkModel applies ops in the namespace on ctx, comparing the store with
+// the model after each one.
+func checkModel(ctx context.Context, dbStore *DBStore, ops []operation, seen map[uint]bool) error {
+	model := map[uint]Item{}
+	var written []uint
+
+	for step, op := range ops {
+		var target uint
+		if len(written) > 0 {
+			target = written[op.Target%len(written)]
+		}
+
+		switch {
+		case op.Kind == opCreate:
+			item := op.Item
+			id, err := dbStore.CreateItem(ctx, &item)
+			if err != nil {
+				return fmt.Errorf("step %d, %v: %w", step, op, err)
+			}
+
+			if seen[id] {
+				return fmt.Errorf("step %d, %v: ID %d handed out twice", step, op, id)
+			}
+			seen[id] = true
+
+			model[id] = op.Item
+			written = append(written, id)
+
+		case target == 0:
+			continue
+
+		case op.Kind == opUpdate:
+			update := op.Item
+			update.ID = target
+
+			_, err := dbStore.UpdateItem(ctx, &update, op.Fields...)
+
+			want, live := model[target]
+			if !live {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("step %d, %v: updating a deleted item: got %v, want ErrRecordNotFound", step, op, err)
+				}
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("step %d, %v: %w", step, op, err)
+			}
+
+			fields := op.Fields
+			if fields == nil {
+				fields = []string{FieldName, FieldDescription, FieldLabels}
+			}
+			want.CopyFields(&op.Item, fields...)
+			model[target] = want
+
+		case op.Kind == opDelete:
+			err := dbStore.DeleteItem(ctx, target)
+
+			if _, live := model[target]; !live {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return fmt.Errorf("step %d, %v: deleting a deleted item: got %v, want ErrRecordNotFound", step, op, err)
+				}
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("step %d, %v: %w", step, op, err)
+			}
+
+			delete(model, target)
+		}
+
+		if err := compareWithModel(ctx, dbStore, model, written); err != nil {
+			return fmt.Errorf("after step %d, %v: %w", step, op, err)
+		}
+	}
+
+	return nil
+}
+
+func compareWithModel(ctx context.Context, dbStore *DBStore, model map[uint]Item, written []uint) error {
+	for _, id := range written {
+		item, err := dbStore.GetItem(ctx, id)
+
+		want, live := model[id]
+		if !live {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("GetItem(%d) of a deleted item: got %v, want ErrRecordNotFound", id, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("GetItem(%d): %w", id, err)
+		}
+
+		if err := compareItem(item, id, want); err != nil {
+			return fmt.Errorf("GetItem(%d): %w", id, err)
+		}
+	}
+
+	all, err := dbStore.GetItems(ctx, ItemFilter{})
+	if err != nil {
+		return fmt.Errorf("GetItems: %w", err)
+	}
+
+	if err := compareList("GetItems", all, model, func(Item) bool { return true }); err != nil {
+		return err
+	}
+
+	selector, err := ParseSelector("env=prod,team!=a")
+	if err != nil {
+		return err
+	}
+
+	selected, err := dbStore.GetItems(ctx, ItemFilter{Labels: selector})
+	if err != nil {
+		return fmt.Errorf("GetItems(env=prod,team!=a): %w", err)
+	}
+
+	matches := func(item Item) bool { return item.Labels["env"] == "prod" && item.Labels["team"] != "a" }
+	if err := compareList("GetItems(env=prod,team!=a)", selected, model, matches); err != nil {
+		return err
+	}
+
+	var paged []Item
+	for afterID := uint(0); ; {
+		page, err := dbStore.GetItems(ctx, ItemFilter{AfterID: afterID, Limit: 2})
+		if err != nil {
+			return fmt.Errorf("GetItems(after %d): %w", afterID, err)
+		}
+
+		paged = append(paged, page...)
+
+		if len(page) < 2 {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+
+	if !slices.EqualFunc(paged, all, func(a, b Item) bool { return a.ID == b.ID }) {
+		return fmt.Errorf("pages hold %v, want %v", itemIDs(paged), itemIDs(all))
+	}
+
+	return nil
+}
+
+// compareList checks that items are the model's items that match, in ID
+// order.
+func compareList(what string, items []Item, model map[uint]Item, match func(Item) bool) error {
+	var want []uint
+	for id, item := range model {
+		if match(item) {
+			want = append(want, id)
+		}
+	}
+	sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+
+	if got := itemIDs(items); !slices.Equal(got, want) {
+		return fmt.Errorf("%s: got items %v, want %v", what, got, want)
+	}
+
+	for i := range items {
+		if err := compareItem(&items[i], items[i].ID, model[items[i].ID]); err != nil {
+			return fmt.Errorf("%s: item %d: %w", what, items[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+func compareItem(item *Item, id uint, want Item) error {
+	switch {
+	case item.ID != id:
+		return fmt.Errorf("got ID %d", item.ID)
+	case item.Name != want.Name || item.Description != want.Description:
+		return fmt.Errorf("got %q, %q, want %q, %q", item.Name, item.Description, want.Name, want.Description)
+	case !maps.Equal(item.Labels, want.Labels):
+		return fmt.Errorf("got labels %v, want %v", item.Labels, want.Labels)
+	}
+
+	return nil
+}
+
+func itemIDs(items []Item) []uint {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
@@ -5,6 +5,19 @@ import "gorm.io/gorm"
 type Item struct {
 	gorm.Model
 
+	// Namespace is the tenant that owns the item. Store methods only ever
+	// see items in the namespace on their context.
+	Namespace string `gorm:"size:63;not null;default:'default';index"`
+
 	Name        string
 	Description string
+
+	// Kind selects the JSON Schema that Attributes must satisfy. Items
+	// without a kind may carry free-form attributes.
+	Kind       string `gorm:"size:63;index"`
+	Attributes Attributes
+
+	// Labels live in the item_labels table and are loaded and saved by the
+	// store methods explicitly, rather than as a gorm association.
+	Labels Labels `gorm:"-"`
 }
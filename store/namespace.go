@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"gorm.io/gorm"
+)
+
+// allNamespaces is the app.namespace setting of background workers that
+// serve every tenant, such as the webhook dispatcher. tenancy.Validate
+// rejects it, so no request can act in it.
+const allNamespaces = "*"
+
+// namespacedTables carry a namespace column and are protected by a row-level
+// security policy on Postgres. Other tables are only reachable through them.
+var namespacedTables = []string{"items", "item_events", "item_kinds", "webhooks", "import_checkpoints"}
+
+// transaction runs fn in a transaction acting in namespace. On Postgres the
+// namespace is also set as app.namespace for the row-level security policies,
+// so a query that forgets to filter by namespace still can't see other
+// tenants' rows. Transient failures are retried, so fn may run more than once.
+func (s *DBStore) transaction(ctx context.Context, namespace string, fn func(tx *gorm.DB) error) error {
+	return s.transactionOn(ctx, s.DB, namespace, fn)
+}
+
+// transactionOn is transaction on a pool other than DB, such as readDB.
+func (s *DBStore) transactionOn(ctx context.Context, db *gorm.DB, namespace string, fn func(tx *gorm.DB) error) error {
+	return s.withRetry(ctx, func(commit func()) error {
+		return inTransaction(db.WithContext(ctx), namespace, func(tx *gorm.DB) error {
+			if err := fn(tx); err != nil {
+				return err
+			}
+
+			commit()
+
+			return nil
+		})
+	})
+}
+
+// readTransaction is transaction for read-only work that must see the
+// primary's latest commits, such as streams. On SQLite it runs on the readers
+// rather than queueing for the single writer connection.
+func (s *DBStore) readTransaction(ctx context.Context, namespace string, fn func(tx *gorm.DB) error) error {
+	db := s.DB
+	if s.readDB != nil {
+		db = s.readDB
+	}
+
+	return s.transactionOn(ctx, db, namespace, fn)
+}
+
+// inTransaction is transaction on any connection, such as a read replica's.
+func inTransaction(db *gorm.DB, namespace string, fn func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT set_config('app.namespace', ?, true)", namespace).Error; err != nil {
+				return err
+			}
+		}
+
+		return fn(tx)
+	})
+}
+
+// tenant runs fn in a transaction acting in the namespace on ctx and passes
+// that namespace along, so fn can filter by it explicitly.
+func (s *DBStore) tenant(ctx context.Context, fn func(tx *gorm.DB, namespace string) error) error {
+	namespace := tenancy.FromContext(ctx)
+
+	return s.transaction(ctx, namespace, func(tx *gorm.DB) error {
+		return fn(tx, namespace)
+	})
+}
+
+// migrateNamespaces enables row-level security on the namespaced tables.
+// FORCE applies the policies to the table owner as well; superusers and
+// roles with BYPASSRLS still bypass them, so the server should not connect
+// as one.
+func (s *DBStore) migrateNamespaces() error {
+	if s.DB.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	const visible = `namespace = current_setting('app.namespace', true) OR current_setting('app.namespace', true) = '*'`
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		for _, table := range namespacedTables {
+			statements := []string{
+				fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY", table),
+				fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY", table),
+				fmt.Sprintf("DROP POLICY IF EXISTS namespace_isolation ON %s", table),
+				fmt.Sprintf("CREATE POLICY namespace_isolation ON %s USING (%s) WITH CHECK (%s)", table, visible, visible),
+			}
+
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,273 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"gorm.io/gorm"
+)
+
+// TestNamespaceIsolation writes the same fixtures into two namespaces and
+// checks that no store method acting in one of them can read or change the
+// other's rows.
+func TestNamespaceIsolation(t *testing.T) {
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctxA := tenancy.WithNamespace(context.Background(), "team-a")
+			ctxB := tenancy.WithNamespace(context.Background(), "team-b")
+
+			itemA := Item{Name: "Shared name", Description: "kettle", Kind: "gadget", Attributes: Attributes{"size": 1.0}, Labels: Labels{"env": "prod"}}
+			itemB := Item{Name: "Shared name", Description: "kettle", Kind: "gadget", Attributes: Attributes{"size": 1.0}, Labels: Labels{"env": "prod"}}
+
+			idA, err := dbStore.CreateItem(ctxA, &itemA)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			idB, err := dbStore.CreateItem(ctxB, &itemB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := dbStore.GetItem(ctxB, idA); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Errorf("GetItem across namespaces: got %v, want ErrRecordNotFound", err)
+			}
+
+			item, err := dbStore.GetItem(ctxB, idB)
+			if err != nil || item.Namespace != "team-b" {
+				t.Errorf("GetItem in own namespace: got %+v, %v", item, err)
+			}
+
+			selector, err := ParseSelector("env=prod")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			attributes, err := ParseAttributeFilters("size=1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, filter := range []ItemFilter{{}, {Labels: selector}, {Attributes: attributes}} {
+				items, err := dbStore.GetItems(ctxB, filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assertItemIDs(t, fmt.Sprintf("GetItems(%+v)", filter), items, idB)
+			}
+
+			var streamed []Item
+			err = dbStore.StreamItems(ctxB, 1, func(item *Item) error {
+				streamed = append(streamed, *item)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertItemIDs(t, "StreamItems", streamed, idB)
+
+			results, err := dbStore.SearchItems(ctxB, "kettle", 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := make([]Item, 0, len(results))
+			for _, result := range results {
+				found = append(found, result.Item)
+			}
+			assertItemIDs(t, "SearchItems", found, idB)
+
+			// writes must not reach across namespaces either
+			if _, err := dbStore.UpdateItem(ctxB, &Item{Model: gorm.Model{ID: idA}, Name: "hijacked"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Errorf("UpdateItem across namespaces: got %v, want ErrRecordNotFound", err)
+			}
+
+			if err := dbStore.DeleteItem(ctxB, idA); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Errorf("DeleteItem across namespaces: got %v, want ErrRecordNotFound", err)
+			}
+
+			// Postgres rejects the overwrite through the row-level security
+			// policy rather than with ErrNamespaceConflict
+			if err := dbStore.UpsertItems(ctxB, []Item{{Model: gorm.Model{ID: idA}, Name: "hijacked"}}); err == nil {
+				t.Error("UpsertItems across namespaces: got no error")
+			}
+
+			item, err = dbStore.GetItem(ctxA, idA)
+			if err != nil || item.Name != "Shared name" {
+				t.Errorf("item in team-a changed: got %+v, %v", item, err)
+			}
+
+			// the change log only replays events of the watcher's namespace
+			watchCtx, cancel := context.WithTimeout(ctxB, 500*time.Millisecond)
+			defer cancel()
+
+			var events []ItemEvent
+			err = dbStore.WatchItems(watchCtx, 1, func(event *ItemEvent) error {
+				events = append(events, *event)
+				return nil
+			})
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatal(err)
+			}
+
+			for _, event := range events {
+				if event.Namespace != "team-b" || event.ItemID != idB {
+					t.Errorf("WatchItems: got event %+v from another namespace", event)
+				}
+			}
+
+			if err := dbStore.PutItemKind(ctxA, &ItemKind{Name: "gadget", Schema: `{"type":"object"}`}); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := dbStore.GetItemKind(ctxB, "gadget"); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Errorf("GetItemKind across namespaces: got %v, want ErrRecordNotFound", err)
+			}
+
+			if kinds, err := dbStore.GetItemKinds(ctxB); err != nil || len(kinds) != 0 {
+				t.Errorf("GetItemKinds across namespaces: got %+v, %v", kinds, err)
+			}
+
+			// a namespace can register a kind name another namespace already uses
+			if err := dbStore.PutItemKind(ctxB, &ItemKind{Name: "gadget", Schema: `{"type":"object","required":["size"]}`}); err != nil {
+				t.Fatal(err)
+			}
+
+			if kind, err := dbStore.GetItemKind(ctxA, "gadget"); err != nil || kind.Schema != `{"type":"object"}` {
+				t.Errorf("kind in team-a changed: got %+v, %v", kind, err)
+			}
+
+			// webhooks only see events dispatched after they were created
+			if _, err := dbStore.DispatchOutbox(context.Background(), 100); err != nil {
+				t.Fatal(err)
+			}
+
+			webhookA := Webhook{URL: "https://a.example.com"}
+			if err := dbStore.CreateWebhook(ctxA, &webhookA); err != nil {
+				t.Fatal(err)
+			}
+
+			if webhooks, err := dbStore.GetWebhooks(ctxB); err != nil || len(webhooks) != 0 {
+				t.Errorf("GetWebhooks across namespaces: got %+v, %v", webhooks, err)
+			}
+
+			if err := dbStore.DeleteWebhook(ctxB, webhookA.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Errorf("DeleteWebhook across namespaces: got %v, want ErrRecordNotFound", err)
+			}
+
+			if _, err := dbStore.CreateItem(ctxB, &Item{Name: "after webhook"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := dbStore.CreateItem(ctxA, &Item{Name: "after webhook"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := dbStore.DispatchOutbox(context.Background(), 100); err != nil {
+				t.Fatal(err)
+			}
+
+			deliveries, err := dbStore.GetWebhookDeliveries(ctxA, webhookA.ID, "", 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(deliveries) != 1 || deliveries[0].OutboxMessage.Namespace != "team-a" {
+				t.Errorf("webhook in team-a: got deliveries %+v, want one team-a event", deliveries)
+			}
+
+			if deliveries, err := dbStore.GetWebhookDeliveries(ctxB, webhookA.ID, "", 100); err != nil || len(deliveries) != 0 {
+				t.Errorf("GetWebhookDeliveries across namespaces: got %+v, %v", deliveries, err)
+			}
+
+			if len(deliveries) > 0 {
+				if err := dbStore.RedeliverWebhookDelivery(ctxB, deliveries[0].ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+					t.Errorf("RedeliverWebhookDelivery across namespaces: got %v, want ErrRecordNotFound", err)
+				}
+			}
+		})
+	}
+}
+
+// TestRowLevelSecurity checks that the Postgres policies hide other tenants'
+// rows from queries that don't filter by namespace at all.
+func TestRowLevelSecurity(t *testing.T) {
+	dbStore, ok := testStores(t)["postgres"]
+	if !ok {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+
+	for _, namespace := range []string{"team-a", "team-a", "team-b"} {
+		if _, err := dbStore.CreateItem(tenancy.WithNamespace(ctx, namespace), &Item{Name: "item"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var schema string
+	if err := dbStore.Raw("SELECT current_schema()").Scan(&schema).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// the test may connect as a superuser, which bypasses row-level security,
+	// so query as an unprivileged role
+	role := schema + "_tenant"
+	statements := []string{
+		"CREATE ROLE " + role + " NOLOGIN",
+		"GRANT USAGE ON SCHEMA " + schema + " TO " + role,
+		"GRANT SELECT ON ALL TABLES IN SCHEMA " + schema + " TO " + role,
+	}
+
+	for _, statement := range statements {
+		if err := dbStore.Exec(statement).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		dbStore.Exec("DROP OWNED BY " + role)
+		dbStore.Exec("DROP ROLE " + role)
+	})
+
+	for namespace, want := range map[string]int64{"team-a": 2, "team-b": 1, "team-c": 0, "": 0} {
+		var count int64
+
+		err := dbStore.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SET LOCAL ROLE " + role).Error; err != nil {
+				return err
+			}
+
+			if namespace != "" {
+				if err := tx.Exec("SELECT set_config('app.namespace', ?, true)", namespace).Error; err != nil {
+					return err
+				}
+			}
+
+			return tx.Raw("SELECT COUNT(*) FROM items").Scan(&count).Error
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count != want {
+			t.Errorf("namespace %q: saw %d items, want %d", namespace, count, want)
+		}
+	}
+}
+
+func assertItemIDs(t *testing.T, what string, items []Item, want ...uint) {
+	t.Helper()
+
+	got := make([]uint, 0, len(items))
+	for _, item := range items {
+		got = append(got, item.ID)
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s: got items %v, want %v", what, got, want)
+	}
+}
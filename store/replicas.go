@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	replicaCheckInterval = 5 * time.Second
+	replicaCheckTimeout  = 2 * time.Second
+)
+
+// replica is a Postgres read replica. It only serves reads while its last
+// health check succeeded.
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+	// replayed is the WAL position the replica had replayed at its last
+	// health check.
+	replayed atomic.Uint64
+}
+
+func openReplica(config DBConfig, dsn string) (*replica, error) {
+	// replicas may be down at startup; the health checks find out
+	db, err := openPostgres(config, dsn, &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &replica{db: db}, nil
+}
+
+// replayLSN returns the WAL position the replica has replayed. A server that
+// isn't in recovery reports its current position instead.
+func (r *replica) replayLSN(ctx context.Context) (uint64, error) {
+	var position string
+
+	err := r.db.WithContext(ctx).Raw("SELECT COALESCE(pg_last_wal_replay_lsn(), pg_current_wal_lsn())::text").Scan(&position).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return parseLSN(position)
+}
+
+// parseLSN parses a Postgres WAL position such as "16/B374D848".
+func parseLSN(s string) (uint64, error) {
+	high, low, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid consistency token %q", s)
+	}
+
+	h, err := strconv.ParseUint(high, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid consistency token %q", s)
+	}
+
+	l, err := strconv.ParseUint(low, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid consistency token %q", s)
+	}
+
+	return h<<32 | l, nil
+}
+
+func formatLSN(lsn uint64) string {
+	return fmt.Sprintf("%X/%X", lsn>>32, uint32(lsn))
+}
+
+// MonitorReplicas health-checks the read replicas until ctx is done. Until
+// their first check succeeds, replicas serve no reads.
+func (s *DBStore) MonitorReplicas(ctx context.Context) {
+	if len(s.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for i, r := range s.replicas {
+			checkCtx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+			lsn, err := r.replayLSN(checkCtx)
+			cancel()
+
+			if err != nil {
+				if r.healthy.Swap(false) {
+					logging.FromContext(ctx).Warn("read replica unavailable, reading from primary", zap.Int("replica", i), zap.Error(err))
+				}
+				continue
+			}
+
+			r.replayed.Store(lsn)
+
+			if !r.healthy.Swap(true) {
+				logging.FromContext(ctx).Info("read replica available", zap.Int("replica", i))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prevent collisions with other packages
+type consistencyKey int
+
+var (
+	consistencyTokenKey     consistencyKey = 0
+	consistencyTokenSinkKey consistencyKey = 1
+	readFromPrimaryKey      consistencyKey = 2
+)
+
+// WithConsistencyToken makes GetItem and GetItems on ctx observe at least the
+// writes that returned token, by only reading from replicas that have
+// replayed them.
+func WithConsistencyToken(ctx context.Context, token string) (context.Context, error) {
+	lsn, err := parseLSN(token)
+	if err != nil {
+		return ctx, err
+	}
+
+	return withReadPosition(ctx, lsn), nil
+}
+
+func withReadPosition(ctx context.Context, lsn uint64) context.Context {
+	if current, ok := ctx.Value(consistencyTokenKey).(uint64); ok && current >= lsn {
+		return ctx
+	}
+
+	return context.WithValue(ctx, consistencyTokenKey, lsn)
+}
+
+// WithReadFromPrimary pins the reads on ctx to the primary.
+func WithReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readFromPrimaryKey, true)
+}
+
+// WithConsistencyTokenSink makes item writes on ctx pass a consistency token
+// for their changes to sink once they commit. Tokens are only issued when
+// read replicas are configured.
+func WithConsistencyTokenSink(ctx context.Context, sink func(token string)) context.Context {
+	return context.WithValue(ctx, consistencyTokenSinkKey, sink)
+}
+
+// consistentRead reports whether ctx asks for more than eventual
+// consistency, which a cached result can't promise.
+func consistentRead(ctx context.Context) bool {
+	_, hasToken := ctx.Value(consistencyTokenKey).(uint64)
+	primary, _ := ctx.Value(readFromPrimaryKey).(bool)
+
+	return hasToken || primary
+}
+
+// primaryPosition returns the primary's current WAL position, which covers
+// every transaction committed so far.
+func (s *DBStore) primaryPosition(ctx context.Context) (uint64, error) {
+	var position string
+	if err := s.DB.WithContext(ctx).Raw("SELECT pg_current_wal_lsn()::text").Scan(&position).Error; err != nil {
+		return 0, err
+	}
+
+	return parseLSN(position)
+}
+
+// issueConsistencyToken hands the sink on ctx a token for the writes
+// committed so far, and returns the position it stands for. It returns 0 if
+// there are no replicas to be consistent with.
+func (s *DBStore) issueConsistencyToken(ctx context.Context) uint64 {
+	if len(s.replicas) == 0 {
+		return 0
+	}
+
+	lsn, err := s.primaryPosition(ctx)
+	if err != nil {
+		// the write itself succeeded; without a token, the client can still
+		// pin its reads to the primary
+		logging.FromContext(ctx).Warn("error issuing consistency token", zap.Error(err))
+		return 0
+	}
+
+	if sink, ok := ctx.Value(consistencyTokenSinkKey).(func(string)); ok {
+		sink(formatLSN(lsn))
+	}
+
+	return lsn
+}
+
+// pickReplica returns a healthy replica that satisfies the consistency
+// requested on ctx, round robin, or nil to read from the primary.
+func (s *DBStore) pickReplica(ctx context.Context) *replica {
+	if len(s.replicas) == 0 {
+		return nil
+	}
+
+	if primary, _ := ctx.Value(readFromPrimaryKey).(bool); primary {
+		return nil
+	}
+
+	position, hasPosition := ctx.Value(consistencyTokenKey).(uint64)
+	start := s.nextReplica.Add(1)
+
+	for i := range s.replicas {
+		r := s.replicas[(start+uint64(i))%uint64(len(s.replicas))]
+		if !r.healthy.Load() {
+			continue
+		}
+
+		if hasPosition && r.replayed.Load() < position {
+			// the last health check may be out of date
+			lsn, err := r.replayLSN(ctx)
+			if err != nil || lsn < position {
+				continue
+			}
+			r.replayed.Store(lsn)
+		}
+
+		return r
+	}
+
+	return nil
+}
+
+// tenantRead is tenant for read-only work that a replica, or the SQLite
+// readers, can serve. If a replica fails, it is taken out of rotation until
+// its next successful health check and the read is retried on the primary.
+func (s *DBStore) tenantRead(ctx context.Context, fn func(tx *gorm.DB, namespace string) error) error {
+	if r := s.pickReplica(ctx); r != nil {
+		namespace := tenancy.FromContext(ctx)
+
+		err := inTransaction(r.db.WithContext(ctx), namespace, func(tx *gorm.DB) error {
+			return fn(tx, namespace)
+		})
+		if err == nil || errors.Is(err, gorm.ErrRecordNotFound) || ctx.Err() != nil {
+			return err
+		}
+
+		logging.FromContext(ctx).Warn("error reading from replica, retrying on primary", zap.Error(err))
+		r.healthy.Store(false)
+	}
+
+	namespace := tenancy.FromContext(ctx)
+
+	return s.readTransaction(ctx, namespace, func(tx *gorm.DB) error {
+		return fn(tx, namespace)
+	})
+}
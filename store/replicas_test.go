@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestParseLSN(t *testing.T) {
+	for _, tc := range []struct {
+		token string
+		want  uint64
+	}{
+		{token: "0/0", want: 0},
+		{token: "0/1", want: 1},
+		{token: "16/B374D848", want: 0x16_B374D848},
+		{token: "16/b374d848", want: 0x16_B374D848},
+		{token: "FFFFFFFF/FFFFFFFF", want: 1<<64 - 1},
+	} {
+		t.Run(tc.token, func(t *testing.T) {
+			lsn, err := parseLSN(tc.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lsn != tc.want {
+				t.Errorf("parseLSN(%q) = %X, want %X", tc.token, lsn, tc.want)
+			}
+
+			// tokens are issued in the upper case form Postgres prints
+			if lsn, err := parseLSN(formatLSN(tc.want)); err != nil || lsn != tc.want {
+				t.Errorf("parseLSN(formatLSN(%X)) = %X, %v", tc.want, lsn, err)
+			}
+		})
+	}
+}
+
+func TestParseLSNInvalid(t *testing.T) {
+	for _, token := range []string{"", "16", "16/", "/B374D848", "16/B374D848/1", "G/0", "0/G", "100000000/0", "0/100000000", "-1/0", " 16/B374D848"} {
+		t.Run(token, func(t *testing.T) {
+			if _, err := parseLSN(token); err == nil {
+				t.Errorf("parseLSN(%q): no error", token)
+			}
+		})
+	}
+}
+
+func TestFormatLSN(t *testing.T) {
+	if got, want := formatLSN(0x16_B374D848), "16/B374D848"; got != want {
+		t.Errorf("formatLSN() = %q, want %q", got, want)
+	}
+	if got, want := formatLSN(0x1_00000000), "1/0"; got != want {
+		t.Errorf("formatLSN() = %q, want %q", got, want)
+	}
+}
+
+func TestWithConsistencyToken(t *testing.T) {
+	ctx := context.Background()
+	if consistentRead(ctx) {
+		t.Error("a plain context asks for a consistent read")
+	}
+
+	ctx, err := WithConsistencyToken(ctx, "1/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !consistentRead(ctx) {
+		t.Error("a context with a token doesn't ask for a consistent read")
+	}
+
+	// an older position doesn't lower the one already required
+	ctx, err = WithConsistencyToken(ctx, "0/FF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if position := ctx.Value(consistencyTokenKey).(uint64); position != 1<<32 {
+		t.Errorf("position = %X, want 1/0", position)
+	}
+
+	if ctx = withReadPosition(ctx, 2<<32); ctx.Value(consistencyTokenKey).(uint64) != 2<<32 {
+		t.Error("a newer position wasn't applied")
+	}
+
+	if _, err := WithConsistencyToken(context.Background(), "latest"); err == nil {
+		t.Error("WithConsistencyToken() with an invalid token: no error")
+	}
+
+	if !consistentRead(WithReadFromPrimary(context.Background())) {
+		t.Error("a read from the primary isn't a consistent read")
+	}
+}
+
+// fakeReplica is a replica with the given health state. Its database is an
+// empty SQLite one, so checking its replay position fails, as does reading
+// items from it.
+func fakeReplica(t *testing.T, healthy bool, replayed uint64) *replica {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(InMemory), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &replica{db: db}
+	r.healthy.Store(healthy)
+	r.replayed.Store(replayed)
+
+	return r
+}
+
+func closeReplicas(t *testing.T, replicas ...*replica) {
+	t.Cleanup(func() {
+		for _, r := range replicas {
+			if sqlDB, err := r.db.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}
+	})
+}
+
+func TestPickReplica(t *testing.T) {
+	healthy1, healthy2 := fakeReplica(t, true, 10), fakeReplica(t, true, 20)
+	unhealthy := fakeReplica(t, false, 30)
+	closeReplicas(t, healthy1, healthy2, unhealthy)
+
+	ctx := context.Background()
+
+	t.Run("no replicas", func(t *testing.T) {
+		if r := (&DBStore{}).pickReplica(ctx); r != nil {
+			t.Error("picked a replica of a store without any")
+		}
+	})
+
+	t.Run("round robin over the healthy ones", func(t *testing.T) {
+		s := &DBStore{replicas: []*replica{healthy1, unhealthy, healthy2}}
+
+		picked := map[*replica]int{}
+		for i := 0; i < 30; i++ {
+			picked[s.pickReplica(ctx)]++
+		}
+
+		if picked[unhealthy] != 0 || picked[nil] != 0 {
+			t.Errorf("picked an unhealthy replica %d times and the primary %d times", picked[unhealthy], picked[nil])
+		}
+		if picked[healthy1] < 10 || picked[healthy2] < 10 {
+			t.Errorf("picked the healthy replicas %d and %d times, want both in turn", picked[healthy1], picked[healthy2])
+		}
+	})
+
+	t.Run("none healthy", func(t *testing.T) {
+		s := &DBStore{replicas: []*replica{unhealthy}}
+		if r := s.pickReplica(ctx); r != nil {
+			t.Error("picked an unhealthy replica")
+		}
+	})
+
+	t.Run("read from primary", func(t *testing.T) {
+		s := &DBStore{replicas: []*replica{healthy1, healthy2}}
+		if r := s.pickReplica(WithReadFromPrimary(ctx)); r != nil {
+			t.Error("picked a replica for a read from the primary")
+		}
+	})
+
+	t.Run("caught up replicas only", func(t *testing.T) {
+		s := &DBStore{replicas: []*replica{healthy1, healthy2, unhealthy}}
+
+		// healthy1 has replayed 10, and checking it again fails
+		for i := 0; i < 10; i++ {
+			if r := s.pickReplica(withReadPosition(ctx, 15)); r != healthy2 {
+				t.Fatalf("picked %p, want the replica that replayed position 15", r)
+			}
+		}
+
+		if r := s.pickReplica(withReadPosition(ctx, 25)); r != nil {
+			t.Error("picked a replica behind the position read")
+		}
+	})
+}
+
+// TestTenantReadFailover reads through a replica that fails, which falls back
+// to the primary and takes the replica out of rotation.
+func TestTenantReadFailover(t *testing.T) {
+	dbStore, err := NewSQLiteBackedStore(SQLiteConfig{Path: InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbStore.Close() })
+
+	if err := dbStore.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+	id, err := dbStore.CreateItem(ctx, &Item{Name: "Kettle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// closed along with the store
+	failing := fakeReplica(t, true, 0)
+	dbStore.replicas = []*replica{failing}
+
+	item, err := dbStore.GetItem(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Name != "Kettle" {
+		t.Errorf("name = %q, want Kettle", item.Name)
+	}
+
+	if failing.healthy.Load() {
+		t.Error("the failing replica is still healthy")
+	}
+	if r := dbStore.pickReplica(ctx); r != nil {
+		t.Error("picked the failed replica")
+	}
+}
@@ -0,0 +1,357 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/mattn/go-sqlite3"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned without touching the database while the circuit
+// breaker is open, i.e. after repeated connection failures.
+var ErrUnavailable = errors.New("database unavailable")
+
+// ErrConflict wraps errors of transactions that lost to a concurrent one,
+// after retrying, or that would have duplicated a unique key.
+var ErrConflict = errors.New("conflicting concurrent update")
+
+const (
+	defaultMaxOpenConns     = 25
+	defaultMaxIdleConns     = 10
+	defaultConnMaxLifetime  = 30 * time.Minute
+	defaultStatementTimeout = 30 * time.Second
+	defaultConnectTimeout   = 5 * time.Second
+	defaultStartupTimeout   = time.Minute
+
+	// transactionAttempts bounds how often a transaction is tried when it
+	// fails with a transient error.
+	transactionAttempts = 3
+	retryBaseDelay      = 25 * time.Millisecond
+	retryMaxDelay       = time.Second
+
+	startupBaseDelay = 250 * time.Millisecond
+	startupMaxDelay  = 5 * time.Second
+
+	// breakerThreshold consecutive connection failures open the circuit
+	// breaker for breakerCooldown, after which a single probe may try again.
+	breakerThreshold = 5
+	breakerCooldown  = 5 * time.Second
+)
+
+// DBConfig tunes the Postgres connections. Zero values leave the driver
+// defaults in place.
+type DBConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// StatementTimeout aborts statements that run longer, server side.
+	StatementTimeout time.Duration
+	// ConnectTimeout bounds establishing a single connection.
+	ConnectTimeout time.Duration
+	// StartupTimeout bounds how long opening the store keeps retrying an
+	// unreachable database before giving up. Zero retries until the context
+	// is done.
+	StartupTimeout time.Duration
+}
+
+// DBConfigFromEnv reads DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and the Go
+// durations DB_CONN_MAX_LIFETIME, DB_STATEMENT_TIMEOUT, DB_CONNECT_TIMEOUT and
+// DB_STARTUP_TIMEOUT. Zero disables a limit.
+func DBConfigFromEnv() (DBConfig, error) {
+	config := DBConfig{
+		MaxOpenConns:     defaultMaxOpenConns,
+		MaxIdleConns:     defaultMaxIdleConns,
+		ConnMaxLifetime:  defaultConnMaxLifetime,
+		StatementTimeout: defaultStatementTimeout,
+		ConnectTimeout:   defaultConnectTimeout,
+		StartupTimeout:   defaultStartupTimeout,
+	}
+
+	for name, value := range map[string]*int{
+		"DB_MAX_OPEN_CONNS": &config.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS": &config.MaxIdleConns,
+	} {
+		if env := os.Getenv(name); env != "" {
+			var err error
+			if *value, err = strconv.Atoi(env); err != nil || *value < 0 {
+				return config, fmt.Errorf("invalid %s %q", name, env)
+			}
+		}
+	}
+
+	for name, value := range map[string]*time.Duration{
+		"DB_CONN_MAX_LIFETIME": &config.ConnMaxLifetime,
+		"DB_STATEMENT_TIMEOUT": &config.StatementTimeout,
+		"DB_CONNECT_TIMEOUT":   &config.ConnectTimeout,
+		"DB_STARTUP_TIMEOUT":   &config.StartupTimeout,
+	} {
+		if env := os.Getenv(name); env != "" {
+			var err error
+			if *value, err = time.ParseDuration(env); err != nil || *value < 0 {
+				return config, fmt.Errorf("invalid %s %q", name, env)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// openPostgres opens a connection pool to dsn tuned by config.
+func openPostgres(config DBConfig, dsn string, gormConfig *gorm.Config) (*gorm.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ConnectTimeout > 0 {
+		connConfig.ConnectTimeout = config.ConnectTimeout
+	}
+
+	if config.StatementTimeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(config.StatementTimeout.Milliseconds(), 10)
+	}
+
+	sqlDB := stdlib.OpenDB(*connConfig)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	// zero would disable idle connections rather than the limit
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), gormConfig)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// openPostgresWithRetry is openPostgres retried with exponential backoff for
+// up to config.StartupTimeout, so the server can start before the database
+// is up.
+func openPostgresWithRetry(ctx context.Context, config DBConfig, dsn string) (*gorm.DB, error) {
+	var deadline time.Time
+	if config.StartupTimeout > 0 {
+		deadline = time.Now().Add(config.StartupTimeout)
+	}
+
+	for attempt := 0; ; attempt++ {
+		db, err := openPostgres(config, dsn, &gorm.Config{})
+		if err == nil {
+			return db, nil
+		}
+
+		delay := backoff(attempt, startupBaseDelay, startupMaxDelay)
+		if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+
+		logging.FromContext(ctx).Warn("database unavailable, retrying", zap.Error(err), zap.Duration("delay", delay))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoff returns a random delay of up to base * 2^attempt, capped at max,
+// so clients retrying at once don't all come back at once.
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	ceiling := max
+	if attempt < 30 && base<<attempt < max {
+		ceiling = base << attempt
+	}
+
+	return rand.N(ceiling) + 1
+}
+
+// Transient reports whether err is a failure worth retrying the call for
+// later, such as a lost connection or a busy database, rather than one of the
+// call itself.
+func Transient(err error) bool {
+	return transientError(err, false)
+}
+
+// transientError reports whether err is worth retrying the transaction for.
+// committing is set when the error came from COMMIT, where a lost connection
+// leaves it unknown whether the transaction was applied.
+func transientError(err error, committing bool) bool {
+	if serializationFailure(err) {
+		return true
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) || !connectionError(err) {
+		return false
+	}
+
+	return !committing || pgconn.SafeToRetry(err)
+}
+
+// serializationFailure reports whether err means the transaction collided
+// with a concurrent one and would likely succeed if run again.
+func serializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001", "40P01": // serialization_failure, deadlock_detected
+			return true
+		}
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+
+	return false
+}
+
+// uniqueViolation reports whether err means a row with the same unique key
+// already exists.
+func uniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505" // unique_violation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+
+	return false
+}
+
+// connectionError reports whether err means the database couldn't be
+// reached, as opposed to it rejecting a query.
+func connectionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "08"): // connection_exception
+			return true
+		case strings.HasPrefix(pgErr.Code, "57"): // operator_intervention, e.g. admin_shutdown
+			return pgErr.Code != "57014" // query_canceled
+		}
+
+		return false
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.As(err, &connectErr) ||
+		errors.As(err, &netErr)
+}
+
+// breaker fails fast while the database is unreachable instead of having
+// every request wait for a connection timeout.
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow reports whether an operation may go to the database. Once the
+// breaker's cooldown is over, it lets a single probe through.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < breakerThreshold {
+		return true
+	}
+
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false
+	}
+
+	b.probing = true
+
+	return true
+}
+
+// record takes the outcome of an operation allow let through. A nil error
+// or one the database itself returned counts as success; canceled operations
+// don't count either way.
+func (b *breaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasProbing := b.probing
+	b.probing = false
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+	case err != nil && connectionError(err):
+		b.failures++
+		if wasProbing || b.failures >= breakerThreshold {
+			b.failures = max(b.failures, breakerThreshold)
+			b.openUntil = time.Now().Add(breakerCooldown)
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+// withRetry runs a transaction through the circuit breaker, and retries it
+// with jittered backoff on transient errors. The transaction must be safe to
+// run again; it calls commit once its work is done, before COMMIT.
+func (s *DBStore) withRetry(ctx context.Context, transaction func(commit func()) error) error {
+	for attempt := 0; ; attempt++ {
+		if !s.breaker.allow() {
+			return ErrUnavailable
+		}
+
+		var committing bool
+		err := transaction(func() { committing = true })
+		s.breaker.record(ctx, err)
+
+		if err == nil || attempt+1 == transactionAttempts || ctx.Err() != nil || !transientError(err, committing) {
+			if serializationFailure(err) || uniqueViolation(err) {
+				return fmt.Errorf("%w: %w", ErrConflict, err)
+			}
+			return err
+		}
+
+		delay := backoff(attempt, retryBaseDelay, retryMaxDelay)
+		logging.FromContext(ctx).Debug("retrying transaction", zap.Error(err), zap.Int("attempt", attempt+1), zap.Duration("delay", delay))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestBreaker(t *testing.T) {
+	ctx := context.Background()
+	var b breaker
+
+	// failures below the threshold, or that aren't about the connection,
+	// keep it closed
+	for i := 0; i < breakerThreshold-1; i++ {
+		if !b.allow() {
+			t.Fatalf("closed after %d failures", i)
+		}
+		b.record(ctx, driver.ErrBadConn)
+	}
+	b.record(ctx, gorm.ErrRecordNotFound)
+	if b.failures != 0 {
+		t.Fatalf("failures = %d after a query error, want 0", b.failures)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	for i := 0; i < breakerThreshold; i++ {
+		b.record(canceled, driver.ErrBadConn)
+	}
+	if !b.allow() {
+		t.Fatal("opened on canceled operations")
+	}
+
+	for i := 0; i < breakerThreshold; i++ {
+		b.record(ctx, driver.ErrBadConn)
+	}
+	if b.allow() {
+		t.Fatalf("still closed after %d failures", breakerThreshold)
+	}
+	if cooldown := time.Until(b.openUntil); cooldown <= 0 || cooldown > breakerCooldown {
+		t.Errorf("open for %v, want up to %v", cooldown, breakerCooldown)
+	}
+
+	// after the cooldown a single probe goes through, and failing opens the
+	// breaker again
+	b.openUntil = time.Now()
+	if !b.allow() {
+		t.Fatal("no probe after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("a second operation went through while probing")
+	}
+	b.record(ctx, driver.ErrBadConn)
+	if b.allow() {
+		t.Fatal("closed after a failed probe")
+	}
+
+	// a successful probe closes it
+	b.openUntil = time.Now()
+	if !b.allow() {
+		t.Fatal("no probe after the cooldown")
+	}
+	b.record(ctx, nil)
+	for i := 0; i < breakerThreshold; i++ {
+		if !b.allow() {
+			t.Fatal("still open after a successful probe")
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+
+	busy := sqlite3.Error{Code: sqlite3.ErrBusy}
+	unique := sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}
+	invalid := errors.New("invalid item")
+
+	for _, tc := range []struct {
+		name       string
+		err        error
+		committing bool
+		attempts   int
+		conflict   bool
+	}{
+		{name: "success", attempts: 1},
+		{name: "serialization failure", err: busy, attempts: transactionAttempts, conflict: true},
+		{name: "lost connection", err: driver.ErrBadConn, attempts: transactionAttempts},
+		{name: "lost connection committing", err: driver.ErrBadConn, committing: true, attempts: 1},
+		{name: "unique violation", err: unique, attempts: 1, conflict: true},
+		{name: "not found", err: gorm.ErrRecordNotFound, attempts: 1},
+		{name: "invalid", err: invalid, attempts: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &DBStore{}
+
+			attempts := 0
+			err := s.withRetry(ctx, func(commit func()) error {
+				attempts++
+				if tc.committing {
+					commit()
+				}
+				return tc.err
+			})
+
+			if attempts != tc.attempts {
+				t.Errorf("tried %d times, want %d", attempts, tc.attempts)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("got %v, want %v", err, tc.err)
+			}
+			if errors.Is(err, ErrConflict) != tc.conflict {
+				t.Errorf("got %v, want a conflict: %v", err, tc.conflict)
+			}
+		})
+	}
+
+	t.Run("recovers", func(t *testing.T) {
+		s := &DBStore{}
+
+		attempts := 0
+		err := s.withRetry(ctx, func(commit func()) error {
+			if attempts++; attempts < transactionAttempts {
+				return busy
+			}
+			return nil
+		})
+		if err != nil || attempts != transactionAttempts {
+			t.Errorf("got %v after %d attempts, want success after %d", err, attempts, transactionAttempts)
+		}
+	})
+
+	t.Run("breaker open", func(t *testing.T) {
+		s := &DBStore{}
+		for i := 0; i < breakerThreshold; i++ {
+			s.breaker.record(ctx, driver.ErrBadConn)
+		}
+
+		err := s.withRetry(ctx, func(commit func()) error {
+			t.Error("ran a transaction while the breaker is open")
+			return nil
+		})
+		if !errors.Is(err, ErrUnavailable) {
+			t.Errorf("got %v, want ErrUnavailable", err)
+		}
+	})
+}
+
+// TestWebhookDeliveryWrites checks that the dispatcher's writes go through
+// the circuit breaker like every other transaction.
+func TestWebhookDeliveryWrites(t *testing.T) {
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := logging.WithLogger(context.Background(), zap.NewNop())
+
+			for i := 0; i < breakerThreshold; i++ {
+				dbStore.breaker.record(ctx, driver.ErrBadConn)
+			}
+			defer dbStore.breaker.record(ctx, nil)
+
+			if err := dbStore.SaveWebhookDeliveryAttempt(ctx, &WebhookDelivery{}); !errors.Is(err, ErrUnavailable) {
+				t.Errorf("SaveWebhookDeliveryAttempt() = %v, want ErrUnavailable", err)
+			}
+			if err := dbStore.CompactWebhookDeliveries(ctx, time.Now()); !errors.Is(err, ErrUnavailable) {
+				t.Errorf("CompactWebhookDeliveries() = %v, want ErrUnavailable", err)
+			}
+		})
+	}
+}
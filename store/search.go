@@ -0,0 +1,246 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var ftsTriggers = []string{"items_fts_insert", "items_fts_delete", "items_fts_update"}
+
+// SearchResult is an item matched by SearchItems. Higher scores rank first;
+// scores are only comparable within a single result set.
+type SearchResult struct {
+	Item
+	Score float64
+}
+
+// SearchTerms splits a free-text query into the lower-cased terms matched by
+// SearchItems. Punctuation and query operators are dropped, so user input can
+// never change the shape of the full-text query.
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// migrateSearch creates the full-text index and the database-side machinery
+// that keeps it in sync with the items table. Postgres indexes a generated
+// tsvector column; SQLite mirrors items into an FTS5 table via triggers.
+func (s *DBStore) migrateSearch() error {
+	if s.DB.Dialector.Name() == "postgres" {
+		return s.DB.Exec(`
+			ALTER TABLE items ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+				setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+			) STORED;
+			CREATE INDEX IF NOT EXISTS idx_items_search_vector ON items USING GIN (search_vector);
+		`).Error
+	}
+
+	// triggers that write to an FTS5 table would break every write to items
+	// if this binary can't load the fts5 module, so drop them; the index is
+	// rebuilt once a binary with fts5 migrates again
+	probe := s.DB.Session(&gorm.Session{Logger: logger.Discard})
+	if err := probe.Exec("CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(x)").Error; err != nil {
+		if !strings.Contains(err.Error(), "no such module: fts5") {
+			return err
+		}
+
+		for _, trigger := range ftsTriggers {
+			if err := s.DB.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := s.DB.Exec("DROP TABLE temp.fts5_probe").Error; err != nil {
+		return err
+	}
+
+	var installed int64
+	err := s.DB.Table("sqlite_master").Where("type = 'trigger' AND name IN ?", ftsTriggers).Count(&installed).Error
+	if err != nil {
+		return err
+	}
+
+	if installed < int64(len(ftsTriggers)) {
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			statements := []string{
+				`CREATE VIRTUAL TABLE IF NOT EXISTS items_fts USING fts5(name, description, content='items', content_rowid='id')`,
+				`CREATE TRIGGER IF NOT EXISTS items_fts_insert AFTER INSERT ON items BEGIN
+					INSERT INTO items_fts(rowid, name, description) VALUES (new.id, new.name, new.description);
+				END`,
+				`CREATE TRIGGER IF NOT EXISTS items_fts_delete AFTER DELETE ON items BEGIN
+					INSERT INTO items_fts(items_fts, rowid, name, description) VALUES ('delete', old.id, old.name, old.description);
+				END`,
+				`CREATE TRIGGER IF NOT EXISTS items_fts_update AFTER UPDATE ON items BEGIN
+					INSERT INTO items_fts(items_fts, rowid, name, description) VALUES ('delete', old.id, old.name, old.description);
+					INSERT INTO items_fts(rowid, name, description) VALUES (new.id, new.name, new.description);
+				END`,
+				`INSERT INTO items_fts(items_fts) VALUES ('rebuild')`,
+			}
+
+			for _, statement := range statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	s.searchEnabled = true
+
+	return nil
+}
+
+// SearchItems returns up to limit items in the namespace on ctx whose name or
+// description contains every term of query as a word prefix, best match
+// first. Name matches outweigh description matches.
+func (s *DBStore) SearchItems(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	search := searchItems
+	if s.DB.Dialector.Name() != "postgres" && !s.searchEnabled {
+		search = scanItems
+	}
+
+	var results []SearchResult
+
+	err := s.tenantRead(ctx, func(tx *gorm.DB, namespace string) error {
+		if err := search(tx, namespace, terms, limit, &results); err != nil {
+			return err
+		}
+
+		return loadSearchResultLabels(tx, results)
+	})
+
+	return results, err
+}
+
+func searchItems(db *gorm.DB, namespace string, terms []string, limit int, results *[]SearchResult) error {
+	if db.Dialector.Name() == "postgres" {
+		prefixes := make([]string, 0, len(terms))
+		for _, term := range terms {
+			prefixes = append(prefixes, term+":*")
+		}
+
+		tsquery := db.Raw("to_tsquery('simple', ?)", strings.Join(prefixes, " & "))
+
+		return db.Model(&Item{}).
+			Select("items.*, ts_rank(search_vector, (?)) AS score", tsquery).
+			Where("items.namespace = ? AND search_vector @@ (?)", namespace, tsquery).
+			Order("score DESC, id").
+			Limit(limit).
+			Scan(results).Error
+	}
+
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, fmt.Sprintf("%q*", term))
+	}
+
+	// bm25 ranks lower-is-better; negate it so both backends sort descending
+	return db.Model(&Item{}).
+		Select("items.*, -bm25(items_fts, 10.0, 1.0) AS score").
+		Joins("JOIN items_fts ON items_fts.rowid = items.id").
+		Where("items.namespace = ? AND items_fts MATCH ?", namespace, strings.Join(prefixes, " AND ")).
+		Order("score DESC, items.id").
+		Limit(limit).
+		Scan(results).Error
+}
+
+// scanItems searches SQLite built without FTS5. LIKE narrows the items down
+// to those containing every term, and Go then matches the terms as word
+// prefixes and scores them, a name match counting as much as ten
+// description matches, as with bm25. It reads every candidate, so it is
+// slower than the index, but returns the same results.
+func scanItems(db *gorm.DB, namespace string, terms []string, limit int, results *[]SearchResult) error {
+	query := db.Model(&Item{}).Where("namespace = ?", namespace)
+	for _, term := range terms {
+		// LIKE only folds the case of ASCII letters, so other terms are
+		// left to the matching below
+		if isASCII(term) {
+			pattern := "%" + term + "%"
+			query = query.Where("(name LIKE ? OR description LIKE ?)", pattern, pattern)
+		}
+	}
+
+	var candidates []Item
+	if err := query.Order("id").Find(&candidates).Error; err != nil {
+		return err
+	}
+
+	for _, item := range candidates {
+		nameWords, descriptionWords := SearchTerms(item.Name), SearchTerms(item.Description)
+
+		score := 0.0
+		for _, term := range terms {
+			inName, inDescription := countPrefixed(nameWords, term), countPrefixed(descriptionWords, term)
+			if inName+inDescription == 0 {
+				score = 0
+				break
+			}
+			score += 10*float64(inName) + float64(inDescription)
+		}
+
+		if score > 0 {
+			*results = append(*results, SearchResult{Item: item, Score: score})
+		}
+	}
+
+	sort.SliceStable(*results, func(i, j int) bool {
+		return (*results)[i].Score > (*results)[j].Score
+	})
+
+	if len(*results) > limit {
+		*results = (*results)[:limit]
+	}
+
+	return nil
+}
+
+// countPrefixed counts the words starting with prefix.
+func countPrefixed(words []string, prefix string) int {
+	count := 0
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+func loadSearchResultLabels(db *gorm.DB, results []SearchResult) error {
+	items := make([]*Item, len(results))
+	for i := range results {
+		items[i] = &results[i].Item
+	}
+
+	return loadLabels(db, items...)
+}
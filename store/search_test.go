@@ -0,0 +1,154 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var searchCorpus = []Item{
+	{Name: "Red bicycle", Description: "A lightweight road bike with carbon wheels"},
+	{Name: "Blue bicycle helmet", Description: "Protective gear for cyclists"},
+	{Name: "Carbon fiber tripod", Description: "Sturdy camera stand, folds to 40cm"},
+	{Name: "Road atlas", Description: "Maps of every highway and back road"},
+	{Name: "Kettle", Description: "Electric kettle, boils water in two minutes"},
+	{Name: "Kettlebell", Description: "16kg cast iron weight"},
+}
+
+// searchCases map queries to the IDs (1-based corpus positions) both backends
+// must return. When ordered is set, the results must also rank in that order.
+var searchCases = []struct {
+	query   string
+	want    []uint
+	ordered bool
+}{
+	{query: "bicycle", want: []uint{1, 2}},
+	{query: "bicy", want: []uint{1, 2}},
+	{query: "carbon", want: []uint{3, 1}, ordered: true},
+	{query: "road", want: []uint{4, 1}, ordered: true},
+	{query: "kettle", want: []uint{5, 6}},
+	{query: "KETTLE water", want: []uint{5}},
+	{query: "bicycle helmet", want: []uint{2}},
+	{query: "submarine", want: nil},
+	{query: `road" OR "kettle*`, want: nil},
+	{query: "   ", want: nil},
+}
+
+func TestSearchItems(t *testing.T) {
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for _, item := range searchCorpus {
+				if _, err := dbStore.CreateItem(ctx, &item); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// a deleted item must drop out of the index
+			id, err := dbStore.CreateItem(ctx, &Item{Name: "Bicycle pump"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := dbStore.DeleteItem(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, tc := range searchCases {
+				results, err := dbStore.SearchItems(ctx, tc.query, 10)
+				if err != nil {
+					t.Fatalf("%q: %v", tc.query, err)
+				}
+
+				got := make([]uint, 0, len(results))
+				for _, result := range results {
+					got = append(got, result.ID)
+				}
+
+				want := append([]uint(nil), tc.want...)
+				if !tc.ordered {
+					sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+					sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+				}
+
+				if fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("%q: got %v, want %v", tc.query, got, want)
+				}
+			}
+
+			// updates must be reflected in the index
+			if _, err := dbStore.UpdateItem(ctx, &Item{Model: gorm.Model{ID: 5}, Name: "Teapot", Description: "Ceramic"}); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := dbStore.SearchItems(ctx, "kettle", 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(results) != 1 || results[0].ID != 6 {
+				t.Errorf("after update: got %+v, want only item 6", results)
+			}
+		})
+	}
+}
+
+// testStores returns a fresh SQLite store, and a Postgres store in a
+// throwaway schema when TEST_POSTGRES_DSN is set.
+func testStores(t *testing.T) map[string]*DBStore {
+	t.Helper()
+
+	stores := make(map[string]*DBStore)
+
+	sqliteStore, err := NewSQLiteBackedStore(SQLiteConfig{Path: InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sqliteStore.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	stores["sqlite"] = sqliteStore
+
+	if dsn := os.Getenv("TEST_POSTGRES_DSN"); dsn != "" {
+		admin, err := NewPostgresBackedStore(context.Background(), DBConfig{}, dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema := fmt.Sprintf("store_test_%d", time.Now().UnixNano())
+		if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+		separator := " "
+		if strings.Contains(dsn, "://") {
+			separator = "&"
+			if !strings.Contains(dsn, "?") {
+				separator = "?"
+			}
+		}
+
+		pgStore, err := NewPostgresBackedStore(context.Background(), DBConfig{}, dsn+separator+"search_path="+schema)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := pgStore.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		stores["postgres"] = pgStore
+	}
+
+	return stores
+}
@@ -0,0 +1,299 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+type Operator string
+
+const (
+	OpEquals       Operator = "="
+	OpNotEquals    Operator = "!="
+	OpIn           Operator = "in"
+	OpNotIn        Operator = "notin"
+	OpExists       Operator = "exists"
+	OpDoesNotExist Operator = "!"
+)
+
+// Requirement is a single clause of a label selector.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector is a conjunction of requirements, written in the Kubernetes label
+// selector syntax: "env=prod,team in (a,b),!deprecated". The empty selector
+// matches every item.
+type Selector []Requirement
+
+// ParseSelector parses a Kubernetes-style label selector. It supports =, ==,
+// !=, in, notin, bare keys (exists) and !key (does not exist).
+func ParseSelector(input string) (Selector, error) {
+	tokens, err := lexSelector(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &selectorParser{tokens: tokens}
+	var selector Selector
+
+	for p.more() {
+		req, err := p.requirement()
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", input, err)
+		}
+		selector = append(selector, req)
+
+		if p.more() {
+			if tok := p.next(); tok != "," {
+				return nil, fmt.Errorf("invalid label selector %q: expected ',' but found %q", input, tok)
+			}
+			if !p.more() {
+				return nil, fmt.Errorf("invalid label selector %q: trailing ','", input)
+			}
+		}
+	}
+
+	return selector, nil
+}
+
+// Matches reports whether labels satisfy every requirement of the selector.
+func (s Selector) Matches(labels Labels) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+
+		switch req.Operator {
+		case OpEquals, OpIn:
+			if !ok || !slices.Contains(req.Values, value) {
+				return false
+			}
+		case OpNotEquals, OpNotIn:
+			if ok && slices.Contains(req.Values, value) {
+				return false
+			}
+		case OpExists:
+			if !ok {
+				return false
+			}
+		case OpDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+
+	for _, req := range s {
+		switch req.Operator {
+		case OpEquals, OpNotEquals:
+			parts = append(parts, req.Key+string(req.Operator)+req.Values[0])
+		case OpIn, OpNotIn:
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", req.Key, req.Operator, strings.Join(req.Values, ",")))
+		case OpExists:
+			parts = append(parts, req.Key)
+		case OpDoesNotExist:
+			parts = append(parts, "!"+req.Key)
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// apply restricts a query on items to those matching the selector. Negative
+// requirements match items that lack the key, as in Kubernetes.
+func (s Selector) apply(db *gorm.DB) *gorm.DB {
+	for _, req := range s {
+		labels := db.Session(&gorm.Session{NewDB: true}).Model(&ItemLabel{}).
+			Select("1").Where("item_labels.item_id = items.id AND item_labels.key = ?", req.Key)
+
+		switch req.Operator {
+		case OpEquals, OpIn:
+			db = db.Where("EXISTS (?)", labels.Where("item_labels.value IN ?", req.Values))
+		case OpNotEquals, OpNotIn:
+			db = db.Where("NOT EXISTS (?)", labels.Where("item_labels.value IN ?", req.Values))
+		case OpExists:
+			db = db.Where("EXISTS (?)", labels)
+		case OpDoesNotExist:
+			db = db.Where("NOT EXISTS (?)", labels)
+		}
+	}
+
+	return db
+}
+
+type selectorParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *selectorParser) more() bool {
+	return p.pos < len(p.tokens)
+}
+
+func (p *selectorParser) peek() string {
+	if !p.more() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *selectorParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *selectorParser) requirement() (Requirement, error) {
+	if p.peek() == "!" {
+		p.next()
+
+		key := p.next()
+		if err := ValidateLabelKey(key); err != nil {
+			return Requirement{}, err
+		}
+
+		return Requirement{Key: key, Operator: OpDoesNotExist}, nil
+	}
+
+	key := p.next()
+	if err := ValidateLabelKey(key); err != nil {
+		return Requirement{}, err
+	}
+
+	switch op := p.peek(); op {
+	case "", ",":
+		return Requirement{Key: key, Operator: OpExists}, nil
+	case "=", "==", "!=":
+		p.next()
+
+		var value string
+		switch tok := p.peek(); tok {
+		case "", ",":
+			// an empty value, as in "env="
+		case "(", ")", "!", "=", "==", "!=":
+			return Requirement{}, fmt.Errorf("expected a value after %q", op)
+		default:
+			value = p.next()
+		}
+
+		if err := ValidateLabelValue(value); err != nil {
+			return Requirement{}, err
+		}
+
+		operator := OpEquals
+		if op == "!=" {
+			operator = OpNotEquals
+		}
+
+		return Requirement{Key: key, Operator: operator, Values: []string{value}}, nil
+	case "in", "notin":
+		p.next()
+
+		values, err := p.valueSet()
+		if err != nil {
+			return Requirement{}, err
+		}
+
+		operator := OpIn
+		if op == "notin" {
+			operator = OpNotIn
+		}
+
+		return Requirement{Key: key, Operator: operator, Values: values}, nil
+	default:
+		return Requirement{}, fmt.Errorf("unexpected %q after key %q", op, key)
+	}
+}
+
+func (p *selectorParser) valueSet() ([]string, error) {
+	if p.next() != "(" {
+		return nil, fmt.Errorf("expected '('")
+	}
+
+	var values []string
+
+	for {
+		tok := p.next()
+
+		switch tok {
+		case ")":
+			if len(values) == 0 {
+				return nil, fmt.Errorf("empty value set")
+			}
+			return values, nil
+		case "":
+			return nil, fmt.Errorf("unterminated value set")
+		case ",", "(", "!", "=", "==", "!=":
+			return nil, fmt.Errorf("unexpected %q in value set", tok)
+		}
+
+		if err := ValidateLabelValue(tok); err != nil {
+			return nil, err
+		}
+		values = append(values, tok)
+
+		switch p.peek() {
+		case ",":
+			p.next()
+			if p.peek() == ")" {
+				return nil, fmt.Errorf("unexpected %q in value set", ")")
+			}
+		case ")":
+		case "":
+			return nil, fmt.Errorf("unterminated value set")
+		default:
+			return nil, fmt.Errorf("expected ',' or ')' in value set")
+		}
+	}
+}
+
+// lexSelector splits a selector into identifiers and the punctuation tokens
+// "(", ")", ",", "!", "=", "==" and "!=".
+func lexSelector(input string) ([]string, error) {
+	var tokens []string
+
+	for i := 0; i < len(input); {
+		c := input[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '=' || c == '!':
+			if i+1 < len(input) && input[i+1] == '=' {
+				tokens = append(tokens, input[i:i+2])
+				i += 2
+			} else {
+				tokens = append(tokens, string(c))
+				i++
+			}
+		case isSelectorIdentRune(rune(c)):
+			start := i
+			for i < len(input) && isSelectorIdentRune(rune(input[i])) {
+				i++
+			}
+			tokens = append(tokens, input[start:i])
+		default:
+			return nil, fmt.Errorf("invalid label selector %q: unexpected character %q", input, c)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isSelectorIdentRune(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' || r == '/')
+}
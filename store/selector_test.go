@@ -0,0 +1,176 @@
+package store
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  Selector
+	}{
+		{input: "", want: nil},
+		{input: "   ", want: nil},
+		{input: "env=prod", want: Selector{{Key: "env", Operator: OpEquals, Values: []string{"prod"}}}},
+		{input: "env==prod", want: Selector{{Key: "env", Operator: OpEquals, Values: []string{"prod"}}}},
+		{input: "env!=prod", want: Selector{{Key: "env", Operator: OpNotEquals, Values: []string{"prod"}}}},
+		{input: "env=", want: Selector{{Key: "env", Operator: OpEquals, Values: []string{""}}}},
+		{input: "env!=,tier", want: Selector{
+			{Key: "env", Operator: OpNotEquals, Values: []string{""}},
+			{Key: "tier", Operator: OpExists},
+		}},
+		{input: "team in (a,b)", want: Selector{{Key: "team", Operator: OpIn, Values: []string{"a", "b"}}}},
+		{input: "team notin (a)", want: Selector{{Key: "team", Operator: OpNotIn, Values: []string{"a"}}}},
+		{input: "deprecated", want: Selector{{Key: "deprecated", Operator: OpExists}}},
+		{input: "!deprecated", want: Selector{{Key: "deprecated", Operator: OpDoesNotExist}}},
+		{input: "example.com/owner=ops", want: Selector{{Key: "example.com/owner", Operator: OpEquals, Values: []string{"ops"}}}},
+		{input: " env = prod ,\tteam  in  ( a , b ) , ! deprecated ", want: Selector{
+			{Key: "env", Operator: OpEquals, Values: []string{"prod"}},
+			{Key: "team", Operator: OpIn, Values: []string{"a", "b"}},
+			{Key: "deprecated", Operator: OpDoesNotExist},
+		}},
+		{input: "in=notin", want: Selector{{Key: "in", Operator: OpEquals, Values: []string{"notin"}}}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseSelector(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %#v, want %#v", got, tc.want)
+			}
+
+			// the canonical form parses back to the same selector
+			again, err := ParseSelector(got.String())
+			if err != nil {
+				t.Fatalf("parsing %q: %v", got.String(), err)
+			}
+			if !reflect.DeepEqual(again, got) {
+				t.Errorf("%q parsed as %#v, want %#v", got.String(), again, got)
+			}
+		})
+	}
+}
+
+func TestParseSelectorInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  string
+	}{
+		{input: "team in (a,b", want: "unterminated value set"},
+		{input: "team in (", want: "unterminated value set"},
+		{input: "team in ()", want: "empty value set"},
+		{input: "team in (a,)", want: `unexpected ")"`},
+		{input: "team in (a,,b)", want: `unexpected ","`},
+		{input: "team in (a b)", want: "expected ',' or ')'"},
+		{input: "team in a", want: "expected '('"},
+		{input: "team notin", want: "expected '('"},
+		{input: "env=(prod)", want: `expected a value after "="`},
+		{input: "env==!", want: `expected a value after "=="`},
+		{input: "env=prod,", want: "trailing ','"},
+		{input: ",env", want: `invalid label key ","`},
+		{input: "env=prod tier", want: `expected ',' but found "tier"`},
+		{input: "env prod", want: `unexpected "prod" after key "env"`},
+		{input: "!env=prod", want: `expected ',' but found "="`},
+		{input: "!", want: `invalid label key ""`},
+		{input: "-env=prod", want: `invalid label key "-env"`},
+		{input: "env=prod-", want: `invalid label value "prod-"`},
+		{input: "team in (a,-b)", want: `invalid label value "-b"`},
+		{input: "Example.com/env", want: "prefix must be a DNS subdomain"},
+		{input: "env=prod;", want: "unexpected character ';'"},
+		{input: "env=pröd", want: "unexpected character"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := ParseSelector(tc.input)
+			if err == nil {
+				t.Fatal("got no error")
+			}
+
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got error %q, want it to contain %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	labels := Labels{"env": "prod", "team": "a", "empty": ""}
+
+	for _, tc := range []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "", want: true},
+		{selector: "env=prod", want: true},
+		{selector: "env=dev", want: false},
+		{selector: "env!=dev", want: true},
+		{selector: "env!=prod", want: false},
+		{selector: "missing!=x", want: true},
+		{selector: "missing=", want: false},
+		{selector: "empty=", want: true},
+		{selector: "team in (a,b)", want: true},
+		{selector: "team in (b,c)", want: false},
+		{selector: "team notin (b,c)", want: true},
+		{selector: "team notin (a)", want: false},
+		{selector: "missing notin (a)", want: true},
+		{selector: "env", want: true},
+		{selector: "missing", want: false},
+		{selector: "!missing", want: true},
+		{selector: "!env", want: false},
+		{selector: "env=prod,team in (a),!missing", want: true},
+		{selector: "env=prod,team in (b)", want: false},
+	} {
+		t.Run(tc.selector, func(t *testing.T) {
+			selector, err := ParseSelector(tc.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := selector.Matches(labels); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateLabels(t *testing.T) {
+	for _, tc := range []struct {
+		key, value string
+		valid      bool
+	}{
+		{key: "env", value: "prod", valid: true},
+		{key: "env", value: "", valid: true},
+		{key: "a", value: "b", valid: true},
+		{key: "app.kubernetes.io/name", value: "web_1.2-x", valid: true},
+		{key: "My_Key.v2", value: "Value", valid: true},
+		{key: strings.Repeat("k", 63), value: strings.Repeat("v", 63), valid: true},
+		{key: strings.Repeat("k", 64), value: "v"},
+		{key: "env", value: strings.Repeat("v", 64)},
+		{key: "", value: "v"},
+		{key: "-env", value: "v"},
+		{key: "env-", value: "v"},
+		{key: "env", value: "_prod"},
+		{key: "env", value: "prod."},
+		{key: "env", value: "a b"},
+		{key: "env", value: "a/b"},
+		{key: "/env", value: "v"},
+		{key: "example.com/", value: "v"},
+		{key: "Example.com/env", value: "v"},
+		{key: "example..com/env", value: "v"},
+		{key: "example.com/team/env", value: "v"},
+		{key: strings.Repeat("a.", 127) + "a/env", value: "v"},
+	} {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			err := Labels{tc.key: tc.value}.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("got %v, want valid", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("got valid, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSQLitePath        = "tables.db"
+	defaultSQLiteBusyTimeout = 5 * time.Second
+
+	// InMemory as the SQLite path keeps the database in memory, e.g. for
+	// tests. It is gone once the store is closed.
+	InMemory = ":memory:"
+
+	sqliteReaders = 4
+)
+
+// SQLiteConfig locates and tunes the SQLite database.
+type SQLiteConfig struct {
+	// Path is the database file, or InMemory.
+	Path string
+	// BusyTimeout is how long a connection waits for another process holding
+	// the write lock before failing with SQLITE_BUSY.
+	BusyTimeout time.Duration
+}
+
+// SQLiteConfigFromEnv reads SQLITE_PATH, by default tables.db in the working
+// directory, and SQLITE_BUSY_TIMEOUT, a Go duration.
+func SQLiteConfigFromEnv() (SQLiteConfig, error) {
+	config := SQLiteConfig{Path: defaultSQLitePath, BusyTimeout: defaultSQLiteBusyTimeout}
+
+	if path := os.Getenv("SQLITE_PATH"); path != "" {
+		config.Path = path
+	}
+
+	if timeout := os.Getenv("SQLITE_BUSY_TIMEOUT"); timeout != "" {
+		var err error
+		if config.BusyTimeout, err = time.ParseDuration(timeout); err != nil || config.BusyTimeout < 0 {
+			return config, fmt.Errorf("invalid SQLITE_BUSY_TIMEOUT %q", timeout)
+		}
+	}
+
+	return config, nil
+}
+
+// openSQLite opens the database at config.Path in WAL mode with foreign keys
+// enforced. SQLite allows a single writer at a time, so writes go through a
+// single connection that takes the write lock as soon as a transaction
+// begins; upgrading a read lock later fails with SQLITE_BUSY instead of
+// waiting. Reads that can be served from a snapshot get a pool of read-only
+// connections, which WAL lets run alongside the writer. In memory, every
+// connection would get a database of its own, so the single connection serves
+// reads as well and the returned reader is nil.
+func openSQLite(config SQLiteConfig) (writer, reader *gorm.DB, err error) {
+	pragmas := fmt.Sprintf("_busy_timeout=%d&_foreign_keys=on&_txlock=immediate", config.BusyTimeout.Milliseconds())
+
+	if config.Path == InMemory {
+		writer, err = gorm.Open(sqlite.Open("file::memory:?" + pragmas))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := singleConnection(writer); err != nil {
+			return nil, nil, err
+		}
+
+		return writer, nil, nil
+	}
+
+	writer, err = gorm.Open(sqlite.Open(sqliteURI(config.Path, "_journal_mode=WAL&"+pragmas)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := singleConnection(writer); err != nil {
+		return nil, nil, err
+	}
+
+	// the writer has created the file and switched it to WAL by now
+	reader, err = gorm.Open(sqlite.Open(sqliteURI(config.Path, fmt.Sprintf("_busy_timeout=%d&_query_only=on", config.BusyTimeout.Milliseconds()))))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqlDB, err := reader.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlDB.SetMaxOpenConns(sqliteReaders)
+	sqlDB.SetMaxIdleConns(sqliteReaders)
+
+	return writer, reader, nil
+}
+
+// singleConnection keeps db on one connection for good, which an in-memory
+// database also needs to survive.
+func singleConnection(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	return nil
+}
+
+// sqliteURI is the file: URI of the database at path with query. The path is
+// escaped, so a '?', '#' or '%' in it isn't taken for the start of the query,
+// the fragment or an escape.
+func sqliteURI(path, query string) string {
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + query
+}
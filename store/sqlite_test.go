@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func newSQLiteFileStore(t *testing.T, path string) *DBStore {
+	t.Helper()
+
+	dbStore, err := NewSQLiteBackedStore(SQLiteConfig{Path: path, BusyTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbStore.Close() })
+
+	if err := dbStore.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return dbStore
+}
+
+// TestSQLitePath opens a database whose path has characters that mean
+// something in a URI.
+func TestSQLitePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items?mode=memory#1 %41.db")
+	ctx := context.Background()
+
+	dbStore := newSQLiteFileStore(t, path)
+
+	id, err := dbStore.CreateItem(ctx, &Item{Name: "Kettle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dbStore.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database file: %v", err)
+	}
+
+	reopened := newSQLiteFileStore(t, path)
+	if item, err := reopened.GetItem(ctx, id); err != nil || item.Name != "Kettle" {
+		t.Errorf("got %+v, %v after reopening, want the kettle", item, err)
+	}
+}
+
+// TestSQLiteStreamsDontWaitForWriter holds the single writer connection in a
+// transaction and checks that streams are served by the readers meanwhile.
+func TestSQLiteStreamsDontWaitForWriter(t *testing.T) {
+	dbStore := newSQLiteFileStore(t, filepath.Join(t.TempDir(), "items.db"))
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+
+	for _, name := range []string{"Kettle", "Teapot"} {
+		if _, err := dbStore.CreateItem(ctx, &Item{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	held := make(chan error)
+	go func() {
+		held <- dbStore.DB.Transaction(func(tx *gorm.DB) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	defer func() {
+		close(release)
+		if err := <-held; err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var streamed int
+	err := dbStore.StreamItems(ctx, 1, func(*Item) error {
+		streamed++
+		return nil
+	})
+	if err != nil || streamed != 2 {
+		t.Errorf("StreamItems: got %d items, %v, want 2", streamed, err)
+	}
+
+	results, err := dbStore.SearchItems(ctx, "kettle", 10)
+	if err != nil || len(results) != 1 {
+		t.Errorf("SearchItems: got %v, %v, want the kettle", results, err)
+	}
+
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+
+	var watched int
+	err = dbStore.WatchItems(watchCtx, 0, func(*ItemEvent) error {
+		if watched++; watched == 2 {
+			stopWatching()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || watched != 2 || ctx.Err() != nil {
+		t.Errorf("WatchItems: got %d events, %v, want 2 events before it was stopped", watched, err)
+	}
+}
@@ -1,29 +1,398 @@
 package store
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
 
-func (s *DBStore) GetItem(ctx context.Context, id uint) (*Item, error) {
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNamespaceConflict is returned when a write names an item ID that belongs
+// to another namespace.
+var ErrNamespaceConflict = errors.New("item id belongs to another namespace")
+
+// GetItem returns gorm.ErrRecordNotFound if no item with the given ID exists
+// in the namespace on ctx. If fields are given, only those fields and the ID
+// are loaded.
+func (s *DBStore) GetItem(ctx context.Context, id uint, fields ...string) (*Item, error) {
+	if s.cache != nil && !consistentRead(ctx) {
+		return s.cachedItem(ctx, id, fields)
+	}
+
+	return s.getItem(ctx, id, fields)
+}
+
+func (s *DBStore) getItem(ctx context.Context, id uint, fields []string) (*Item, error) {
 	var item Item
 
-	err := s.DB.WithContext(ctx).First(&item, id).Error
+	columns, labels, err := itemColumns(fields)
+	if err != nil {
+		return &item, err
+	}
+
+	err = s.tenantRead(ctx, func(tx *gorm.DB, namespace string) error {
+		if err := tx.Select(columns).Where("namespace = ?", namespace).First(&item, id).Error; err != nil {
+			return err
+		}
+
+		if !labels {
+			return nil
+		}
+
+		return loadLabels(tx, &item)
+	})
 
 	return &item, err
 }
 
-func (s *DBStore) GetItems(ctx context.Context) ([]Item, error) {
+// ItemFilter restricts the items returned by GetItems. The zero value
+// matches every item.
+type ItemFilter struct {
+	Labels     Selector
+	Attributes []AttributeFilter
+
+	// Fields restricts the fields loaded for each item, besides its ID. Nil
+	// loads every field.
+	Fields []string
+
+	// AfterID and Limit page through the items in ID order: Limit items with
+	// IDs above AfterID. A zero Limit returns every item.
+	AfterID uint
+	Limit   int
+}
+
+func (s *DBStore) GetItems(ctx context.Context, filter ItemFilter) ([]Item, error) {
+	if s.cache != nil && !consistentRead(ctx) {
+		return s.cachedItems(ctx, filter)
+	}
+
+	return s.getItems(ctx, filter)
+}
+
+func (s *DBStore) getItems(ctx context.Context, filter ItemFilter) ([]Item, error) {
 	var items []Item
-	err := s.DB.WithContext(ctx).Find(&items).Error
+
+	columns, labels, err := itemColumns(filter.Fields)
+	if err != nil {
+		return items, err
+	}
+
+	err = s.tenantRead(ctx, func(tx *gorm.DB, namespace string) error {
+		query := applyAttributeFilters(filter.Labels.apply(tx.Select(columns)), filter.Attributes)
+
+		if filter.AfterID > 0 {
+			query = query.Where("items.id > ?", filter.AfterID)
+		}
+
+		if filter.Limit > 0 {
+			query = query.Order("items.id").Limit(filter.Limit)
+		}
+
+		if err := query.Where("items.namespace = ?", namespace).Find(&items).Error; err != nil {
+			return err
+		}
+
+		if !labels {
+			return nil
+		}
+
+		return loadLabels(tx, itemPointers(items)...)
+	})
 
 	return items, err
 }
 
-func (s *DBStore) CreateItem(ctx context.Context, name, description string) (uint, error) {
-	item := Item{
-		Name:        name,
-		Description: description,
-	}
+// CreateItem inserts item into the namespace on ctx along with its labels and
+// returns the new ID.
+func (s *DBStore) CreateItem(ctx context.Context, item *Item) (uint, error) {
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		item.Namespace = namespace
+
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+
+		if err := replaceLabels(tx, *item); err != nil {
+			return err
+		}
 
-	err := s.DB.WithContext(ctx).Create(&item).Error
+		return recordEvents(tx, EventAdded, *item)
+	})
+
+	if err == nil {
+		s.afterCommit(ctx, *item)
+	}
 
 	return item.ID, err
 }
+
+// UpdateItem overwrites the given fields of the existing item with update's
+// ID, or all of UpdatableFields if none are given, and returns the updated
+// row. Only the columns of those fields are written. It returns
+// gorm.ErrRecordNotFound if no such item exists in the namespace on ctx.
+func (s *DBStore) UpdateItem(ctx context.Context, update *Item, fields ...string) (*Item, error) {
+	return s.UpdateItemChecked(ctx, update, nil, fields...)
+}
+
+// UpdateItemChecked is UpdateItem, calling check, if set, with the updated
+// item and the kind it names, nil if it names none or an unknown one, before
+// writing it. Both are read in the transaction that writes the item, so
+// check sees what the write applies to; an error from check aborts it.
+func (s *DBStore) UpdateItemChecked(ctx context.Context, update *Item, check func(item *Item, kind *ItemKind) error, fields ...string) (*Item, error) {
+	var item Item
+
+	if len(fields) == 0 {
+		fields = UpdatableFields
+	}
+
+	columns := []string{"updated_at"}
+	updateLabels := false
+
+	for _, field := range fields {
+		switch {
+		case field == FieldLabels:
+			updateLabels = true
+		case slices.Contains(UpdatableFields, field):
+			columns = append(columns, field)
+		default:
+			return nil, fmt.Errorf("item field %q can't be updated", field)
+		}
+	}
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		// the whole item is read anyway, for the change log
+		if err := tx.Where("namespace = ?", namespace).First(&item, update.ID).Error; err != nil {
+			return err
+		}
+
+		if err := loadLabels(tx, &item); err != nil {
+			return err
+		}
+
+		item.CopyFields(update, fields...)
+
+		if check != nil {
+			kind, err := itemKind(tx, namespace, item.Kind)
+			if err != nil {
+				return err
+			}
+
+			if err := check(&item, kind); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&item).Select(columns).Updates(&item).Error; err != nil {
+			return err
+		}
+
+		if updateLabels {
+			if err := replaceLabels(tx, item); err != nil {
+				return err
+			}
+		}
+
+		return recordEvents(tx, EventModified, item)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterCommit(ctx, item)
+
+	return &item, nil
+}
+
+// DeleteItem soft-deletes an item. It returns gorm.ErrRecordNotFound if no
+// such item exists in the namespace on ctx.
+func (s *DBStore) DeleteItem(ctx context.Context, id uint) error {
+	var item Item
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		if err := tx.Where("namespace = ?", namespace).First(&item, id).Error; err != nil {
+			return err
+		}
+
+		if err := loadLabels(tx, &item); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+
+		return recordEvents(tx, EventDeleted, item)
+	})
+
+	if err == nil {
+		s.afterCommit(ctx, item)
+	}
+
+	return err
+}
+
+// UpsertItems writes items into the namespace on ctx in a single transaction.
+// Items without an ID are created; items that carry an ID overwrite the
+// existing row with that ID, so replaying the same batch is idempotent. It
+// returns ErrNamespaceConflict if an ID belongs to another namespace.
+func (s *DBStore) UpsertItems(ctx context.Context, items []Item) error {
+	return s.upsertItems(ctx, items, nil)
+}
+
+// upsertItems is UpsertItems, running before, if set, first in the same
+// transaction.
+func (s *DBStore) upsertItems(ctx context.Context, items []Item, before func(tx *gorm.DB, namespace string) error) error {
+	var created, upserted []Item
+
+	for _, item := range items {
+		item.Namespace = tenancy.FromContext(ctx)
+
+		if item.ID == 0 {
+			created = append(created, item)
+		} else {
+			upserted = append(upserted, item)
+		}
+	}
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		if before != nil {
+			if err := before(tx, namespace); err != nil {
+				return err
+			}
+		}
+
+		if len(upserted) > 0 {
+			ids := make([]uint, 0, len(upserted))
+			maxID := uint(0)
+			for _, item := range upserted {
+				ids = append(ids, item.ID)
+				maxID = max(maxID, item.ID)
+			}
+
+			// the upsert below would otherwise overwrite another tenant's row;
+			// on Postgres those rows are hidden, and the policy rejects the
+			// write instead
+			var foreign int64
+			err := tx.Model(&Item{}).Unscoped().Where("id IN ? AND namespace <> ?", ids, namespace).Count(&foreign).Error
+			if err != nil {
+				return err
+			}
+
+			if foreign > 0 {
+				return ErrNamespaceConflict
+			}
+
+			var existing []uint
+			err = tx.Model(&Item{}).Where("id IN ? AND namespace = ?", ids, namespace).Pluck("id", &existing).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "description", "kind", "attributes", "updated_at", "deleted_at"}),
+			}).Create(&upserted).Error
+
+			if err != nil {
+				return err
+			}
+
+			// explicit IDs don't advance the Postgres sequence, so later inserts
+			// would collide; MAX(id) would only see this namespace, so move the
+			// sequence forward without ever moving it back
+			if tx.Dialector.Name() == "postgres" {
+				err := tx.Exec("SELECT setval(seq::regclass, GREATEST(?, nextval(seq::regclass))) FROM pg_get_serial_sequence('items', 'id') AS seq", maxID).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			isExisting := make(map[uint]bool, len(existing))
+			for _, id := range existing {
+				isExisting[id] = true
+			}
+
+			var added, modified []Item
+			for _, item := range upserted {
+				if isExisting[item.ID] {
+					modified = append(modified, item)
+				} else {
+					added = append(added, item)
+				}
+			}
+
+			if err := replaceLabels(tx, upserted...); err != nil {
+				return err
+			}
+
+			if err := recordEvents(tx, EventAdded, added...); err != nil {
+				return err
+			}
+
+			if err := recordEvents(tx, EventModified, modified...); err != nil {
+				return err
+			}
+		}
+
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+
+			if err := replaceLabels(tx, created...); err != nil {
+				return err
+			}
+
+			return recordEvents(tx, EventAdded, created...)
+		}
+
+		return nil
+	})
+
+	if err == nil {
+		s.afterCommit(ctx, append(upserted, created...)...)
+	}
+
+	return err
+}
+
+// StreamItems calls fn for every item in the namespace on ctx, in ID order.
+// Items are fetched batchSize rows at a time using keyset pagination, so the
+// full table is never held in memory.
+func (s *DBStore) StreamItems(ctx context.Context, batchSize int, fn func(*Item) error) error {
+	namespace := tenancy.FromContext(ctx)
+	var lastID uint
+
+	for {
+		var batch []Item
+
+		err := s.readTransaction(ctx, namespace, func(tx *gorm.DB) error {
+			err := tx.Where("namespace = ? AND id > ?", namespace, lastID).Order("id").Limit(batchSize).Find(&batch).Error
+			if err != nil {
+				return err
+			}
+
+			return loadLabels(tx, itemPointers(batch)...)
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		lastID = batch[len(batch)-1].ID
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestUpdateItemChecked(t *testing.T) {
+	for name, dbStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			if err := dbStore.PutItemKind(ctx, &ItemKind{Name: "laptop", Schema: `{"type": "object"}`}); err != nil {
+				t.Fatal(err)
+			}
+
+			id, err := dbStore.CreateItem(ctx, &Item{Name: "Laptop", Kind: "laptop", Attributes: Attributes{"ram_gb": 16.0}, Labels: Labels{"env": "prod"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// check sees the stored row with the masked fields applied, and
+			// its kind
+			var checked Item
+			var checkedKind *ItemKind
+			check := func(item *Item, kind *ItemKind) error {
+				checked, checkedKind = *item, kind
+				return nil
+			}
+
+			updated, err := dbStore.UpdateItemChecked(ctx, &Item{Model: gorm.Model{ID: id}, Name: "Notebook"}, check, FieldName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if checked.Name != "Notebook" || checked.Kind != "laptop" || checked.Attributes["ram_gb"] != 16.0 || checked.Labels["env"] != "prod" {
+				t.Errorf("checked %+v, want the merged item", checked)
+			}
+			if checkedKind == nil || checkedKind.Name != "laptop" {
+				t.Errorf("checked kind %+v, want laptop", checkedKind)
+			}
+			if updated.Name != "Notebook" {
+				t.Errorf("updated name = %q", updated.Name)
+			}
+
+			// an unknown kind is passed as nil
+			if _, err := dbStore.UpdateItemChecked(ctx, &Item{Model: gorm.Model{ID: id}, Kind: "phone"}, check, FieldKind); err != nil {
+				t.Fatal(err)
+			}
+			if checkedKind != nil {
+				t.Errorf("checked kind %+v for an unknown kind, want nil", checkedKind)
+			}
+
+			// an error from check aborts the update
+			rejected := errors.New("rejected")
+			_, err = dbStore.UpdateItemChecked(ctx, &Item{Model: gorm.Model{ID: id}, Name: "Tablet"}, func(*Item, *ItemKind) error { return rejected }, FieldName)
+			if !errors.Is(err, rejected) {
+				t.Fatalf("UpdateItemChecked() error = %v, want %v", err, rejected)
+			}
+
+			item, err := dbStore.GetItem(ctx, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if item.Name != "Notebook" {
+				t.Errorf("name = %q after a rejected update, want Notebook", item.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/tenancy"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	watchBatchSize = 500
+
+	// watchPollInterval bounds how long a watcher can miss a change if a
+	// notification is lost, e.g. while the LISTEN connection reconnects.
+	watchPollInterval = 5 * time.Second
+
+	listenRetryInterval = time.Second
+)
+
+// ErrResourceVersionTooOld is returned when a watch resumes from a resource
+// version whose events have already been compacted. Clients should re-list.
+var ErrResourceVersionTooOld = errors.New("resource version too old")
+
+// broker fans out change notifications to in-process watchers. Notifications
+// carry no payload; watchers re-read the change log when woken.
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subscribers: make(map[chan struct{}]struct{})}
+}
+
+func (b *broker) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+func (b *broker) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// startListening starts listen, unless it has already been started, until
+// Close. The listener keeps the logger of ctx, but outlives it.
+func (s *DBStore) startListening(ctx context.Context) {
+	s.listening.Do(func() {
+		ctx, s.stopListening = context.WithCancel(context.WithoutCancel(ctx))
+
+		s.listener.Add(1)
+		go func() {
+			defer s.listener.Done()
+			s.listen(ctx)
+		}()
+	})
+}
+
+// listen forwards Postgres notifications to the local broker, reconnecting
+// until ctx is done.
+func (s *DBStore) listen(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.listenOnce(ctx); err != nil {
+			logging.FromContext(ctx).Warn("item change listener disconnected", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+func (s *DBStore) listenOnce(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, s.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+changesChannel); err != nil {
+		return err
+	}
+
+	// changes may have been committed while we were disconnected
+	s.changes.notify()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+
+		s.changes.notify()
+	}
+}
+
+// WatchItems calls fn for every change in the namespace on ctx committed after
+// fromVersion, in order, until ctx is done or fn returns an error. A zero fromVersion first replays
+// the current items as ADDED events, all stamped with the latest resource
+// version, and then follows changes from there.
+func (s *DBStore) WatchItems(ctx context.Context, fromVersion uint64, fn func(*ItemEvent) error) error {
+	if s.dsn != "" {
+		s.startListening(ctx)
+	}
+
+	wake, unsubscribe := s.changes.subscribe()
+	defer unsubscribe()
+
+	var bounds struct {
+		Oldest uint64
+		Latest uint64
+	}
+
+	// resource versions are shared by all namespaces, and compaction is too,
+	// so only the global bounds tell whether events were compacted
+	err := s.readTransaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		return tx.Model(&ItemEvent{}).Select("COALESCE(MIN(id), 0) AS oldest, COALESCE(MAX(id), 0) AS latest").Scan(&bounds).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// IDs are signed in the database, so no event comes after a larger
+	// version; keep the query valid rather than failing it
+	fromVersion = min(fromVersion, math.MaxInt64)
+
+	if fromVersion == 0 {
+		err := s.StreamItems(ctx, watchBatchSize, func(item *Item) error {
+			return fn(&ItemEvent{ID: bounds.Latest, Type: EventAdded, ItemID: item.ID, Object: *item})
+		})
+		if err != nil {
+			return err
+		}
+
+		fromVersion = bounds.Latest
+	} else if fromVersion+1 < bounds.Oldest {
+		return ErrResourceVersionTooOld
+	}
+
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+
+	namespace := tenancy.FromContext(ctx)
+
+	for {
+		var events []ItemEvent
+
+		err := s.readTransaction(ctx, namespace, func(tx *gorm.DB) error {
+			return tx.Where("namespace = ? AND id > ?", namespace, fromVersion).Order("id").Limit(watchBatchSize).Find(&events).Error
+		})
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			if err := fn(&events[i]); err != nil {
+				return err
+			}
+			fromVersion = events[i].ID
+		}
+
+		if len(events) == watchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		case <-poll.C:
+		}
+	}
+}
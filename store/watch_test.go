@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"go.uber.org/zap"
+)
+
+// TestCloseStopsListener checks that Close stops the change listener, which
+// otherwise reconnects forever, and that none starts once the store is
+// closed.
+func TestCloseStopsListener(t *testing.T) {
+	dbStore, err := NewSQLiteBackedStore(SQLiteConfig{Path: InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing listens there, so the listener retries until stopped
+	dbStore.dsn = "postgres://127.0.0.1:1/items?connect_timeout=1"
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+	dbStore.startListening(ctx)
+
+	closed := make(chan error, 1)
+	go func() { closed <- dbStore.Close() }()
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close didn't return, waiting for the listener")
+	}
+
+	unused, err := NewSQLiteBackedStore(SQLiteConfig{Path: InMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused.dsn = dbStore.dsn
+
+	if err := unused.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	unused.startListening(ctx)
+	if unused.stopListening != nil {
+		t.Error("a listener started after Close")
+	}
+}
@@ -0,0 +1,272 @@
+package store
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxMessage is an item change waiting to be fanned out to webhooks. It is
+// written in the same transaction as the change itself, so an event is
+// published if and only if the mutation commits.
+type OutboxMessage struct {
+	ID           uint64    `gorm:"primaryKey"`
+	EventID      uint64    `gorm:"index"`
+	Namespace    string    `gorm:"size:63;not null;default:'default'"`
+	Type         EventType `gorm:"size:16"`
+	ItemID       uint
+	Object       Item `gorm:"serializer:json"`
+	CreatedAt    time.Time
+	DispatchedAt *time.Time `gorm:"index"`
+}
+
+// Webhook subscribes to the item changes of the namespace it was created in.
+type Webhook struct {
+	gorm.Model
+
+	Namespace string `gorm:"size:63;not null;default:'default';index"`
+
+	URL    string
+	Secret string
+	// EventTypes is a comma separated list of the event types to deliver.
+	// An empty list subscribes to every event.
+	EventTypes string
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryDead      DeliveryStatus = "DEAD"
+)
+
+// WebhookDelivery tracks delivery of one outbox message to one webhook.
+type WebhookDelivery struct {
+	ID              uint64 `gorm:"primaryKey"`
+	WebhookID       uint   `gorm:"index"`
+	Webhook         Webhook
+	OutboxMessageID uint64
+	OutboxMessage   OutboxMessage
+	Status          DeliveryStatus `gorm:"size:16;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts        uint
+	LastError       string
+	NextAttemptAt   time.Time `gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	DeliveredAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (s *DBStore) CreateWebhook(ctx context.Context, webhook *Webhook) error {
+	return s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		webhook.Namespace = namespace
+		return tx.Create(webhook).Error
+	})
+}
+
+func (s *DBStore) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		return tx.Where("namespace = ?", namespace).Order("id").Find(&webhooks).Error
+	})
+
+	return webhooks, err
+}
+
+// ownWebhooks selects the IDs of the webhooks in namespace, to scope queries
+// on deliveries, which have no namespace of their own.
+func ownWebhooks(tx *gorm.DB, namespace string) *gorm.DB {
+	return tx.Model(&Webhook{}).Select("id").Where("namespace = ?", namespace)
+}
+
+// DeleteWebhook removes a webhook and dead-letters its pending deliveries. It
+// returns gorm.ErrRecordNotFound if no such webhook exists in the namespace on
+// ctx.
+func (s *DBStore) DeleteWebhook(ctx context.Context, id uint) error {
+	return s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		res := tx.Where("namespace = ?", namespace).Delete(&Webhook{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&WebhookDelivery{}).
+			Where("webhook_id = ? AND status = ?", id, DeliveryPending).
+			Updates(map[string]interface{}{"status": DeliveryDead, "last_error": "webhook deleted"}).Error
+	})
+}
+
+// GetWebhookDeliveries lists the deliveries for a webhook in the namespace on
+// ctx, newest first, optionally filtered by status.
+func (s *DBStore) GetWebhookDeliveries(ctx context.Context, webhookID uint, status DeliveryStatus, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+
+	err := s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		query := tx.Preload("OutboxMessage").Where("webhook_id = ? AND webhook_id IN (?)", webhookID, ownWebhooks(tx, namespace))
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+
+		return query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	})
+
+	return deliveries, err
+}
+
+// RedeliverWebhookDelivery resets a delivery, typically a dead-lettered one, so
+// the dispatcher retries it from scratch. It returns gorm.ErrRecordNotFound if
+// no such delivery exists in the namespace on ctx.
+func (s *DBStore) RedeliverWebhookDelivery(ctx context.Context, id uint64) error {
+	// IDs are signed in the database, and SQLite rejects larger arguments
+	if id > math.MaxInt64 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return s.tenant(ctx, func(tx *gorm.DB, namespace string) error {
+		res := tx.Model(&WebhookDelivery{}).Where("id = ? AND webhook_id IN (?)", id, ownWebhooks(tx, namespace)).Updates(map[string]interface{}{
+			"status":          DeliveryPending,
+			"attempts":        0,
+			"last_error":      "",
+			"next_attempt_at": time.Now(),
+			"delivered_at":    nil,
+		})
+
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return res.Error
+	})
+}
+
+// DispatchOutbox fans up to limit undispatched outbox messages of every
+// namespace out into one pending delivery per subscribed webhook in the
+// message's namespace, and returns how many messages it dispatched.
+func (s *DBStore) DispatchOutbox(ctx context.Context, limit int) (int, error) {
+	var dispatched int
+
+	err := s.transaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		var messages []OutboxMessage
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL").Order("id").Limit(limit).Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		var webhooks []Webhook
+		if err := tx.Find(&webhooks).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var deliveries []WebhookDelivery
+		ids := make([]uint64, 0, len(messages))
+
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+
+			for _, webhook := range webhooks {
+				if webhook.Namespace == message.Namespace && webhook.Subscribes(message.Type) {
+					deliveries = append(deliveries, WebhookDelivery{
+						WebhookID:       webhook.ID,
+						OutboxMessageID: message.ID,
+						Status:          DeliveryPending,
+						NextAttemptAt:   now,
+					})
+				}
+			}
+		}
+
+		if len(deliveries) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&deliveries).Error; err != nil {
+				return err
+			}
+		}
+
+		dispatched = len(messages)
+
+		return tx.Model(&OutboxMessage{}).Where("id IN ?", ids).Update("dispatched_at", now).Error
+	})
+
+	return dispatched, err
+}
+
+// ClaimWebhookDeliveries returns up to limit deliveries that are due and
+// pushes their next attempt out by lease, so concurrent dispatchers don't
+// pick them up while they are in flight.
+func (s *DBStore) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+
+	err := s.transaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		now := time.Now()
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryPending, now).
+			Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+
+		err = tx.Model(&WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Preload("Webhook").Preload("OutboxMessage").Where("id IN ?", ids).Find(&deliveries).Error
+	})
+
+	return deliveries, err
+}
+
+// SaveWebhookDeliveryAttempt records the outcome of a delivery attempt.
+func (s *DBStore) SaveWebhookDeliveryAttempt(ctx context.Context, delivery *WebhookDelivery) error {
+	return s.transaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		return tx.Model(delivery).
+			Select("status", "attempts", "last_error", "next_attempt_at", "delivered_at").
+			Updates(delivery).Error
+	})
+}
+
+// CompactWebhookDeliveries deletes delivered deliveries created before the
+// given time, along with outbox messages that have nothing left to deliver.
+func (s *DBStore) CompactWebhookDeliveries(ctx context.Context, before time.Time) error {
+	return s.transaction(ctx, allNamespaces, func(tx *gorm.DB) error {
+		err := tx.Where("status = ? AND created_at < ?", DeliveryDelivered, before).Delete(&WebhookDelivery{}).Error
+		if err != nil {
+			return err
+		}
+
+		remaining := tx.Model(&WebhookDelivery{}).Select("outbox_message_id")
+
+		return tx.Where("dispatched_at < ? AND id NOT IN (?)", before, remaining).Delete(&OutboxMessage{}).Error
+	})
+}
+
+// Subscribes reports whether the webhook wants events of the given type.
+func (w *Webhook) Subscribes(eventType EventType) bool {
+	if w.EventTypes == "" {
+		return true
+	}
+
+	for _, t := range strings.Split(w.EventTypes, ",") {
+		if EventType(t) == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+// Package tenancy carries the namespace (tenant) a request acts in. Every
+// store query is scoped to the namespace on its context.
+package tenancy
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultNamespace is used when a request names no namespace.
+	DefaultNamespace = "default"
+
+	// Header selects the namespace of an unauthenticated request. The
+	// gateway forwards it as gRPC metadata.
+	Header = "X-Namespace"
+)
+
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// prevent collisions with other packages
+type key int
+
+var namespaceKey key = 0
+
+// Validate checks that namespace is an RFC 1123 label, like a Kubernetes
+// namespace name.
+func Validate(namespace string) error {
+	if !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("invalid namespace %q: must be a lowercase RFC 1123 label of at most 63 characters", namespace)
+	}
+
+	return nil
+}
+
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey, namespace)
+}
+
+// FromContext returns the namespace on ctx, or DefaultNamespace if there is
+// none.
+func FromContext(ctx context.Context) string {
+	if namespace, ok := ctx.Value(namespaceKey).(string); ok {
+		return namespace
+	}
+
+	return DefaultNamespace
+}
+
+// ResourceName returns the resource name of an item, such as
+// "namespaces/default/items/1".
+func ResourceName(namespace string, itemID uint64) string {
+	return fmt.Sprintf("namespaces/%s/items/%d", namespace, itemID)
+}
+
+// Authentication configures how the namespace of an incoming call is
+// authenticated. Its zero value trusts the namespace header of callers
+// without a client certificate.
+type Authentication struct {
+	// RequirePrincipal rejects calls without a verified TLS client
+	// certificate, so that the header alone never selects a namespace.
+	RequirePrincipal bool
+	// Forwarder is the DER certificate of a proxy, the gateway, that
+	// authenticates its own clients and passes their namespace on in the
+	// header, which is then trusted.
+	Forwarder []byte
+}
+
+// CertificateNamespace returns the namespace a client certificate pins its
+// holder to: the first Organization of its subject.
+func CertificateNamespace(cert *x509.Certificate) (string, bool) {
+	if len(cert.Subject.Organization) == 0 {
+		return "", false
+	}
+
+	return cert.Subject.Organization[0], true
+}
+
+// principal returns the verified TLS client certificate of a call.
+func principal(ctx context.Context) (*x509.Certificate, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	return tlsInfo.State.VerifiedChains[0][0], true
+}
+
+// resolve determines the namespace of an incoming call. An authenticated
+// principal decides its own namespace and may only repeat it in the header,
+// unless it's the forwarder, which sets the header for its clients;
+// anonymous callers choose one with the header, unless auth requires a
+// principal.
+func (auth Authentication) resolve(ctx context.Context) (context.Context, error) {
+	var requested string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(Header); len(values) > 0 {
+			requested = values[0]
+		}
+	}
+
+	namespace := requested
+	cert, ok := principal(ctx)
+	switch {
+	case ok && auth.Forwarder != nil && bytes.Equal(cert.Raw, auth.Forwarder):
+		// the forwarder set the header, or made the call itself, e.g. a
+		// health check
+
+	case ok:
+		certNamespace, ok := CertificateNamespace(cert)
+		if !ok {
+			return ctx, status.Error(codes.Unauthenticated, "client certificate names no namespace in its subject's Organization")
+		}
+		if requested != "" && requested != certNamespace {
+			return ctx, status.Errorf(codes.PermissionDenied, "principal may not act in namespace %q", requested)
+		}
+		namespace = certNamespace
+
+	case auth.RequirePrincipal:
+		return ctx, status.Error(codes.Unauthenticated, "a TLS client certificate is required")
+	}
+
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	if err := Validate(namespace); err != nil {
+		return ctx, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return WithNamespace(ctx, namespace), nil
+}
+
+func UnaryServerInterceptor(auth Authentication) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := auth.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(auth Authentication) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := auth.resolve(stream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &namespacedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type namespacedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *namespacedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,175 @@
+// Package testutil runs the whole server in process for tests: the gRPC
+// server, the REST gateway and the metrics server, each on an ephemeral
+// port, over an in-memory SQLite store. Unlike the server binary, it doesn't
+// bind the fixed ports, so tests and test packages can run in parallel.
+package testutil
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/skip-mev/platform-take-home/api/server"
+	"github.com/skip-mev/platform-take-home/client"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/observability/metrics"
+	"github.com/skip-mev/platform-take-home/recording"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+// Config configures a Server. The zero value serves a fresh in-memory SQLite
+// store.
+type Config struct {
+	// Store is served instead of a fresh in-memory SQLite store. It is
+	// migrated, and left open when the server stops.
+	Store *store.DBStore
+	// Recorder records sampled requests if set.
+	Recorder *recording.Recorder
+	// Logger receives the server's logs, which are discarded by default.
+	Logger *zap.Logger
+	// TLS secures the gRPC server and the gateway, and ClientTLS the
+	// server's clients, which are plaintext if it's unset.
+	TLS       server.TLSConfig
+	ClientTLS *tls.Config
+}
+
+// Server is a running server along with clients of it.
+type Server struct {
+	// Client calls the gRPC server at GRPCAddress.
+	Client      *client.Client
+	GRPCAddress string
+
+	// HTTP calls the gateway at URL and the metrics server at MetricsURL,
+	// e.g. http://127.0.0.1:41234, or https:// for a gateway with TLS.
+	HTTP       *http.Client
+	URL        string
+	MetricsURL string
+
+	Store *store.DBStore
+}
+
+// Start starts a server, returning once it serves, and stops it when the
+// test and its subtests have completed, failing the test if it stopped with
+// an error. The server is stopped gracefully, so streams the test leaves
+// open delay its end until the clients are closed.
+func Start(t testing.TB, config Config) *Server {
+	t.Helper()
+
+	dbStore := config.Store
+	if dbStore == nil {
+		var err error
+		if dbStore, err = store.NewSQLiteBackedStore(store.SQLiteConfig{Path: store.InMemory}); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			if err := dbStore.Close(); err != nil {
+				t.Errorf("error closing store: %v", err)
+			}
+		})
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	// not the global provider, which other servers of the test process
+	// would share
+	meterProvider, err := metrics.NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grpcListener := listen(t)
+	gatewayListener := listen(t)
+	metricsListener := listen(t)
+
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logger))
+	eg, ctx := errgroup.WithContext(ctx)
+
+	grpcServer, err := server.NewServer(logger, config.Recorder, config.TLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eg.Go(func() error {
+		return grpcServer.Serve(ctx, grpcListener, dbStore)
+	})
+
+	eg.Go(func() error {
+		return server.ServeGRPCGateway(ctx, gatewayListener, grpcListener.Addr().String(), config.Recorder, config.TLS)
+	})
+
+	eg.Go(func() error {
+		return metrics.Serve(ctx, metricsListener, meterProvider)
+	})
+
+	t.Cleanup(func() {
+		cancel()
+		if err := eg.Wait(); err != nil && !stoppedCleanly(err) {
+			t.Errorf("error serving: %v", err)
+		}
+	})
+
+	// wait until the store is migrated and the server accepts connections
+	select {
+	case <-grpcServer.Serving():
+	case <-ctx.Done():
+		// one of the servers failed, which Cleanup reports
+		t.FailNow()
+	}
+
+	clientConfig := client.Config{Address: grpcListener.Addr().String(), TLS: config.ClientTLS, Insecure: config.ClientTLS == nil}
+	c, err := client.New(clientConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: config.ClientTLS}}
+
+	scheme := "http://"
+	if config.ClientTLS != nil {
+		scheme = "https://"
+	}
+
+	// stop the clients first, so that no stream holds up the server
+	t.Cleanup(func() {
+		httpClient.CloseIdleConnections()
+		if err := c.Close(); err != nil {
+			t.Errorf("error closing client: %v", err)
+		}
+	})
+
+	return &Server{
+		Client:      c,
+		GRPCAddress: grpcListener.Addr().String(),
+		HTTP:        httpClient,
+		URL:         scheme + gatewayListener.Addr().String(),
+		MetricsURL:  "http://" + metricsListener.Addr().String(),
+		Store:       dbStore,
+	}
+}
+
+// stoppedCleanly reports whether err is how a server stopped by Cleanup
+// may fail, racing its own startup.
+func stoppedCleanly(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, grpc.ErrServerStopped)
+}
+
+func listen(t testing.TB) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return listener
+}
@@ -0,0 +1,219 @@
+// Package webhook delivers item events from the transactional outbox to
+// registered webhook endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/service"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	pollInterval    = time.Second
+	batchSize       = 100
+	concurrency     = 8
+	requestTimeout  = 10 * time.Second
+	maxAttempts     = 12
+	baseBackoff     = 5 * time.Second
+	maxBackoff      = time.Hour
+	compactInterval = time.Hour
+	retention       = 7 * 24 * time.Hour
+	maxErrorBody    = 512
+)
+
+// Dispatcher moves outbox messages into per-webhook deliveries and delivers
+// them. Failed attempts are retried with exponential backoff until
+// maxAttempts, after which the delivery is dead-lettered until redelivered.
+// Delivery is at-least-once, so receivers should deduplicate on the event ID.
+type Dispatcher struct {
+	store  *store.DBStore
+	client *http.Client
+}
+
+// NewDispatcher creates a dispatcher delivering to the addresses config
+// allows, which it checks as it connects, after resolving the webhook's
+// host, and again on every redirect.
+func NewDispatcher(dbStore *store.DBStore, config service.WebhookConfig) *Dispatcher {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			return config.CheckAddress(addrPort.Addr())
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// through a proxy the dialer would only see the proxy's address
+	transport.Proxy = nil
+
+	return &Dispatcher{
+		store:  dbStore,
+		client: &http.Client{Timeout: requestTimeout, Transport: transport},
+	}
+}
+
+// Run dispatches and delivers events until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastCompaction := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := d.tick(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("error dispatching webhooks", zap.Error(err))
+		}
+
+		if time.Since(lastCompaction) > compactInterval {
+			lastCompaction = time.Now()
+
+			if err := d.store.CompactWebhookDeliveries(ctx, time.Now().Add(-retention)); err != nil {
+				logging.FromContext(ctx).Error("error compacting webhook deliveries", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) tick(ctx context.Context) error {
+	for {
+		n, err := d.store.DispatchOutbox(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+
+		if n < batchSize {
+			break
+		}
+	}
+
+	for {
+		deliveries, err := d.store.ClaimWebhookDeliveries(ctx, batchSize, requestTimeout*2)
+		if err != nil {
+			return err
+		}
+
+		// each delivery is attempted and saved on its own: one failing
+		// doesn't cancel the others, whose attempts must still be saved
+		var eg errgroup.Group
+		eg.SetLimit(concurrency)
+
+		for i := range deliveries {
+			delivery := &deliveries[i]
+			eg.Go(func() error {
+				d.attempt(ctx, delivery)
+				return d.store.SaveWebhookDeliveryAttempt(ctx, delivery)
+			})
+		}
+
+		if err := eg.Wait(); err != nil {
+			return err
+		}
+
+		if len(deliveries) < batchSize {
+			return nil
+		}
+	}
+}
+
+// attempt sends delivery once and updates it with the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *store.WebhookDelivery) {
+	delivery.Attempts++
+
+	err := d.send(ctx, delivery)
+	if err == nil {
+		now := time.Now()
+		delivery.Status = store.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+
+	if delivery.Webhook.ID == 0 || delivery.Attempts >= maxAttempts {
+		delivery.Status = store.DeliveryDead
+		logging.FromContext(ctx).Warn("webhook delivery dead-lettered",
+			zap.Uint64("delivery", delivery.ID),
+			zap.Uint("webhook", delivery.WebhookID),
+			zap.Uint("attempts", delivery.Attempts),
+			zap.Error(err))
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts))
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *store.WebhookDelivery) error {
+	if delivery.Webhook.ID == 0 {
+		return fmt.Errorf("webhook deleted")
+	}
+
+	event := service.WebhookEventToAPI(&delivery.OutboxMessage)
+
+	body, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "platform-take-home-webhooks/1")
+	req.Header.Set(EventIDHeader, strconv.FormatUint(event.Id, 10))
+	req.Header.Set(EventTypeHeader, event.Type.String())
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+}
+
+// backoff doubles the delay after every failed attempt, capped at maxBackoff,
+// with ±20% jitter so failing endpoints aren't retried in lockstep.
+func backoff(attempts uint) time.Duration {
+	delay := maxBackoff
+	if attempts < 20 {
+		delay = min(baseBackoff<<(attempts-1), maxBackoff)
+	}
+
+	jitter := 0.8 + 0.4*rand.Float64()
+	return time.Duration(float64(delay) * jitter)
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skip-mev/platform-take-home/api/service"
+	"github.com/skip-mev/platform-take-home/observability/logging"
+	"github.com/skip-mev/platform-take-home/store"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TestAttempt checks the states a delivery moves through: delivered on a 2xx,
+// retried after a backoff on a failure, and dead-lettered after maxAttempts
+// or once its webhook is deleted.
+func TestAttempt(t *testing.T) {
+	var fail bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := Verify("secret", r.Header.Get(SignatureHeader), readAll(t, r), time.Minute); err != nil {
+			t.Errorf("signature: %v", err)
+		}
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	d := NewDispatcher(nil, service.WebhookConfig{AllowLocal: true})
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+
+	newDelivery := func(attempts uint) *store.WebhookDelivery {
+		return &store.WebhookDelivery{
+			ID:            1,
+			WebhookID:     1,
+			Webhook:       store.Webhook{Model: gorm.Model{ID: 1}, URL: receiver.URL, Secret: "secret"},
+			OutboxMessage: store.OutboxMessage{ID: 1, Type: store.EventAdded},
+			Status:        store.DeliveryPending,
+			Attempts:      attempts,
+		}
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		fail = false
+		delivery := newDelivery(3)
+		delivery.LastError = "unexpected status 503"
+		d.attempt(ctx, delivery)
+
+		if delivery.Status != store.DeliveryDelivered || delivery.DeliveredAt == nil || delivery.LastError != "" || delivery.Attempts != 4 {
+			t.Errorf("got %+v, want it delivered on the 4th attempt", delivery)
+		}
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		fail = true
+		delivery := newDelivery(0)
+		before := time.Now()
+		d.attempt(ctx, delivery)
+
+		if delivery.Status != store.DeliveryPending || delivery.Attempts != 1 || !strings.Contains(delivery.LastError, "503") {
+			t.Errorf("got %+v, want it pending after a 503", delivery)
+		}
+
+		if wait := delivery.NextAttemptAt.Sub(before); wait < baseBackoff*8/10 || wait > baseBackoff*12/10+time.Second {
+			t.Errorf("next attempt in %v, want about %v", wait, baseBackoff)
+		}
+	})
+
+	t.Run("dead-lettered", func(t *testing.T) {
+		fail = true
+		delivery := newDelivery(maxAttempts - 1)
+		d.attempt(ctx, delivery)
+
+		if delivery.Status != store.DeliveryDead || delivery.Attempts != maxAttempts {
+			t.Errorf("got %+v, want it dead after %d attempts", delivery, maxAttempts)
+		}
+	})
+
+	t.Run("webhook deleted", func(t *testing.T) {
+		fail = false
+		delivery := newDelivery(0)
+		delivery.Webhook = store.Webhook{}
+		d.attempt(ctx, delivery)
+
+		if delivery.Status != store.DeliveryDead || delivery.LastError != "webhook deleted" {
+			t.Errorf("got %+v, want it dead", delivery)
+		}
+	})
+}
+
+func TestAttemptLocalAddress(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivered to a loopback address")
+	}))
+	defer receiver.Close()
+
+	d := NewDispatcher(nil, service.WebhookConfig{})
+	ctx := logging.WithLogger(context.Background(), zap.NewNop())
+
+	// host names resolving to loopback pass CreateWebhook, but not the
+	// dialer, which sees the resolved address
+	delivery := &store.WebhookDelivery{Webhook: store.Webhook{Model: gorm.Model{ID: 1}, URL: receiver.URL}}
+	d.attempt(ctx, delivery)
+
+	if delivery.Status == store.DeliveryDelivered || !strings.Contains(delivery.LastError, "local address") {
+		t.Errorf("got %+v, want the local address refused", delivery)
+	}
+}
+
+func TestDispatcherIgnoresProxy(t *testing.T) {
+	d := NewDispatcher(nil, service.WebhookConfig{})
+
+	// a proxy would be the only address the dialer checks
+	if d.client.Transport.(*http.Transport).Proxy != nil {
+		t.Error("deliveries go through the environment's proxy")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts uint
+		want     time.Duration
+	}{
+		{1, baseBackoff},
+		{2, 2 * baseBackoff},
+		{5, 16 * baseBackoff},
+		{11, maxBackoff},
+		{100, maxBackoff},
+	} {
+		for range 20 {
+			if got := backoff(tc.attempts); got < tc.want*8/10 || got > tc.want*12/10 {
+				t.Errorf("backoff(%d) = %v, want %v ±20%%", tc.attempts, got, tc.want)
+			}
+		}
+	}
+}
+
+func readAll(t *testing.T, r *http.Request) []byte {
+	t.Helper()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Error(err)
+	}
+	return body
+}
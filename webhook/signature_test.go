@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignFormat(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	header := Sign("secret", at, []byte(`{"id":"1"}`))
+
+	if !regexp.MustCompile(`^t=1700000000,v1=[0-9a-f]{64}$`).MatchString(header) {
+		t.Errorf("got %q, want t=<unix seconds>,v1=<64 hex digits>", header)
+	}
+
+	if again := Sign("secret", at, []byte(`{"id":"1"}`)); again != header {
+		t.Errorf("signing twice: got %q and %q", header, again)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"1","type":"EVENT_TYPE_ADDED"}`)
+	now := time.Now()
+	valid := Sign("secret", now, body)
+
+	for _, tc := range []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		ok     bool
+	}{
+		{name: "round trip", secret: "secret", header: valid, body: body, ok: true},
+		{name: "fields reordered", secret: "secret", header: reorder(valid), body: body, ok: true},
+		{name: "other secret", secret: "other", header: valid, body: body},
+		{name: "tampered body", secret: "secret", header: valid, body: []byte(`{"id":"2","type":"EVENT_TYPE_ADDED"}`)},
+		{name: "too old", secret: "secret", header: Sign("secret", now.Add(-10*time.Minute), body), body: body},
+		{name: "from the future", secret: "secret", header: Sign("secret", now.Add(10*time.Minute), body), body: body},
+		{name: "timestamp changed", secret: "secret", header: "t=" + strconv.FormatInt(now.Unix()-1, 10) + valid[len("t=")+len(strconv.FormatInt(now.Unix(), 10)):], body: body},
+		{name: "empty", secret: "secret", header: "", body: body},
+		{name: "no signature", secret: "secret", header: "t=" + strconv.FormatInt(now.Unix(), 10), body: body},
+		{name: "not hex", secret: "secret", header: "t=" + strconv.FormatInt(now.Unix(), 10) + ",v1=zz", body: body},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.body, 5*time.Minute)
+			if (err == nil) != tc.ok {
+				t.Errorf("Verify(%q): got %v, want ok=%t", tc.header, err, tc.ok)
+			}
+		})
+	}
+}
+
+// reorder swaps the fields of a signature header.
+func reorder(header string) string {
+	re := regexp.MustCompile(`^(t=[^,]*),(v1=.*)$`)
+	return re.ReplaceAllString(header, "$2,$1")
+}